@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
+
 	"github.com/MrBista/blog-api/internal/config"
 	"github.com/MrBista/blog-api/internal/database"
 	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/oauth"
+	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/router"
+	"github.com/MrBista/blog-api/internal/search"
+	"github.com/MrBista/blog-api/internal/services"
 	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/MrBista/blog-api/internal/worker"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
@@ -16,17 +23,93 @@ func main() {
 	database.Connect()
 	defer database.Close()
 
+	utils.InitLogger()
+
+	// logger harus siap lebih dulu, key JWT bisa dibuat/dibersihkan saat init
 	utils.InitJwtService()
 
 	utils.GetValidator()
 
-	utils.InitLogger()
+	oauth.InitProviders(config.AppConfig.OAuth.GetProviders(&config.AppConfig.AppMain))
+
+	search.InitSearchIndex(config.AppConfig.Search.GetIndexPath())
+
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	defer stopWorker()
+
+	// dengan Prefork, main() juga jalan di setiap child process, jadi worker cukup di parent
+	if !fiber.IsChild() {
+		postRepository := repository.NewPostRepository(database.DB)
+		searchService := services.NewSearchService(search.GetSearchIndex(), postRepository)
+
+		// rekonsiliasi langsung jalan saat start, index kosong (pertama kali jalan / file terhapus) ikut dibangun ulang
+		searchReconciler := worker.NewSearchReconciler(searchService, config.AppConfig.Search.GetReconcileInterval())
+		searchReconciler.Start(workerCtx)
+
+		// setelah two_factor.encryption_key dirotasi, secret lama dienkripsi ulang supaya key lama bisa dihapus dari config
+		twoFactorService := services.NewTwoFactorService(
+			repository.NewUserTwoFactorRepository(database.DB),
+			repository.NewUserRepository(database.DB),
+			services.NewLoginGuardService(
+				services.NewLoginAttemptStore(&config.AppConfig.LoginGuard, repository.NewLoginAttemptRepository(database.DB)),
+				repository.NewSecurityLogRepository(database.DB),
+				&config.AppConfig.LoginGuard,
+			),
+			&config.AppConfig.TwoFactor,
+		)
+		if reencrypted, err := twoFactorService.ReencryptSecrets(); err != nil {
+			utils.Logger.Errorf("failed to re-encrypt two factor secrets %v", err)
+		} else if reencrypted > 0 {
+			utils.Logger.Infof("re-encrypted %d two factor secrets with key %s", reencrypted, config.AppConfig.TwoFactor.GetEncryptionKeyID())
+		}
+
+		if err := repository.NewCategoryRepository(database.DB).RecountAll(); err != nil {
+			utils.Logger.Errorf("failed to recount category counters %v", err)
+		}
+
+		publisher := worker.NewPostPublisher(
+			postRepository,
+			repository.NewPostStatusEventRepository(database.DB),
+			searchService,
+			config.AppConfig.Scheduler.GetPublishInterval(),
+		)
+		publisher.Start(workerCtx)
+
+		accountWorker := worker.NewAccountWorker(
+			repository.NewAccountRepository(database.DB),
+			repository.NewDataExportRepository(database.DB),
+			services.NewAccountService(
+				repository.NewUserRepository(database.DB),
+				repository.NewUserIdentityRepository(database.DB),
+				repository.NewAccountRepository(database.DB),
+				repository.NewDataExportRepository(database.DB),
+				repository.NewSecurityLogRepository(database.DB),
+				searchService,
+				services.NewLocalStorage("./public", "/public"),
+			),
+			config.AppConfig.Account.GetWorkerInterval(),
+		)
+		accountWorker.Start(workerCtx)
 
-	utils.InitGoogleOAuth()
+		// strategy read tidak butuh inbox, feed dihitung langsung dari tabel followers
+		if config.AppConfig.Feed.GetStrategy() == config.FeedStrategyWrite {
+			feedFanout := worker.NewFeedFanout(
+				services.NewFeedService(repository.NewFeedRepository(database.DB), &config.AppConfig.Feed),
+				config.AppConfig.Feed.GetFanoutInterval(),
+			)
+			feedFanout.Start(workerCtx)
+		}
+	}
 
 	app := fiber.New(fiber.Config{
 		ErrorHandler: middleware.HandleError,
-		Prefork:      true,
+		Prefork:      config.AppConfig.Server.IsPrefork(),
+		// di belakang reverse proxy c.IP() membaca ProxyHeader, tapi hanya dari proxy yang dipercaya.
+		// Tanpa trusted_proxies perilaku X-Forwarded-Proto untuk c.Protocol() tetap seperti sebelumnya
+		ProxyHeader:             config.AppConfig.Server.ProxyHeader,
+		EnableTrustedProxyCheck: len(config.AppConfig.Server.TrustedProxies) > 0,
+		TrustedProxies:          config.AppConfig.Server.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	app.Use(cors.New(cors.Config{
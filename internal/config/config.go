@@ -1,29 +1,56 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DB      DBConfig
-	JWT     JwtConfig
-	Xendit  XenditConfig
-	AppMain AppMain
+	Server     ServerConfig
+	DB         DBConfig
+	JWT        JwtConfig
+	Xendit     XenditConfig
+	AppMain    AppMain
+	Scheduler  SchedulerConfig
+	Search     SearchConfig
+	Comment    CommentConfig
+	Mail       MailConfig
+	Otp        OtpConfig
+	TwoFactor  TwoFactorConfig
+	OAuth      OAuthConfig
+	LoginGuard LoginGuardConfig
+	Profile    ProfileConfig
+	Account    AccountConfig
+	Feed       FeedConfig
 }
 
 type AppMain struct {
 	PORT               string
 	BaseUrl            string
 	Domain             string
+	PasswordResetUrl   string
 	GoggleClientId     string
 	GoggleClientSecret string
 	GoggleRedirectUrl  string
 }
 
+// ServerConfig pengaturan fiber.App
+type ServerConfig struct {
+	// DisablePrefork prefork aktif secara default, state in-memory tidak dibagi antar child process
+	DisablePrefork bool
+	// ProxyHeader header berisi IP client asli dari reverse proxy, misalnya X-Real-IP (nginx: proxy_set_header X-Real-IP $remote_addr).
+	// Hanya dibaca kalau request datang dari TrustedProxies, dipakai rate limit guest comment dan lockout login per IP.
+	// Hindari X-Forwarded-For kalau proxy hanya menambahkan ke belakang, karena IP paling kiri bisa diisi client sendiri.
+	ProxyHeader string
+	// TrustedProxies IP atau CIDR reverse proxy, wajib diisi kalau ProxyHeader dipakai
+	TrustedProxies []string
+}
+
 type DBConfig struct {
 	Host     string
 	Password string
@@ -33,9 +60,28 @@ type DBConfig struct {
 	SSLMode  string
 }
 
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmEdDSA = "EdDSA"
+)
+
 type JwtConfig struct {
-	SecretKey      string
-	AccessTokenExp time.Duration
+	SecretKey       string
+	AccessTokenExp  time.Duration
+	RefreshTokenExp time.Duration
+	// Algorithm HS256 memakai secret_key (mode lama), RS256/EdDSA memakai key dari KeyDir dan/atau Keys
+	Algorithm string
+	KeyDir    string
+	ActiveKid string
+	Keys      []JwtKeyConfig
+}
+
+// JwtKeyConfig key statis dari config, key tanpa private key hanya dipakai untuk verifikasi
+type JwtKeyConfig struct {
+	Kid            string `mapstructure:"kid"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
 }
 
 type XenditConfig struct {
@@ -44,6 +90,151 @@ type XenditConfig struct {
 	BaseURL    string
 }
 
+type SchedulerConfig struct {
+	PublishInterval time.Duration
+}
+
+// SearchConfig index disimpan di IndexPath (+ .log dan .lock). Kalau file index rusak, hapus ketiga file
+// lalu restart: rekonsiliasi saat startup membangun ulang index dari database.
+type SearchConfig struct {
+	IndexPath string
+	// ReconcileInterval jarak rekonsiliasi index dengan post published di database
+	ReconcileInterval time.Duration
+}
+
+const (
+	MailDriverSMTP = "smtp"
+	MailDriverLog  = "log"
+)
+
+type MailConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type OtpConfig struct {
+	Length         int
+	TTL            time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+}
+
+type TwoFactorConfig struct {
+	Issuer       string
+	RequireAdmin bool
+	PendingExp   time.Duration
+	// EncryptionKey key AES-256 (base64, 32 byte) khusus untuk secret TOTP tersimpan, sengaja tidak
+	// diturunkan dari secret JWT supaya rotasi key JWT tidak mengunci user 2FA.
+	// EncryptionKeyID disimpan di depan setiap ciphertext. Saat rotasi, key lama dipindah ke
+	// PreviousEncryptionKeys (id -> key); secret dienkripsi ulang dengan key baru saat startup.
+	EncryptionKey          string
+	EncryptionKeyID        string
+	PreviousEncryptionKeys map[string]string
+}
+
+const (
+	OAuthProviderOIDC   = "oidc"
+	OAuthProviderGitHub = "github"
+
+	googleIssuer = "https://accounts.google.com"
+)
+
+// OAuthProviderConfig satu provider login, key map di oauth.providers menjadi nama provider
+type OAuthProviderConfig struct {
+	Type         string   `mapstructure:"type"`
+	DisplayName  string   `mapstructure:"display_name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+type OAuthConfig struct {
+	Providers map[string]OAuthProviderConfig
+	StateTTL  time.Duration
+}
+
+const (
+	LoginGuardStoreMemory   = "memory"
+	LoginGuardStoreDatabase = "database"
+)
+
+// LoginGuardConfig batas percobaan login gagal per akun dan per IP
+type LoginGuardConfig struct {
+	Store              string
+	MaxAccountFailures int
+	MaxIPFailures      int
+	// MaxTwoFactorFailures batas kode 2FA salah per user, MaxMfaTokenFailures per token mfa_pending
+	MaxTwoFactorFailures int
+	MaxMfaTokenFailures  int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	LockoutDuration      time.Duration
+	FailureWindow        time.Duration
+}
+
+type ProfileConfig struct {
+	AvatarMaxSize          int64
+	AvatarSize             int
+	UsernameChangeInterval time.Duration
+	UsernameReservation    time.Duration
+}
+
+const (
+	AccountDeletionAnonymize = "anonymize"
+	AccountDeletionPurge     = "purge"
+
+	AccountPostReassign = "reassign"
+	AccountPostDelete   = "delete"
+)
+
+// AccountConfig export data pribadi dan penghapusan akun
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration
+	// DeletionMode anonymize menyisakan baris user tanpa data pribadi, purge menghapus barisnya
+	DeletionMode string
+	// PostAction reassign memindahkan post ke ReassignUserID, delete menghapus post beserta isinya
+	PostAction     string
+	ReassignUserID int64
+	ExportDir      string
+	ExportTTL      time.Duration
+	WorkerInterval time.Duration
+}
+
+const (
+	FeedStrategyRead  = "read"
+	FeedStrategyWrite = "write"
+)
+
+// FeedConfig feed "following", strategy read menghitung feed saat request, write menyalin post ke inbox follower
+type FeedConfig struct {
+	Strategy       string
+	PageSize       int
+	MaxPageSize    int
+	FanoutInterval time.Duration
+	FanoutBatch    int
+	Retention      time.Duration
+}
+
+type CommentConfig struct {
+	MaxDepth        int
+	ReplyLimit      int
+	AllowGuest      bool
+	GuestRateLimit  int
+	GuestRateWindow time.Duration
+	ModerationMode  string
+	SpamThreshold   float64
+	BlockedWords    []string
+	MaxLinks        int
+	VelocityLimit   int
+	VelocityWindow  time.Duration
+}
+
 var AppConfig *Config
 
 func LoadConfig() *Config {
@@ -62,10 +253,16 @@ func LoadConfig() *Config {
 			PORT:               viper.GetString("app.port"),
 			BaseUrl:            viper.GetString("app.base_url"),
 			Domain:             viper.GetString("app.domain"),
+			PasswordResetUrl:   viper.GetString("app.password_reset_url"),
 			GoggleClientId:     viper.GetString("app.google_client_id"),
 			GoggleClientSecret: viper.GetString("app.google_client_secret"),
 			GoggleRedirectUrl:  viper.GetString("app.google_redirect_url"),
 		},
+		Server: ServerConfig{
+			DisablePrefork: viper.GetBool("server.disable_prefork"),
+			ProxyHeader:    viper.GetString("server.proxy_header"),
+			TrustedProxies: viper.GetStringSlice("server.trusted_proxies"),
+		},
 		DB: DBConfig{
 			Host:     viper.GetString("database.host"),
 			User:     viper.GetString("database.user"),
@@ -75,14 +272,100 @@ func LoadConfig() *Config {
 			SSLMode:  viper.GetString("database.sslmode"),
 		},
 		JWT: JwtConfig{
-			SecretKey:      viper.GetString("jwt.secret_key"),
-			AccessTokenExp: viper.GetDuration("jwt.access_token_exp"),
+			SecretKey:       viper.GetString("jwt.secret_key"),
+			AccessTokenExp:  viper.GetDuration("jwt.access_token_exp"),
+			RefreshTokenExp: viper.GetDuration("jwt.refresh_token_exp"),
+			Algorithm:       viper.GetString("jwt.algorithm"),
+			KeyDir:          viper.GetString("jwt.key_dir"),
+			ActiveKid:       viper.GetString("jwt.active_kid"),
+			Keys:            loadJwtKeys(),
 		},
 		Xendit: XenditConfig{
 			APIKey:     viper.GetString("xendit.api_key"),
 			WebhookKey: viper.GetString("xendit.webhook_key"),
 			BaseURL:    viper.GetString("xendit.base_url"),
 		},
+		Scheduler: SchedulerConfig{
+			PublishInterval: viper.GetDuration("scheduler.publish_interval"),
+		},
+		Search: SearchConfig{
+			IndexPath:         viper.GetString("search.index_path"),
+			ReconcileInterval: viper.GetDuration("search.reconcile_interval"),
+		},
+		Mail: MailConfig{
+			Driver:   viper.GetString("mail.driver"),
+			Host:     viper.GetString("mail.host"),
+			Port:     viper.GetString("mail.port"),
+			Username: viper.GetString("mail.username"),
+			Password: viper.GetString("mail.password"),
+			From:     viper.GetString("mail.from"),
+		},
+		Otp: OtpConfig{
+			Length:         viper.GetInt("otp.length"),
+			TTL:            viper.GetDuration("otp.ttl"),
+			MaxAttempts:    viper.GetInt("otp.max_attempts"),
+			ResendCooldown: viper.GetDuration("otp.resend_cooldown"),
+		},
+		TwoFactor: TwoFactorConfig{
+			Issuer:       viper.GetString("two_factor.issuer"),
+			RequireAdmin: viper.GetBool("two_factor.require_admin"),
+			PendingExp:   viper.GetDuration("two_factor.pending_exp"),
+
+			EncryptionKey:          viper.GetString("two_factor.encryption_key"),
+			EncryptionKeyID:        viper.GetString("two_factor.encryption_key_id"),
+			PreviousEncryptionKeys: viper.GetStringMapString("two_factor.previous_encryption_keys"),
+		},
+		OAuth: OAuthConfig{
+			Providers: loadOAuthProviders(),
+			StateTTL:  viper.GetDuration("oauth.state_ttl"),
+		},
+		LoginGuard: LoginGuardConfig{
+			Store:                viper.GetString("login_guard.store"),
+			MaxAccountFailures:   viper.GetInt("login_guard.max_account_failures"),
+			MaxIPFailures:        viper.GetInt("login_guard.max_ip_failures"),
+			MaxTwoFactorFailures: viper.GetInt("login_guard.max_two_factor_failures"),
+			MaxMfaTokenFailures:  viper.GetInt("login_guard.max_mfa_token_failures"),
+			BaseDelay:            viper.GetDuration("login_guard.base_delay"),
+			MaxDelay:             viper.GetDuration("login_guard.max_delay"),
+			LockoutDuration:      viper.GetDuration("login_guard.lockout_duration"),
+			FailureWindow:        viper.GetDuration("login_guard.failure_window"),
+		},
+		Profile: ProfileConfig{
+			AvatarMaxSize:          viper.GetInt64("profile.avatar_max_size"),
+			AvatarSize:             viper.GetInt("profile.avatar_size"),
+			UsernameChangeInterval: viper.GetDuration("profile.username_change_interval"),
+			UsernameReservation:    viper.GetDuration("profile.username_reservation"),
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod: viper.GetDuration("account.deletion_grace_period"),
+			DeletionMode:        viper.GetString("account.deletion_mode"),
+			PostAction:          viper.GetString("account.post_action"),
+			ReassignUserID:      viper.GetInt64("account.reassign_user_id"),
+			ExportDir:           viper.GetString("account.export_dir"),
+			ExportTTL:           viper.GetDuration("account.export_ttl"),
+			WorkerInterval:      viper.GetDuration("account.worker_interval"),
+		},
+		Feed: FeedConfig{
+			Strategy:       viper.GetString("feed.strategy"),
+			PageSize:       viper.GetInt("feed.page_size"),
+			MaxPageSize:    viper.GetInt("feed.max_page_size"),
+			FanoutInterval: viper.GetDuration("feed.fanout_interval"),
+			FanoutBatch:    viper.GetInt("feed.fanout_batch"),
+			Retention:      viper.GetDuration("feed.retention"),
+		},
+		Comment: CommentConfig{
+			MaxDepth:        viper.GetInt("comment.max_depth"),
+			ReplyLimit:      viper.GetInt("comment.reply_limit"),
+			AllowGuest:      viper.GetBool("comment.allow_guest"),
+			GuestRateLimit:  viper.GetInt("comment.guest_rate_limit"),
+			GuestRateWindow: viper.GetDuration("comment.guest_rate_window"),
+			ModerationMode:  viper.GetString("comment.moderation_mode"),
+			SpamThreshold:   viper.GetFloat64("comment.spam_threshold"),
+			BlockedWords:    viper.GetStringSlice("comment.blocked_words"),
+			MaxLinks:        viper.GetInt("comment.max_links"),
+			VelocityLimit:   viper.GetInt("comment.velocity_limit"),
+			VelocityWindow:  viper.GetDuration("comment.velocity_window"),
+		},
 	}
 
 	validateConfig(conf)
@@ -101,6 +384,66 @@ func validateConfig(cfg *Config) {
 		log.Fatal("❌ Missing required JWT configuration (SecretKey)")
 	}
 
+	switch cfg.JWT.GetAlgorithm() {
+	case JwtAlgorithmHS256:
+	case JwtAlgorithmRS256, JwtAlgorithmEdDSA:
+		if cfg.JWT.KeyDir == "" && len(cfg.JWT.Keys) == 0 {
+			log.Fatal("❌ JWT algorithm " + cfg.JWT.Algorithm + " needs jwt.key_dir or jwt.keys")
+		}
+	default:
+		log.Fatal("❌ Unsupported JWT algorithm " + cfg.JWT.Algorithm)
+	}
+
+	if cfg.TwoFactor.EncryptionKey == "" {
+		log.Fatal("❌ Missing required two factor configuration (two_factor.encryption_key)")
+	}
+	if _, err := cfg.TwoFactor.EncryptionKeys(); err != nil {
+		log.Fatal("❌ Invalid two factor encryption key: " + err.Error())
+	}
+
+	switch cfg.Account.GetDeletionMode() {
+	case AccountDeletionAnonymize, AccountDeletionPurge:
+	default:
+		log.Fatal("❌ Unsupported account deletion mode " + cfg.Account.DeletionMode)
+	}
+
+	switch cfg.Account.GetPostAction() {
+	case AccountPostDelete:
+	case AccountPostReassign:
+		// tanpa user tujuan, post tetap milik akun anonim; purge butuh tujuan karena barisnya hilang
+		if cfg.Account.GetDeletionMode() == AccountDeletionPurge && cfg.Account.ReassignUserID <= 0 {
+			log.Fatal("❌ account.post_action reassign with purge mode needs account.reassign_user_id")
+		}
+	default:
+		log.Fatal("❌ Unsupported account post action " + cfg.Account.PostAction)
+	}
+
+	// tanpa daftar proxy, siapa pun bisa mengirim header ProxyHeader dan memalsukan IP-nya
+	if cfg.Server.ProxyHeader != "" && len(cfg.Server.TrustedProxies) == 0 {
+		log.Fatal("❌ server.proxy_header needs server.trusted_proxies")
+	}
+
+	switch cfg.LoginGuard.GetStore() {
+	case LoginGuardStoreDatabase:
+	case LoginGuardStoreMemory:
+		// setiap child prefork punya counter sendiri, batas gagal jadi berlipat dan daftar lockout admin tidak lengkap
+		if cfg.Server.IsPrefork() {
+			log.Fatal("❌ login_guard.store memory cannot be used with prefork, use database or set server.disable_prefork")
+		}
+	default:
+		log.Fatal("❌ Unsupported login guard store " + cfg.LoginGuard.Store)
+	}
+
+	switch cfg.Feed.GetStrategy() {
+	case FeedStrategyRead, FeedStrategyWrite:
+	default:
+		log.Fatal("❌ Unsupported feed strategy " + cfg.Feed.Strategy)
+	}
+
+}
+
+func (c *ServerConfig) IsPrefork() bool {
+	return !c.DisablePrefork
 }
 
 func (c *DBConfig) Dsn() string {
@@ -118,9 +461,36 @@ func (c *JwtConfig) GetSecretKey() string {
 	return c.SecretKey
 }
 
+func (c *JwtConfig) GetAlgorithm() string {
+	if c.Algorithm == "" {
+		return JwtAlgorithmHS256
+	}
+	return c.Algorithm
+}
+
+// IsAsymmetric true kalau token login ditandatangani dengan private key dan bisa diverifikasi lewat JWKS
+func (c *JwtConfig) IsAsymmetric() bool {
+	return c.GetAlgorithm() != JwtAlgorithmHS256
+}
+
+func loadJwtKeys() []JwtKeyConfig {
+	var keys []JwtKeyConfig
+	if err := viper.UnmarshalKey("jwt.keys", &keys); err != nil {
+		log.Printf("failed to read jwt keys: %v", err)
+	}
+	return keys
+}
+
 func (c *JwtConfig) GetExpTimeAccessToken() time.Duration {
 	return c.AccessTokenExp
 }
+
+func (c *JwtConfig) GetExpTimeRefreshToken() time.Duration {
+	if c.RefreshTokenExp <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return c.RefreshTokenExp
+}
 func (c *XenditConfig) GetBaseUrl() string {
 	return c.BaseURL
 }
@@ -143,6 +513,14 @@ func (c *AppMain) GetPort() string {
 func (c *AppMain) GetBaseUrl() string {
 	return c.BaseUrl
 }
+
+// GetPasswordResetUrl halaman frontend yang menerima query token untuk reset password
+func (c *AppMain) GetPasswordResetUrl() string {
+	if c.PasswordResetUrl == "" {
+		return c.BaseUrl + "/reset-password"
+	}
+	return c.PasswordResetUrl
+}
 func (c *AppMain) GetGoogleClientId() string {
 	return c.GoggleClientId
 }
@@ -154,3 +532,385 @@ func (c *AppMain) GetGoogleClientSecret() string {
 func (c *AppMain) GetGoogleRedirctUrl() string {
 	return c.GoggleRedirectUrl
 }
+
+func (c *SchedulerConfig) GetPublishInterval() time.Duration {
+	if c.PublishInterval <= 0 {
+		return time.Minute
+	}
+	return c.PublishInterval
+}
+
+func (c *SearchConfig) GetIndexPath() string {
+	if c.IndexPath == "" {
+		return "./data/search_index.gob"
+	}
+	return c.IndexPath
+}
+
+func (c *SearchConfig) GetReconcileInterval() time.Duration {
+	if c.ReconcileInterval <= 0 {
+		return time.Hour
+	}
+	return c.ReconcileInterval
+}
+
+func (c *CommentConfig) GetMaxDepth() int {
+	if c.MaxDepth <= 0 {
+		return 5
+	}
+	return c.MaxDepth
+}
+
+func (c *CommentConfig) GetReplyLimit() int {
+	if c.ReplyLimit <= 0 {
+		return 3
+	}
+	return c.ReplyLimit
+}
+
+func (c *CommentConfig) GetModerationMode() string {
+	switch c.ModerationMode {
+	case "off", "first_time", "all":
+		return c.ModerationMode
+	default:
+		return "first_time"
+	}
+}
+
+func (c *CommentConfig) GetSpamThreshold() float64 {
+	if c.SpamThreshold <= 0 {
+		return 0.5
+	}
+	return c.SpamThreshold
+}
+
+func (c *CommentConfig) GetGuestRateLimit() int {
+	if c.GuestRateLimit <= 0 {
+		return 3
+	}
+	return c.GuestRateLimit
+}
+
+func (c *CommentConfig) GetGuestRateWindow() time.Duration {
+	if c.GuestRateWindow <= 0 {
+		return 10 * time.Minute
+	}
+	return c.GuestRateWindow
+}
+
+func (c *MailConfig) GetDriver() string {
+	if c.Driver == "" {
+		return MailDriverSMTP
+	}
+	return c.Driver
+}
+
+func (c *MailConfig) GetPort() string {
+	if c.Port == "" {
+		return "587"
+	}
+	return c.Port
+}
+
+func (c *OtpConfig) GetLength() int {
+	if c.Length < 4 || c.Length > 10 {
+		return 6
+	}
+	return c.Length
+}
+
+func (c *OtpConfig) GetTTL() time.Duration {
+	if c.TTL <= 0 {
+		return 10 * time.Minute
+	}
+	return c.TTL
+}
+
+func (c *OtpConfig) GetMaxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return 5
+	}
+	return c.MaxAttempts
+}
+
+func (c *OtpConfig) GetResendCooldown() time.Duration {
+	if c.ResendCooldown <= 0 {
+		return time.Minute
+	}
+	return c.ResendCooldown
+}
+
+func (c *TwoFactorConfig) GetIssuer() string {
+	if c.Issuer == "" {
+		return "Blog API"
+	}
+	return c.Issuer
+}
+
+// GetPendingExp masa berlaku token mfa_pending antara cek password dan input kode TOTP
+func (c *TwoFactorConfig) GetPendingExp() time.Duration {
+	if c.PendingExp <= 0 {
+		return 5 * time.Minute
+	}
+	return c.PendingExp
+}
+
+// GetEncryptionKeyID huruf kecil karena viper juga menyimpan key map (previous_encryption_keys) dalam huruf kecil
+func (c *TwoFactorConfig) GetEncryptionKeyID() string {
+	if c.EncryptionKeyID == "" {
+		return "v1"
+	}
+	return strings.ToLower(c.EncryptionKeyID)
+}
+
+// EncryptionKeys semua key yang bisa dipakai decrypt (aktif + lama) per key id
+func (c *TwoFactorConfig) EncryptionKeys() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(c.PreviousEncryptionKeys)+1)
+
+	add := func(id string, value string) error {
+		if id == "" || strings.Contains(id, ":") {
+			return fmt.Errorf("key id %q must not be empty or contain ':'", id)
+		}
+		if _, exists := keys[id]; exists {
+			return fmt.Errorf("key id %q is used more than once", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) != 32 {
+			return fmt.Errorf("key %q must be 32 bytes encoded as base64", id)
+		}
+		keys[id] = key
+		return nil
+	}
+
+	if err := add(c.GetEncryptionKeyID(), c.EncryptionKey); err != nil {
+		return nil, err
+	}
+	for id, value := range c.PreviousEncryptionKeys {
+		if err := add(id, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func loadOAuthProviders() map[string]OAuthProviderConfig {
+	providers := map[string]OAuthProviderConfig{}
+	if err := viper.UnmarshalKey("oauth.providers", &providers); err != nil {
+		log.Printf("failed to read oauth providers: %v", err)
+	}
+	return providers
+}
+
+// GetProviders provider dari oauth.providers, ditambah google dari config lama app.google_* kalau belum didefinisikan
+func (c *OAuthConfig) GetProviders(app *AppMain) map[string]OAuthProviderConfig {
+	providers := make(map[string]OAuthProviderConfig, len(c.Providers)+1)
+	for name, provider := range c.Providers {
+		providers[name] = provider
+	}
+
+	if _, ok := providers["google"]; !ok && app.GetGoogleClientId() != "" {
+		providers["google"] = OAuthProviderConfig{
+			Type:         OAuthProviderOIDC,
+			DisplayName:  "Google",
+			Issuer:       googleIssuer,
+			ClientID:     app.GetGoogleClientId(),
+			ClientSecret: app.GetGoogleClientSecret(),
+			RedirectURL:  app.GetGoogleRedirctUrl(),
+		}
+	}
+
+	return providers
+}
+
+func (c *OAuthConfig) GetStateTTL() time.Duration {
+	if c.StateTTL <= 0 {
+		return 10 * time.Minute
+	}
+	return c.StateTTL
+}
+
+// GetStore default database supaya counter dibagi ke semua child prefork, memory hanya untuk server tanpa prefork
+func (c *LoginGuardConfig) GetStore() string {
+	if c.Store == "" {
+		return LoginGuardStoreDatabase
+	}
+	return c.Store
+}
+
+func (c *LoginGuardConfig) GetMaxAccountFailures() int {
+	if c.MaxAccountFailures <= 0 {
+		return 5
+	}
+	return c.MaxAccountFailures
+}
+
+func (c *LoginGuardConfig) GetMaxIPFailures() int {
+	if c.MaxIPFailures <= 0 {
+		return 20
+	}
+	return c.MaxIPFailures
+}
+
+func (c *LoginGuardConfig) GetMaxTwoFactorFailures() int {
+	if c.MaxTwoFactorFailures <= 0 {
+		return 5
+	}
+	return c.MaxTwoFactorFailures
+}
+
+// GetMaxMfaTokenFailures setelah batas ini token mfa_pending tidak bisa dipakai lagi, user harus login ulang
+func (c *LoginGuardConfig) GetMaxMfaTokenFailures() int {
+	if c.MaxMfaTokenFailures <= 0 {
+		return 3
+	}
+	return c.MaxMfaTokenFailures
+}
+
+func (c *LoginGuardConfig) GetBaseDelay() time.Duration {
+	if c.BaseDelay <= 0 {
+		return time.Second
+	}
+	return c.BaseDelay
+}
+
+func (c *LoginGuardConfig) GetMaxDelay() time.Duration {
+	if c.MaxDelay <= 0 {
+		return 30 * time.Second
+	}
+	return c.MaxDelay
+}
+
+func (c *LoginGuardConfig) GetLockoutDuration() time.Duration {
+	if c.LockoutDuration <= 0 {
+		return 15 * time.Minute
+	}
+	return c.LockoutDuration
+}
+
+// GetFailureWindow counter gagal di-reset kalau tidak ada kegagalan baru selama window ini
+func (c *LoginGuardConfig) GetFailureWindow() time.Duration {
+	if c.FailureWindow <= 0 {
+		return time.Hour
+	}
+	return c.FailureWindow
+}
+
+// GetAvatarMaxSize ukuran file avatar maksimal dalam byte
+func (c *ProfileConfig) GetAvatarMaxSize() int64 {
+	if c.AvatarMaxSize <= 0 {
+		return 2 * 1024 * 1024
+	}
+	return c.AvatarMaxSize
+}
+
+// GetAvatarSize sisi avatar persegi setelah dipotong, dalam pixel
+func (c *ProfileConfig) GetAvatarSize() int {
+	if c.AvatarSize <= 0 {
+		return 256
+	}
+	return c.AvatarSize
+}
+
+func (c *ProfileConfig) GetUsernameChangeInterval() time.Duration {
+	if c.UsernameChangeInterval <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return c.UsernameChangeInterval
+}
+
+// GetUsernameReservation lama username lama tidak bisa dipakai user lain setelah diganti
+func (c *ProfileConfig) GetUsernameReservation() time.Duration {
+	if c.UsernameReservation <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return c.UsernameReservation
+}
+
+func (c *AccountConfig) GetDeletionGracePeriod() time.Duration {
+	if c.DeletionGracePeriod <= 0 {
+		return 14 * 24 * time.Hour
+	}
+	return c.DeletionGracePeriod
+}
+
+func (c *AccountConfig) GetDeletionMode() string {
+	if c.DeletionMode == "" {
+		return AccountDeletionAnonymize
+	}
+	return c.DeletionMode
+}
+
+func (c *AccountConfig) GetPostAction() string {
+	if c.PostAction == "" {
+		return AccountPostReassign
+	}
+	return c.PostAction
+}
+
+// GetExportDir folder zip export, sengaja di luar ./public karena berisi data pribadi
+func (c *AccountConfig) GetExportDir() string {
+	if c.ExportDir == "" {
+		return "./storage/exports"
+	}
+	return c.ExportDir
+}
+
+// GetExportTTL lama file export bisa diunduh sebelum dihapus
+func (c *AccountConfig) GetExportTTL() time.Duration {
+	if c.ExportTTL <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return c.ExportTTL
+}
+
+func (c *AccountConfig) GetWorkerInterval() time.Duration {
+	if c.WorkerInterval <= 0 {
+		return time.Minute
+	}
+	return c.WorkerInterval
+}
+
+func (c *FeedConfig) GetStrategy() string {
+	if c.Strategy == "" {
+		return FeedStrategyRead
+	}
+	return c.Strategy
+}
+
+func (c *FeedConfig) GetPageSize() int {
+	if c.PageSize <= 0 {
+		return 20
+	}
+	return c.PageSize
+}
+
+func (c *FeedConfig) GetMaxPageSize() int {
+	if c.MaxPageSize <= 0 {
+		return 50
+	}
+	return c.MaxPageSize
+}
+
+func (c *FeedConfig) GetFanoutInterval() time.Duration {
+	if c.FanoutInterval <= 0 {
+		return 30 * time.Second
+	}
+	return c.FanoutInterval
+}
+
+// GetFanoutBatch jumlah post yang disebar ke inbox follower per putaran worker
+func (c *FeedConfig) GetFanoutBatch() int {
+	if c.FanoutBatch <= 0 {
+		return 50
+	}
+	return c.FanoutBatch
+}
+
+// GetRetention item inbox yang lebih tua dari ini dihapus, post lama juga tidak disebar lagi
+func (c *FeedConfig) GetRetention() time.Duration {
+	if c.Retention <= 0 {
+		return 90 * 24 * time.Hour
+	}
+	return c.Retention
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/models"
+)
+
+// DeleteAccountRequest Password boleh kosong untuk akun login provider yang belum punya password
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type AccountDeletionResponse struct {
+	DeletionScheduledAt time.Time `json:"deletionScheduledAt"`
+}
+
+// AccountDeletionOptions cara akun dihapus, diisi dari config account.*
+type AccountDeletionOptions struct {
+	Purge       bool
+	DeletePosts bool
+	// ReassignTo 0 berarti post tetap milik akun yang dianonimkan
+	ReassignTo  int64
+	DeletedName string
+}
+
+type DataExportResponse struct {
+	ID           int64      `json:"id"`
+	Status       string     `json:"status"`
+	FileSize     int64      `json:"fileSize,omitempty"`
+	ErrorMessage *string    `json:"errorMessage,omitempty"`
+	DownloadURL  string     `json:"downloadUrl,omitempty"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// ExportProfile isi profile.json di file export, password dan secret lain tidak ikut
+type ExportProfile struct {
+	ID              int64                 `json:"id"`
+	Name            string                `json:"name"`
+	Username        string                `json:"username"`
+	Email           string                `json:"email"`
+	Bio             *string               `json:"bio,omitempty"`
+	ProfileImageURI *string               `json:"profileImageUri,omitempty"`
+	SocialLinks     *models.SocialLinks   `json:"socialLinks,omitempty"`
+	Role            int                   `json:"role"`
+	Status          int                   `json:"status"`
+	AuthProvider    string                `json:"authProvider"`
+	EmailVerifiedAt *time.Time            `json:"emailVerifiedAt,omitempty"`
+	IsSubscribed    bool                  `json:"isSubscribed"`
+	SubscriptionEnd *time.Time            `json:"subscriptionEnd,omitempty"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	UpdatedAt       time.Time             `json:"updatedAt"`
+	Identities      []models.UserIdentity `json:"identities"`
+}
+
+type ExportReadingList struct {
+	models.ReadingList
+	SavedPosts []models.SavedPost `json:"savedPosts"`
+}
+
+type ExportFollows struct {
+	Followers []models.Follower `json:"followers"`
+	Following []models.Follower `json:"following"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type LoginRequest struct {
 	Identifier string `json:"identifier" validate:"required"`
 	Password   string `json:"password" validate:"required"`
@@ -10,6 +12,11 @@ type LoginResponse struct {
 	AccessToken  string `json:"accessToken"`
 	TypeToken    string `json:"typeToken"`
 	RefreshToken string `json:"refreshToken"`
+
+	// diisi kalau login masih butuh langkah 2FA, token lain kosong
+	MfaRequired      bool   `json:"mfaRequired,omitempty"`
+	MfaSetupRequired bool   `json:"mfaSetupRequired,omitempty"`
+	MfaToken         string `json:"mfaToken,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -21,20 +28,98 @@ type RegisterRequest struct {
 	Role     int    `json:"role"`
 }
 
-type GoogleCallbackRequest struct {
-	Code string `json:"code" validate:"required"`
+type VerifyOtpRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Code  string `json:"code" validate:"required,numeric"`
+}
+
+type ResendOtpRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8"`
 }
 
-type GoogleAuthURLResponse struct {
+// ChangePasswordRequest CurrentPassword boleh kosong untuk akun login provider yang belum punya password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+type OAuthURLResponse struct {
 	URL string `json:"url"`
 }
 
-type GoogleUserInfo struct {
-	ID            string `json:"id"`
-	Email         string `json:"email"`
-	VerifiedEmail bool   `json:"verifiedEmail"`
-	Name          string `json:"name"`
-	GivenName     string `json:"givenName"`
-	FamilyName    string `json:"familyName"`
-	Picture       string `json:"picture"`
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// SessionMeta informasi device yang disimpan di setiap session
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+type UserSessionResponse struct {
+	ID         int64     `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	Current    bool      `gorm:"-" json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+	QRCode          string `json:"qrCode"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+// TwoFactorVerifyRequest isi Code (TOTP) atau RecoveryCode
+type TwoFactorVerifyRequest struct {
+	MfaToken     string `json:"mfaToken" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recoveryCode" validate:"required_without=Code"`
+}
+
+type TwoFactorPendingRequest struct {
+	MfaToken string `json:"mfaToken" validate:"required"`
+}
+
+type TwoFactorPendingEnableRequest struct {
+	MfaToken string `json:"mfaToken" validate:"required"`
+	Code     string `json:"code" validate:"required,numeric,len=6"`
+}
+
+type TwoFactorDisableRequest struct {
+	Code         string `json:"code" validate:"required_without=RecoveryCode,omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recoveryCode" validate:"required_without=Code"`
+}
+
+type TwoFactorEnableResponse struct {
+	RecoveryCodes []string       `json:"recoveryCodes"`
+	Login         *LoginResponse `json:"login,omitempty"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabledAt,omitempty"`
+	Required               bool       `json:"required"`
+	RemainingRecoveryCodes int64      `json:"remainingRecoveryCodes"`
 }
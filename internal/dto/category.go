@@ -1,11 +1,15 @@
 package dto
 
+import "time"
+
 type CategoryResponse struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name"`
-	Slug     string `json:"slug"`
-	Desc     string `json:"desc"`
-	ParentId int    `json:"parentId"`
+	Id                 int    `json:"id"`
+	Name               string `json:"name"`
+	Slug               string `json:"slug"`
+	Desc               string `json:"desc"`
+	ParentId           int    `json:"parentId"`
+	FollowerCount      int64  `json:"followerCount"`
+	PublishedPostCount int64  `json:"publishedPostCount"`
 }
 
 type CategoryRequst struct {
@@ -13,3 +17,24 @@ type CategoryRequst struct {
 	Desc     string `json:"desc"`
 	ParentId int    `json:"parentId" validate:"numeric"`
 }
+
+type CategoryTreeResponse struct {
+	Id                 int                     `json:"id"`
+	Name               string                  `json:"name"`
+	Slug               string                  `json:"slug"`
+	Desc               string                  `json:"desc"`
+	ParentId           int                     `json:"parentId"`
+	FollowerCount      int64                   `json:"followerCount"`
+	PublishedPostCount int64                   `json:"publishedPostCount"`
+	Children           []*CategoryTreeResponse `json:"children"`
+}
+
+type FollowedCategoryResponse struct {
+	CategoryResponse
+	FollowedAt time.Time `json:"followedAt"`
+}
+
+// MoveCategoryRequest ParentId null atau 0 menjadikan kategori root
+type MoveCategoryRequest struct {
+	ParentId *int64 `json:"parentId"`
+}
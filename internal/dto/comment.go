@@ -5,3 +5,16 @@ type CommentRequest struct {
 	ParentId int    `json:"parentId"`
 	Content  string `json:"content" validate:"required"`
 }
+
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,max=5000"`
+}
+
+type GuestCommentRequest struct {
+	PostId    int    `json:"postId"`
+	ParentId  int    `json:"parentId"`
+	Name      string `json:"name" validate:"required,max=150"`
+	Email     string `json:"email" validate:"required,email,max=150"`
+	Content   string `json:"content" validate:"required,max=5000"`
+	IPAddress string `json:"-"`
+}
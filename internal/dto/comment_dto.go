@@ -8,13 +8,43 @@ type CommentWithUserResponse struct {
 	UserID    *int64             `json:"userId,omitempty"`
 	ParentID  *int64             `json:"parentId,omitempty"`
 	Name      *string            `json:"name,omitempty"`
-	Email     *string            `json:"email,omitempty"`
+	Email     *string            `json:"-"`
 	Content   string             `json:"content"`
 	Status    int8               `json:"status"`
+	LikeCount int64              `json:"likeCount"`
+	LikedByMe bool               `json:"likedByMe"`
+	IsDeleted bool               `json:"isDeleted"`
+	EditedAt  *time.Time         `json:"editedAt,omitempty"`
 	CreatedAt time.Time          `json:"createdAt"`
 	User      *UserBriefResponse `json:"user,omitempty"`
 }
 
+// MaskDeleted mengosongkan isi dan penulis komentar yang sudah dihapus (status 2)
+func (c *CommentWithUserResponse) MaskDeleted() {
+	if c.Status != 2 {
+		return
+	}
+	c.IsDeleted = true
+	c.Content = ""
+	c.UserID = nil
+	c.User = nil
+	c.Name = nil
+	c.Email = nil
+}
+
+type CommentNodeResponse struct {
+	CommentWithUserResponse
+	ReplyCount int64                  `json:"replyCount"`
+	Replies    []*CommentNodeResponse `json:"replies"`
+	// NextCursor diisi kalau masih ada balasan yang belum dimuat, dipakai untuk endpoint replies
+	NextCursor *int64 `json:"nextCursor,omitempty"`
+}
+
+type CommentRepliesResponse struct {
+	Replies    []*CommentNodeResponse `json:"replies"`
+	NextCursor *int64                 `json:"nextCursor,omitempty"`
+}
+
 type UserBriefResponse struct {
 	ID              int64   `json:"id"`
 	Name            string  `json:"name"`
@@ -22,3 +52,22 @@ type UserBriefResponse struct {
 	Email           string  `json:"email"`
 	ProfileImageURI *string `json:"profileImageUri"`
 }
+
+type CommentModerationResponse struct {
+	CommentWithUserResponse
+	GuestEmail  *string `gorm:"-" json:"guestEmail,omitempty"`
+	PostTitle   string  `json:"postTitle"`
+	PostSlug    string  `json:"postSlug"`
+	SpamScore   float64 `json:"spamScore"`
+	SpamReasons *string `json:"spamReasons,omitempty"`
+}
+
+type CommentModerationBulkRequest struct {
+	IDs    []int64 `json:"ids" validate:"required,min=1,max=100"`
+	Action string  `json:"action" validate:"required,oneof=approve reject"`
+}
+
+type CommentModerationBulkResponse struct {
+	Processed int64 `json:"processed"`
+	Skipped   int64 `json:"skipped"`
+}
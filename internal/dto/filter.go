@@ -1,14 +1,22 @@
 package dto
 
+import "time"
+
 type PostFilterRequest struct {
 	Title           string `json:"title" query:"title"`
 	CategoryID      int    `json:"categoryId" query:"categoryId"`
 	AuthorID        int    `json:"authorId" query:"authorId"`
 	Status          int    `json:"status" query:"status"`
+	Tag             string `json:"tag" query:"tag"`
+	Unapproved      int    `json:"unapproved" query:"unapproved"`
 	IncludeLike     int    `json:"includeLike" query:"includeLike"`
 	IncludeAuthor   int    `json:"includeAuthor" query:"includeAuthor"`
 	IncludeCategory int    `json:"includeCategory" query:"includeCategory"`
 	IncludeComment  int    `json:"includeComment" query:"includeComment"`
+	// ViewerID user yang sedang login, dipakai untuk flag likedByMe (0 = anonim)
+	ViewerID int `json:"-"`
+	// IncludeSubcategories 1 berarti post di semua turunan CategoryID ikut ditampilkan
+	IncludeSubcategories int `json:"includeSubcategories" query:"includeSubcategories"`
 	PaginationParams
 }
 
@@ -27,5 +35,65 @@ type CategoryFilterRequest struct {
 type CommentFilterRequest struct {
 	PostId   int `json:"postId"`
 	ParentId int `json:"parentId"`
+	ViewerID int `json:"-"`
+	PaginationParams
+}
+
+type CommentTreeFilterRequest struct {
+	PostId     int `json:"postId"`
+	Depth      int `json:"depth" query:"depth"`
+	ReplyLimit int `json:"replyLimit" query:"reply_limit"`
+	ViewerID   int `json:"-"`
+	PaginationParams
+}
+
+type CommentRepliesFilterRequest struct {
+	PostId     int   `json:"postId"`
+	ParentId   int64 `json:"parentId"`
+	Cursor     int64 `json:"cursor" query:"cursor"`
+	Limit      int   `json:"limit" query:"limit"`
+	Depth      int   `json:"depth" query:"depth"`
+	ReplyLimit int   `json:"replyLimit" query:"reply_limit"`
+	ViewerID   int   `json:"-"`
+}
+
+// FeedFilterRequest Cursor berasal dari nextCursor halaman sebelumnya, kosong untuk halaman pertama
+type FeedFilterRequest struct {
+	Cursor string `json:"cursor" query:"cursor"`
+	Limit  int    `json:"limit" query:"limit"`
+	// AfterPublishedAt dan AfterID hasil decode Cursor, post yang diambil lebih lama dari posisi ini
+	AfterPublishedAt *time.Time `json:"-"`
+	AfterID          int64      `json:"-"`
+	ViewerID         int        `json:"-"`
+}
+
+type TagFilterRequest struct {
+	Name string `json:"name" query:"name"`
+	PaginationParams
+}
+
+type SearchFilterRequest struct {
+	Query      string `json:"q" query:"q" validate:"required,max=200"`
+	CategoryID int    `json:"categoryId" query:"category_id"`
+	AuthorID   int    `json:"authorId" query:"author_id"`
+	PaginationParams
+}
+
+type LikeFilterRequest struct {
+	UserID     int `json:"userId"`
+	TargetType int `json:"targetType" query:"type"`
+	PaginationParams
+}
+
+type CommentModerationFilterRequest struct {
+	Status int `json:"status" query:"status"`
+	PostId int `json:"postId" query:"post_id"`
+	PaginationParams
+}
+
+type SecurityLogFilterRequest struct {
+	Event     string `json:"event" query:"event"`
+	UserID    int64  `json:"userId" query:"user_id"`
+	IPAddress string `json:"ipAddress" query:"ip"`
 	PaginationParams
 }
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type LikeStatusResponse struct {
+	TargetType int   `json:"targetType"`
+	TargetID   int64 `json:"targetId"`
+	Liked      bool  `json:"liked"`
+	LikeCount  int64 `json:"likeCount"`
+}
+
+type LikeResponse struct {
+	ID             int64     `json:"id"`
+	TargetType     int       `json:"targetType"`
+	TargetID       int64     `json:"targetId"`
+	PostID         int64     `json:"postId"`
+	PostTitle      string    `json:"postTitle"`
+	PostSlug       string    `json:"postSlug"`
+	CommentContent *string   `json:"commentContent,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
@@ -19,6 +19,8 @@ type PaginationResult struct {
 	FieldName string
 	Data      interface{}    `json:"data"`
 	Meta      PaginationMeta `json:"meta"`
+	// Extra berisi field tambahan di level yang sama dengan data & meta (misal facets)
+	Extra map[string]interface{} `json:"-"`
 }
 
 func NewPaginationResult(data interface{}, total int64, page, pageSize int, field string) *PaginationResult {
@@ -45,10 +47,15 @@ func (p *PaginationResult) MarshalJSON() ([]byte, error) {
 		field = "data"
 	}
 
-	return json.Marshal(map[string]interface{}{
+	result := map[string]interface{}{
 		field:  p.Data,
 		"meta": p.Meta,
-	})
+	}
+	for key, value := range p.Extra {
+		result[key] = value
+	}
+
+	return json.Marshal(result)
 }
 
 // SetDefaults untuk set nilai default pagination
@@ -3,18 +3,26 @@ package dto
 import "time"
 
 type CreatePostRequest struct {
-	Id         int    `json:"id,omitempty"`
-	Title      string `json:"title" validate:"required"`
-	Content    string `json:"content" validate:"required"`
-	CategoryId int    `json:"categoryId" validate:"required"`
-	ImgUrl     string `json:"imgUrl"`
+	Id         int        `json:"id,omitempty"`
+	Title      string     `json:"title" validate:"required"`
+	Content    string     `json:"content" validate:"required"`
+	CategoryId int        `json:"categoryId" validate:"required"`
+	ImgUrl     string     `json:"imgUrl"`
+	Tags       []string   `json:"tags" validate:"omitempty,max=10,dive,required,max=50"`
+	PublishAt  *time.Time `json:"publishAt"`
+	// AllowGuestComments nil berarti mengikuti setting global
+	AllowGuestComments *bool `json:"allowGuestComments"`
 }
 
 type UpdatePostRequest struct {
 	Slug    string  `json:"slug,omitempty"`
 	Title   *string `json:"title"`
 	Content *string `json:"content"`
-	Status  int     `json:"status" validate:"required"`
+	Status  *int    `json:"status" validate:"omitempty,min=0,max=4"`
+	// Tags nil berarti tag tidak diubah, slice kosong berarti hapus semua tag
+	Tags               *[]string  `json:"tags" validate:"omitempty,max=10,dive,required,max=50"`
+	PublishAt          *time.Time `json:"publishAt"`
+	AllowGuestComments *bool      `json:"allowGuestComments"`
 }
 
 type AuthorResponse struct {
@@ -24,18 +32,29 @@ type AuthorResponse struct {
 }
 
 type PostResponse struct {
-	ID             uint64            `json:"id"`
-	Title          string            `json:"title"`
-	Slug           string            `json:"slug"`
-	Content        string            `json:"content"`
-	MainImageURI   string            `json:"mainImageURI"`
-	AuthorId       int               `json:"authorId"`
-	AuthorDetail   *AuthorResponse   `gorm:"embedded;embeddedPrefix:AuthorDetail_" json:"authorDetail,omitempty"`
-	CategoryDetail *CategoryResponse `gorm:"embedded;embeddedPrefix:CategoryDetail_" json:"categoryDetail,omitempty"`
-	LikeCount      int64             `json:"likeCount"`
-	Status         int               `json:"status"`
-	CreatedAt      time.Time         `json:"createdAt"`
-	UpdatedAt      time.Time         `json:"updatedAt"`
+	ID                 uint64            `json:"id"`
+	Title              string            `json:"title"`
+	Slug               string            `json:"slug"`
+	Content            string            `json:"content"`
+	MainImageURI       string            `json:"mainImageURI"`
+	AuthorId           int               `json:"authorId"`
+	AuthorDetail       *AuthorResponse   `gorm:"embedded;embeddedPrefix:AuthorDetail_" json:"authorDetail,omitempty"`
+	CategoryDetail     *CategoryResponse `gorm:"embedded;embeddedPrefix:CategoryDetail_" json:"categoryDetail,omitempty"`
+	LikeCount          int64             `json:"likeCount"`
+	LikedByMe          bool              `json:"likedByMe"`
+	Tags               []TagResponse     `gorm:"-" json:"tags,omitempty"`
+	Series             *PostSeriesInfo   `gorm:"-" json:"series,omitempty"`
+	Status             int               `json:"status"`
+	PublishedAt        *time.Time        `json:"publishedAt,omitempty"`
+	ScheduledAt        *time.Time        `json:"scheduledAt,omitempty"`
+	AllowGuestComments *bool             `json:"allowGuestComments,omitempty"`
+	CreatedAt          time.Time         `json:"createdAt"`
+	UpdatedAt          time.Time         `json:"updatedAt"`
+}
+
+type FeedResponse struct {
+	Posts      []PostResponse `json:"posts"`
+	NextCursor *string        `json:"nextCursor,omitempty"`
 }
 
 type PostUploadResponse struct {
@@ -115,3 +134,25 @@ type UpdateSavedPostRequest struct {
 	Notes  *string `json:"notes"`
 	IsRead *bool   `json:"isRead"`
 }
+
+type PostStatusEventResponse struct {
+	ID         int64     `json:"id"`
+	PostID     int64     `json:"postId"`
+	PostTitle  string    `json:"postTitle,omitempty"`
+	PostSlug   string    `json:"postSlug,omitempty"`
+	FromStatus int       `json:"fromStatus"`
+	ToStatus   int       `json:"toStatus"`
+	ActorID    *int64    `json:"actorId,omitempty"`
+	ActorName  *string   `json:"actorName,omitempty"`
+	Comment    *string   `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type PostReviewRequest struct {
+	Comment string `json:"comment" validate:"max=2000"`
+}
+
+type PostStatusEventFilterRequest struct {
+	ActorID int `json:"actorId"`
+	PaginationParams
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type PostRevisionResponse struct {
+	ID             int64     `json:"id"`
+	PostID         int64     `json:"postId"`
+	RevisionNumber int       `json:"revisionNumber"`
+	Title          string    `json:"title"`
+	Content        string    `json:"content,omitempty"`
+	EditorID       int64     `json:"editorId"`
+	EditorName     string    `json:"editorName"`
+	RestoredFromID *int64    `json:"restoredFromId,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type PostRevisionDiffResponse struct {
+	From         PostRevisionResponse `json:"from"`
+	To           PostRevisionResponse `json:"to"`
+	TitleChanged bool                 `json:"titleChanged"`
+	Additions    int                  `json:"additions"`
+	Deletions    int                  `json:"deletions"`
+	Lines        []utils.DiffLine     `json:"lines"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "time"
+
+// LoginLockoutResponse akun atau IP yang sedang dikunci karena terlalu banyak login gagal
+type LoginLockoutResponse struct {
+	Key           string     `json:"key"`
+	Scope         string     `json:"scope"`
+	UserID        *int64     `json:"userId,omitempty"`
+	Identifier    string     `json:"identifier,omitempty"`
+	IPAddress     string     `json:"ipAddress,omitempty"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"lastFailureAt"`
+	LockedUntil   *time.Time `json:"lockedUntil,omitempty"`
+}
+
+type ClearLockoutRequest struct {
+	Key string `json:"key" validate:"required,max=191"`
+}
+
+// JwtKeyResponse key penandatangan JWT tanpa materi private key
+type JwtKeyResponse struct {
+	Kid       string    `json:"kid"`
+	Algorithm string    `json:"algorithm"`
+	Source    string    `json:"source"`
+	CanSign   bool      `json:"canSign"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+type CreateSeriesRequest struct {
+	Title       string  `json:"title" validate:"required,max=255"`
+	Description *string `json:"description"`
+	// PostIds urutan bagian series, boleh kosong dan diisi belakangan
+	PostIds []int64 `json:"postIds" validate:"max=100"`
+}
+
+type UpdateSeriesRequest struct {
+	Title       *string `json:"title" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+}
+
+// SetSeriesPostsRequest mengganti seluruh isi series sesuai urutan PostIds
+type SetSeriesPostsRequest struct {
+	PostIds []int64 `json:"postIds" validate:"max=100"`
+}
+
+type SeriesFilterRequest struct {
+	AuthorID int `json:"authorId" query:"author_id"`
+	PaginationParams
+}
+
+type SeriesResponse struct {
+	Id          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	Description *string   `json:"description,omitempty"`
+	AuthorId    int64     `json:"authorId"`
+	AuthorName  string    `json:"authorName"`
+	PostCount   int64     `json:"postCount"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type SeriesPartResponse struct {
+	Position    int        `json:"position"`
+	PostId      int64      `json:"postId"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Status      int        `json:"status"`
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+	// IsRead dari saved_posts.is_read milik user yang login, selalu false untuk anonim
+	IsRead bool `json:"isRead"`
+}
+
+type SeriesDetailResponse struct {
+	SeriesResponse
+	Parts     []SeriesPartResponse `json:"parts"`
+	ReadCount int                  `json:"readCount"`
+	// Progress persentase bagian yang sudah dibaca, 0-100
+	Progress int `json:"progress"`
+}
+
+type SeriesPostLink struct {
+	Id    int64  `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// PostSeriesInfo posisi post di dalam series untuk halaman detail post
+type PostSeriesInfo struct {
+	Id       int64           `json:"id"`
+	Title    string          `json:"title"`
+	Slug     string          `json:"slug"`
+	Position int             `json:"position"`
+	Total    int             `json:"total"`
+	Previous *SeriesPostLink `json:"previous,omitempty"`
+	Next     *SeriesPostLink `json:"next,omitempty"`
+}
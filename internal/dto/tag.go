@@ -0,0 +1,8 @@
+package dto
+
+type TagResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	PostCount int64  `json:"postCount,omitempty"`
+}
@@ -1,14 +1,19 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/models"
+)
 
 type UserResponse struct {
-	Id       int    `json:"id"`
-	Name     string `json:"name" validate:"required, min=1,max=100"`
-	Username string `json:"username" validate:"required"`
-	Email    string `json:"email" validate:"required, email"`
-	Bio      string `json:"bio"`
-	Role     int    `json:"role"`
+	Id              int     `json:"id"`
+	Name            string  `json:"name" validate:"required, min=1,max=100"`
+	Username        string  `json:"username" validate:"required"`
+	Email           string  `json:"email" validate:"required, email"`
+	Bio             string  `json:"bio"`
+	ProfileImageURI *string `json:"profileImageUri" gorm:"column:profile_image_uri"`
+	Role            int     `json:"role"`
 }
 
 type UserRequest struct {
@@ -37,3 +42,48 @@ type UserFollowingDTO struct {
 	Bio             *string   `json:"bio"`
 	FollowedAt      time.Time `json:"followed_at"`
 }
+
+type SocialLinksRequest struct {
+	Website   string `json:"website" validate:"omitempty,url,max=255"`
+	Twitter   string `json:"twitter" validate:"omitempty,url,max=255"`
+	Github    string `json:"github" validate:"omitempty,url,max=255"`
+	Linkedin  string `json:"linkedin" validate:"omitempty,url,max=255"`
+	Instagram string `json:"instagram" validate:"omitempty,url,max=255"`
+	Youtube   string `json:"youtube" validate:"omitempty,url,max=255"`
+}
+
+type UpdateProfileRequest struct {
+	Name        string              `json:"name" validate:"required,min=1,max=150"`
+	Bio         string              `json:"bio" validate:"max=500"`
+	SocialLinks *SocialLinksRequest `json:"socialLinks"`
+}
+
+// BanUsersRequest dipakai endpoint PUT /users/deactive
+type BanUsersRequest struct {
+	Ids []int `json:"ids" validate:"required,min=1,max=100,dive,gt=0"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=30"`
+}
+
+// UserProfileResponse profil publik, tanpa email
+type UserProfileResponse struct {
+	Id                int64               `json:"id"`
+	Name              string              `json:"name"`
+	Username          string              `json:"username"`
+	Bio               *string             `json:"bio,omitempty"`
+	ProfileImageURI   *string             `json:"profileImageUri,omitempty"`
+	SocialLinks       *models.SocialLinks `json:"socialLinks,omitempty"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	UsernameChangedAt *time.Time          `json:"usernameChangedAt,omitempty"`
+}
+
+// UserBlockResponse user yang diblokir/di-mute, CreatedAt waktu block/mute dibuat
+type UserBlockResponse struct {
+	Id              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Username        string    `json:"username"`
+	ProfileImageURI *string   `json:"profileImageUri"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
@@ -0,0 +1,18 @@
+package enum
+
+// CategoryDeleteMode reparent memindahkan sub kategori dan post ke parent, cascade menghapus seluruh subtree
+type CategoryDeleteMode string
+
+const (
+	CategoryDeleteReparent CategoryDeleteMode = "reparent"
+	CategoryDeleteCascade  CategoryDeleteMode = "cascade"
+)
+
+func IsValidCategoryDeleteMode(mode CategoryDeleteMode) bool {
+	switch mode {
+	case CategoryDeleteReparent, CategoryDeleteCascade:
+		return true
+	default:
+		return false
+	}
+}
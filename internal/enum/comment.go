@@ -0,0 +1,19 @@
+package enum
+
+type CommentStatus int8
+
+const (
+	CommentStatusInactive    CommentStatus = iota // 0
+	CommentStatusActive                           // 1
+	CommentStatusDeleted                          // 2
+	CommentStatusRejected                         // 3
+	CommentStatusUnconfirmed                      // 4, komentar tamu yang emailnya belum dikonfirmasi
+)
+
+type ModerationMode string
+
+const (
+	ModerationOff       ModerationMode = "off"
+	ModerationFirstTime ModerationMode = "first_time"
+	ModerationAll       ModerationMode = "all"
+)
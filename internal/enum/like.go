@@ -0,0 +1,17 @@
+package enum
+
+type LikeTarget int8
+
+const (
+	LikeTargetPost    LikeTarget = iota + 1 // 1
+	LikeTargetComment                       // 2
+)
+
+func IsValidLikeTarget(target LikeTarget) bool {
+	switch target {
+	case LikeTargetPost, LikeTargetComment:
+		return true
+	default:
+		return false
+	}
+}
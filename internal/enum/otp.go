@@ -0,0 +1,7 @@
+package enum
+
+type OtpPurpose string
+
+const (
+	OtpPurposeEmailVerification OtpPurpose = "email_verification"
+)
@@ -0,0 +1,18 @@
+package enum
+
+// Permission hak akses yang dipetakan ke role di package policy.
+// Permission berakhiran ".any" berlaku untuk resource milik user lain; pemilik resource tidak membutuhkannya.
+type Permission string
+
+const (
+	PermissionPostPublish      Permission = "post.publish"
+	PermissionPostEditAny      Permission = "post.edit.any"
+	PermissionPostDeleteAny    Permission = "post.delete.any"
+	PermissionPostArchiveAny   Permission = "post.archive.any"
+	PermissionCommentModerate  Permission = "comment.moderate"
+	PermissionCommentDeleteAny Permission = "comment.delete.any"
+	PermissionCategoryManage   Permission = "category.manage"
+	PermissionUserManage       Permission = "user.manage"
+	PermissionUserBan          Permission = "user.ban"
+	PermissionSecurityManage   Permission = "security.manage"
+)
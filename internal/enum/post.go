@@ -0,0 +1,37 @@
+package enum
+
+type PostStatus int
+
+const (
+	PostStatusInactive  PostStatus = iota // 0
+	PostStatusDraft                       // 1
+	PostStatusReview                      // 2
+	PostStatusPublished                   // 3
+	PostStatusArchived                    // 4
+)
+
+func IsValidPostStatus(status PostStatus) bool {
+	switch status {
+	case PostStatusInactive, PostStatusDraft, PostStatusReview, PostStatusPublished, PostStatusArchived:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s PostStatus) String() string {
+	switch s {
+	case PostStatusInactive:
+		return "inactive"
+	case PostStatusDraft:
+		return "draft"
+	case PostStatusReview:
+		return "review"
+	case PostStatusPublished:
+		return "published"
+	case PostStatusArchived:
+		return "archived"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,30 @@
+package enum
+
+type SecurityEvent string
+
+const (
+	SecurityEventLoginFailed    SecurityEvent = "login_failed"
+	SecurityEventLoginBlocked   SecurityEvent = "login_blocked"
+	SecurityEventLoginLocked    SecurityEvent = "login_locked"
+	SecurityEventLockoutCleared SecurityEvent = "lockout_cleared"
+
+	SecurityEventTwoFactorFailed  SecurityEvent = "two_factor_failed"
+	SecurityEventTwoFactorBlocked SecurityEvent = "two_factor_blocked"
+	SecurityEventTwoFactorLocked  SecurityEvent = "two_factor_locked"
+	SecurityEventJwtKeyRotated    SecurityEvent = "jwt_key_rotated"
+
+	SecurityEventAccountDeletionRequested SecurityEvent = "account_deletion_requested"
+	SecurityEventAccountDeletionCancelled SecurityEvent = "account_deletion_cancelled"
+	SecurityEventAccountDeleted           SecurityEvent = "account_deleted"
+)
+
+// LoginAttemptScope jenis counter login gagal
+type LoginAttemptScope string
+
+const (
+	LoginAttemptScopeAccount LoginAttemptScope = "account"
+	LoginAttemptScopeIP      LoginAttemptScope = "ip"
+	// LoginAttemptScopeTwoFactor kode 2FA salah per user, LoginAttemptScopeMfaToken per token mfa_pending (jti)
+	LoginAttemptScopeTwoFactor LoginAttemptScope = "2fa"
+	LoginAttemptScopeMfaToken  LoginAttemptScope = "mfa"
+)
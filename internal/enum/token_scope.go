@@ -0,0 +1,22 @@
+package enum
+
+// TokenScope hak akses personal access token, JWT login biasa selalu punya semua scope
+type TokenScope string
+
+const (
+	ScopePostsRead        TokenScope = "posts:read"
+	ScopePostsWrite       TokenScope = "posts:write"
+	ScopePostsReview      TokenScope = "posts:review"
+	ScopeCommentsWrite    TokenScope = "comments:write"
+	ScopeCommentsModerate TokenScope = "comments:moderate"
+	ScopeCategoriesWrite  TokenScope = "categories:write"
+)
+
+func IsValidTokenScope(scope TokenScope) bool {
+	switch scope {
+	case ScopePostsRead, ScopePostsWrite, ScopePostsReview, ScopeCommentsWrite, ScopeCommentsModerate, ScopeCategoriesWrite:
+		return true
+	default:
+		return false
+	}
+}
@@ -17,3 +17,31 @@ func IsValidRole(role UserRole) bool {
 		return false
 	}
 }
+
+type UserStatus int
+
+const (
+	UserStatusInactive UserStatus = iota // 0
+	UserStatusActive                     // 1
+	UserStatusArchived                   // 2
+	UserStatusBanned                     // 3
+	UserStatusDeleted                    // 4, akun yang sudah dihapus dan dianonimkan
+)
+
+// DataExportStatus status job export data pribadi
+type DataExportStatus string
+
+const (
+	DataExportPending    DataExportStatus = "pending"
+	DataExportProcessing DataExportStatus = "processing"
+	DataExportReady      DataExportStatus = "ready"
+	DataExportFailed     DataExportStatus = "failed"
+)
+
+// UserBlockType block memutus interaksi dua arah, mute hanya menyembunyikan konten di sisi user yang me-mute
+type UserBlockType string
+
+const (
+	UserBlockTypeBlock UserBlockType = "block"
+	UserBlockTypeMute  UserBlockType = "mute"
+)
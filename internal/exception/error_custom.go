@@ -15,6 +15,8 @@ type ErrorCustom struct {
 	Errors  []ValidationErrorItem `json:"errors,omitempty"`
 	Status  int                   `json:"status"`
 	Code    string                `json:"code"`
+	// RetryAfter detik sampai request boleh diulang, dikirim juga sebagai header Retry-After
+	RetryAfter int `json:"retryAfter,omitempty"`
 }
 
 func (e *ErrorCustom) Error() string {
@@ -39,6 +41,7 @@ const (
 	ERR_DB              = "DB_ERROR"
 	ERR_UNHANDLE        = "INTERNAL_SERVER_ERROR"
 	ERR_COMMON          = "COMMON_ERR"
+	ERR_TOO_MANY        = "TOO_MANY_REQUESTS_ERROR"
 )
 
 func NewBusnissLogicErr(message string) *ErrorCustom {
@@ -73,6 +76,15 @@ func NewForbiddenErr(message string) *ErrorCustom {
 	}
 }
 
+func NewTooManyRequestsErr(message string, retryAfter int) *ErrorCustom {
+	return &ErrorCustom{
+		Code:       ERR_TOO_MANY,
+		Message:    message,
+		Status:     fiber.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+	}
+}
+
 func NewNotFoundErr(message string) *ErrorCustom {
 	return &ErrorCustom{
 		Code:    ERR_NOT_FOUND,
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccountHandler interface {
+	RequestMyExport(c *fiber.Ctx) error
+	DownloadMyExport(c *fiber.Ctx) error
+	DeleteMyAccount(c *fiber.Ctx) error
+	CancelMyDeletion(c *fiber.Ctx) error
+}
+
+type AccountHandlerImpl struct {
+	AccountService services.AccountService
+}
+
+func NewAccountHandler(accountService services.AccountService) AccountHandler {
+	return &AccountHandlerImpl{
+		AccountService: accountService,
+	}
+}
+
+func (h *AccountHandlerImpl) RequestMyExport(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.AccountService.RequestExport(*userDetail)
+	if err != nil {
+		return err
+	}
+
+	// export dibuat di background, client cukup polling endpoint ini sampai status ready
+	if enum.DataExportStatus(response.Status) != enum.DataExportReady {
+		return c.Status(fiber.StatusAccepted).JSON(dto.CommonResponseSuccess{
+			Data:    response,
+			Status:  fiber.StatusAccepted,
+			Message: "Data export is being prepared",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Data export is ready",
+	})
+}
+
+func (h *AccountHandlerImpl) DownloadMyExport(c *fiber.Ctx) error {
+	exportId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid export ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	filePath, filename, err := h.AccountService.FindExportFile(exportId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Download(filePath, filename)
+}
+
+func (h *AccountHandlerImpl) DeleteMyAccount(c *fiber.Ctx) error {
+	var req dto.DeleteAccountRequest
+
+	// body opsional untuk akun tanpa password
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return exception.NewBadRequestErr("Invalid request body")
+		}
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.AccountService.RequestDeletion(req, *userDetail, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusAccepted,
+		Message: "Account is scheduled for deletion, you can cancel it before the scheduled time",
+	})
+}
+
+func (h *AccountHandlerImpl) CancelMyDeletion(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.AccountService.CancelDeletion(*userDetail, sessionMeta(c)); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully cancel account deletion",
+	})
+}
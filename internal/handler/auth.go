@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"strconv"
 
 	"github.com/MrBista/blog-api/internal/dto"
 	"github.com/MrBista/blog-api/internal/exception"
@@ -14,17 +15,35 @@ type AuthHandler interface {
 	LoginUser(c *fiber.Ctx) error
 	RegisterUser(c *fiber.Ctx) error
 	ConfirmOtp(c *fiber.Ctx) error
-	GetGoogleAuthURL(c *fiber.Ctx) error
-	GoogleCallback(c *fiber.Ctx) error
+	ResendOtp(c *fiber.Ctx) error
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+	ChangePassword(c *fiber.Ctx) error
+	RefreshToken(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+	FindMySessions(c *fiber.Ctx) error
+	RevokeMySession(c *fiber.Ctx) error
+	VerifyTwoFactorLogin(c *fiber.Ctx) error
+	SetupPendingTwoFactor(c *fiber.Ctx) error
+	EnablePendingTwoFactor(c *fiber.Ctx) error
 }
 
 type AuthHandlerImpl struct {
-	AuthService services.AuthService
+	AuthService    services.AuthService
+	SessionService services.SessionService
 }
 
-func NewAuthHandler(authService services.AuthService) AuthHandler {
+func NewAuthHandler(authService services.AuthService, sessionService services.SessionService) AuthHandler {
 	return &AuthHandlerImpl{
-		AuthService: authService,
+		AuthService:    authService,
+		SessionService: sessionService,
+	}
+}
+
+func sessionMeta(c *fiber.Ctx) dto.SessionMeta {
+	return dto.SessionMeta{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IPAddress: c.IP(),
 	}
 }
 
@@ -43,7 +62,7 @@ func (h *AuthHandlerImpl) LoginUser(c *fiber.Ctx) error {
 		return exception.NewValidationErr(err)
 	}
 
-	responseLogin, err := h.AuthService.LoginUser(loginReq)
+	responseLogin, err := h.AuthService.LoginUser(loginReq, sessionMeta(c))
 
 	if err != nil {
 		return err
@@ -56,22 +75,38 @@ func (h *AuthHandlerImpl) LoginUser(c *fiber.Ctx) error {
 	})
 }
 
-func (h *AuthHandlerImpl) GetGoogleAuthURL(c *fiber.Ctx) error {
-	state := utils.GenerateRandomString(32)
-	url := h.AuthService.GetGoogleAuthURL(state)
+func (h *AuthHandlerImpl) RegisterUser(c *fiber.Ctx) error {
+	var userReq dto.RegisterRequest
+
+	body := c.Body()
+
+	if err := json.Unmarshal(body, &userReq); err != nil {
+		return err
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&userReq); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	err := h.AuthService.RegisterUser(userReq)
+	if err != nil {
+		return err
+	}
 
-	return c.JSON(dto.GoogleAuthURLResponse{
-		URL: url,
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusCreated,
+		Message: "Successfully register user, please check your email for the verification code",
 	})
 }
 
-func (h *AuthHandlerImpl) GoogleCallback(c *fiber.Ctx) error {
-	var req dto.GoogleCallbackRequest
+func (h *AuthHandlerImpl) ConfirmOtp(c *fiber.Ctx) error {
+	var req dto.VerifyOtpRequest
 
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+		return exception.NewBadRequestErr("Invalid request body")
 	}
 
 	validator := utils.GetValidator()
@@ -80,7 +115,55 @@ func (h *AuthHandlerImpl) GoogleCallback(c *fiber.Ctx) error {
 		return exception.NewValidationErr(err)
 	}
 
-	response, err := h.AuthService.HandleGoogleCallback(req.Code)
+	if err := h.AuthService.ConfirmOtp(req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully verify email",
+	})
+}
+
+func (h *AuthHandlerImpl) ResendOtp(c *fiber.Ctx) error {
+	var req dto.ResendOtpRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	if err := h.AuthService.ResendOtp(req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "If the email is registered and not verified yet, a new code has been sent",
+	})
+}
+
+func (h *AuthHandlerImpl) RefreshToken(c *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	response, err := h.SessionService.Refresh(req.RefreshToken, sessionMeta(c))
 	if err != nil {
 		return err
 	}
@@ -88,37 +171,215 @@ func (h *AuthHandlerImpl) GoogleCallback(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
 		Data:    response,
 		Status:  fiber.StatusOK,
-		Message: "Successfully login",
+		Message: "Successfully refresh token",
 	})
 }
 
-func (h *AuthHandlerImpl) RegisterUser(c *fiber.Ctx) error {
-	var userReq dto.RegisterRequest
+func (h *AuthHandlerImpl) Logout(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
 
-	body := c.Body()
+	if err := h.SessionService.Logout(*userDetail); err != nil {
+		return err
+	}
 
-	if err := json.Unmarshal(body, &userReq); err != nil {
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully logout",
+	})
+}
+
+func (h *AuthHandlerImpl) FindMySessions(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.SessionService.FindMySessions(*userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    sessions,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get sessions",
+	})
+}
+
+func (h *AuthHandlerImpl) RevokeMySession(c *fiber.Ctx) error {
+	sessionId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid session ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.SessionService.RevokeMySession(sessionId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully revoke session",
+	})
+}
+
+func (h *AuthHandlerImpl) ForgotPassword(c *fiber.Ctx) error {
+	var req dto.ForgotPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	if err := h.AuthService.ForgotPassword(req); err != nil {
 		return err
 	}
 
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "If the email is registered, a reset link has been sent",
+	})
+}
+
+func (h *AuthHandlerImpl) ResetPassword(c *fiber.Ctx) error {
+	var req dto.ResetPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
 	validator := utils.GetValidator()
 
-	if err := validator.Struct(&userReq); err != nil {
+	if err := validator.Struct(&req); err != nil {
 		return exception.NewValidationErr(err)
 	}
 
-	err := h.AuthService.RegisterUser(userReq)
+	if err := h.AuthService.ResetPassword(req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully reset password",
+	})
+}
+
+func (h *AuthHandlerImpl) ChangePassword(c *fiber.Ctx) error {
+	var req dto.ChangePasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+	if err := h.AuthService.ChangePassword(req, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
 		Data:    true,
-		Status:  fiber.StatusCreated,
-		Message: "Successfully register user",
+		Status:  fiber.StatusOK,
+		Message: "Successfully change password",
 	})
 }
 
-func (h *AuthHandlerImpl) ConfirmOtp(c *fiber.Ctx) error {
-	panic("not implemented") // TODO: Implement
+func (h *AuthHandlerImpl) VerifyTwoFactorLogin(c *fiber.Ctx) error {
+	var req dto.TwoFactorVerifyRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	response, err := h.AuthService.VerifyTwoFactorLogin(req, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully login",
+	})
+}
+
+func (h *AuthHandlerImpl) SetupPendingTwoFactor(c *fiber.Ctx) error {
+	var req dto.TwoFactorPendingRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	response, err := h.AuthService.SetupPendingTwoFactor(req, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully setup two factor authentication, confirm it with a code from your authenticator app",
+	})
+}
+
+func (h *AuthHandlerImpl) EnablePendingTwoFactor(c *fiber.Ctx) error {
+	var req dto.TwoFactorPendingEnableRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	response, err := h.AuthService.EnablePendingTwoFactor(req, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully enable two factor authentication",
+	})
 }
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/MrBista/blog-api/internal/utils"
@@ -17,6 +18,11 @@ type CategoryHandler interface {
 	CreateCategory(c *fiber.Ctx) error
 	UpdateCategory(c *fiber.Ctx) error
 	DeleteCategory(c *fiber.Ctx) error
+	FindCategoryTree(c *fiber.Ctx) error
+	MoveCategory(c *fiber.Ctx) error
+	FollowCategory(c *fiber.Ctx) error
+	UnfollowCategory(c *fiber.Ctx) error
+	FindMyCategories(c *fiber.Ctx) error
 }
 
 type CategoryHandlerImpl struct {
@@ -149,7 +155,10 @@ func (h *CategoryHandlerImpl) DeleteCategory(c *fiber.Ctx) error {
 		return exception.NewBadRequestErr(err.Error())
 	}
 
-	err = h.CategoryService.DeleteById(valId)
+	// mode=reparent (default) memindahkan sub kategori ke parent, mode=cascade menghapus seluruh subtree
+	mode := enum.CategoryDeleteMode(c.Query("mode", string(enum.CategoryDeleteReparent)))
+
+	err = h.CategoryService.DeleteById(valId, mode)
 	if err != nil {
 		return err
 	}
@@ -160,3 +169,101 @@ func (h *CategoryHandlerImpl) DeleteCategory(c *fiber.Ctx) error {
 		Status:  fiber.StatusOK,
 	})
 }
+
+func (h *CategoryHandlerImpl) FindCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.CategoryService.FindTree()
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    tree,
+		Message: "Successfully get category tree",
+		Status:  fiber.StatusOK,
+	})
+}
+
+func (h *CategoryHandlerImpl) MoveCategory(c *fiber.Ctx) error {
+	var req dto.MoveCategoryRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	valId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return exception.NewBadRequestErr(err.Error())
+	}
+
+	if err := h.CategoryService.MoveCategory(valId, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Message: "Successfully move category",
+		Status:  fiber.StatusOK,
+	})
+}
+
+func (h *CategoryHandlerImpl) FollowCategory(c *fiber.Ctx) error {
+	valId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid category ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.CategoryService.FollowCategory(valId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Message: "Successfully followed category",
+		Status:  fiber.StatusCreated,
+	})
+}
+
+func (h *CategoryHandlerImpl) UnfollowCategory(c *fiber.Ctx) error {
+	valId, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid category ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.CategoryService.UnfollowCategory(valId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Message: "Successfully unfollowed category",
+		Status:  fiber.StatusOK,
+	})
+}
+
+func (h *CategoryHandlerImpl) FindMyCategories(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	categories, err := h.CategoryService.FindFollowedCategories(*userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    categories,
+		Message: "Successfully retrieved followed categories",
+		Status:  fiber.StatusOK,
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/MrBista/blog-api/internal/utils"
@@ -14,6 +15,12 @@ import (
 type CommentHandler interface {
 	FindAllComment(c *fiber.Ctx) error
 	CreateComment(c *fiber.Ctx) error
+	FindCommentTree(c *fiber.Ctx) error
+	FindReplies(c *fiber.Ctx) error
+	UpdateComment(c *fiber.Ctx) error
+	DeleteComment(c *fiber.Ctx) error
+	CreateGuestComment(c *fiber.Ctx) error
+	ConfirmGuestComment(c *fiber.Ctx) error
 }
 
 type CommentHandlerImpl struct {
@@ -43,13 +50,13 @@ func (h *CommentHandlerImpl) FindAllComment(c *fiber.Ctx) error {
 		},
 	}
 
-	userDetail, err := utils.GetUserClaims(c)
-
-	if err != nil {
-		return err
+	// pembaca anonim tetap bisa melihat komentar, likedByMe selalu false
+	var viewer utils.Claims
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		viewer = *userDetail
 	}
 
-	data, err := h.CommentService.FindAllCommentByPostId(filter, *userDetail)
+	data, err := h.CommentService.FindAllCommentByPostId(filter, viewer)
 
 	if err != nil {
 		return err
@@ -92,9 +99,210 @@ func (h *CommentHandlerImpl) CreateComment(c *fiber.Ctx) error {
 		return err
 	}
 
+	message := "Successfully create comment"
+	if data.Status == int8(enum.CommentStatusInactive) {
+		message = "Comment is awaiting moderation"
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
 		Data:    data,
 		Status:  fiber.StatusCreated,
-		Message: "Successfully create comment",
+		Message: message,
+	})
+}
+
+func (h *CommentHandlerImpl) FindCommentTree(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	sort := c.Query("sort", "created_at desc")
+	postId, _ := strconv.Atoi(c.Params("postId"))
+	depth, _ := strconv.Atoi(c.Query("depth"))
+	replyLimit, _ := strconv.Atoi(c.Query("reply_limit"))
+
+	filter := dto.CommentTreeFilterRequest{
+		PostId:     postId,
+		Depth:      depth,
+		ReplyLimit: replyLimit,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	var viewer utils.Claims
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		viewer = *userDetail
+	}
+
+	data, err := h.CommentService.FindCommentTree(filter, viewer)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get comment tree",
+	})
+}
+
+func (h *CommentHandlerImpl) FindReplies(c *fiber.Ctx) error {
+	postId, _ := strconv.Atoi(c.Params("postId"))
+	commentId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid comment id")
+	}
+	cursor, _ := strconv.ParseInt(c.Query("cursor", "0"), 10, 64)
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	depth, _ := strconv.Atoi(c.Query("depth"))
+	replyLimit, _ := strconv.Atoi(c.Query("reply_limit"))
+
+	filter := dto.CommentRepliesFilterRequest{
+		PostId:     postId,
+		ParentId:   commentId,
+		Cursor:     cursor,
+		Limit:      limit,
+		Depth:      depth,
+		ReplyLimit: replyLimit,
+	}
+
+	var viewer utils.Claims
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		viewer = *userDetail
+	}
+
+	data, err := h.CommentService.FindReplies(filter, viewer)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get comment replies",
+	})
+}
+
+func (h *CommentHandlerImpl) UpdateComment(c *fiber.Ctx) error {
+	var body dto.UpdateCommentRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+	if err := validator.Struct(&body); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	postId, err := strconv.ParseInt(c.Params("postId"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid post id")
+	}
+	commentId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid comment id")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.CommentService.UpdateComment(postId, commentId, body, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully update comment",
+	})
+}
+
+func (h *CommentHandlerImpl) DeleteComment(c *fiber.Ctx) error {
+	postId, err := strconv.ParseInt(c.Params("postId"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid post id")
+	}
+	commentId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid comment id")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.CommentService.DeleteComment(postId, commentId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully delete comment",
+	})
+}
+
+func (h *CommentHandlerImpl) CreateGuestComment(c *fiber.Ctx) error {
+	var body dto.GuestCommentRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+	if err := validator.Struct(&body); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	postId, err := strconv.Atoi(c.Params("postId"))
+	if err != nil {
+		return exception.NewBadRequestErr("invalid post id")
+	}
+
+	body.PostId = postId
+	body.IPAddress = c.IP()
+
+	if _, err := h.CommentService.CreateGuestComment(body); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusAccepted,
+		Message: "Please check your email to confirm your comment",
+	})
+}
+
+func (h *CommentHandlerImpl) ConfirmGuestComment(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return exception.NewBadRequestErr("token is required")
+	}
+
+	data, err := h.CommentService.ConfirmGuestComment(token)
+	if err != nil {
+		return err
+	}
+
+	message := "Successfully confirm comment"
+	if data.Status == int8(enum.CommentStatusInactive) {
+		message = "Comment confirmed and is awaiting moderation"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data: fiber.Map{
+			"id":     data.ID,
+			"postId": data.PostID,
+			"status": data.Status,
+		},
+		Status:  fiber.StatusOK,
+		Message: message,
 	})
 }
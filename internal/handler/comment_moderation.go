@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CommentModerationHandler interface {
+	FindQueue(c *fiber.Ctx) error
+	ApproveComment(c *fiber.Ctx) error
+	RejectComment(c *fiber.Ctx) error
+	BulkModerate(c *fiber.Ctx) error
+}
+
+type CommentModerationHandlerImpl struct {
+	CommentModerationService services.CommentModerationService
+}
+
+func NewCommentModerationHandler(commentModerationService services.CommentModerationService) CommentModerationHandler {
+	return &CommentModerationHandlerImpl{
+		CommentModerationService: commentModerationService,
+	}
+}
+
+func (h *CommentModerationHandlerImpl) FindQueue(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+	sort := c.Query("sort", "comments.created_at asc")
+	status, _ := strconv.Atoi(c.Query("status", "0"))
+	postId, _ := strconv.Atoi(c.Query("post_id"))
+
+	filter := dto.CommentModerationFilterRequest{
+		Status: status,
+		PostId: postId,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	datas, err := h.CommentModerationService.FindQueue(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get moderation queue",
+	})
+}
+
+func (h *CommentModerationHandlerImpl) moderate(c *fiber.Ctx, action services.CommentModerationAction, message string) error {
+	commentId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("invalid comment id")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.CommentModerationService.Moderate(commentId, action, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: message,
+	})
+}
+
+func (h *CommentModerationHandlerImpl) ApproveComment(c *fiber.Ctx) error {
+	return h.moderate(c, services.CommentModerationApprove, "Successfully approve comment")
+}
+
+func (h *CommentModerationHandlerImpl) RejectComment(c *fiber.Ctx) error {
+	return h.moderate(c, services.CommentModerationReject, "Successfully reject comment")
+}
+
+func (h *CommentModerationHandlerImpl) BulkModerate(c *fiber.Ctx) error {
+	var body dto.CommentModerationBulkRequest
+
+	if err := c.BodyParser(&body); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+	if err := validator.Struct(&body); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.CommentModerationService.BulkModerate(body, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully moderate comments",
+	})
+}
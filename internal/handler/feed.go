@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedHandler interface {
+	FindMyFeed(c *fiber.Ctx) error
+}
+
+type FeedHandlerImpl struct {
+	FeedService services.FeedService
+}
+
+func NewFeedHandler(feedService services.FeedService) FeedHandler {
+	return &FeedHandlerImpl{
+		FeedService: feedService,
+	}
+}
+
+func (h *FeedHandlerImpl) FindMyFeed(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	filter := dto.FeedFilterRequest{
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.FeedService.FindMyFeed(filter, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully retrieved feed",
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LikeHandler interface {
+	LikePost(c *fiber.Ctx) error
+	UnlikePost(c *fiber.Ctx) error
+	LikeComment(c *fiber.Ctx) error
+	UnlikeComment(c *fiber.Ctx) error
+	FindMyLikes(c *fiber.Ctx) error
+}
+
+type LikeHandlerImpl struct {
+	LikeService services.LikeService
+}
+
+func NewLikeHandler(likeService services.LikeService) LikeHandler {
+	return &LikeHandlerImpl{
+		LikeService: likeService,
+	}
+}
+
+func (h *LikeHandlerImpl) LikePost(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.LikeService.LikePost(c.Params("slug"), *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully like post",
+	})
+}
+
+func (h *LikeHandlerImpl) UnlikePost(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.LikeService.UnlikePost(c.Params("slug"), *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully unlike post",
+	})
+}
+
+func (h *LikeHandlerImpl) parseCommentParams(c *fiber.Ctx) (int64, int64, error) {
+	postId, err := strconv.ParseInt(c.Params("postId"), 10, 64)
+	if err != nil {
+		return 0, 0, exception.NewBadRequestErr("invalid post id")
+	}
+
+	commentId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, exception.NewBadRequestErr("invalid comment id")
+	}
+
+	return postId, commentId, nil
+}
+
+func (h *LikeHandlerImpl) LikeComment(c *fiber.Ctx) error {
+	postId, commentId, err := h.parseCommentParams(c)
+	if err != nil {
+		return err
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.LikeService.LikeComment(postId, commentId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully like comment",
+	})
+}
+
+func (h *LikeHandlerImpl) UnlikeComment(c *fiber.Ctx) error {
+	postId, commentId, err := h.parseCommentParams(c)
+	if err != nil {
+		return err
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.LikeService.UnlikeComment(postId, commentId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully unlike comment",
+	})
+}
+
+func (h *LikeHandlerImpl) FindMyLikes(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	sort := c.Query("sort", "likes.created_at desc")
+
+	filter := dto.LikeFilterRequest{
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	switch c.Query("type") {
+	case "post":
+		filter.TargetType = int(enum.LikeTargetPost)
+	case "comment":
+		filter.TargetType = int(enum.LikeTargetComment)
+	case "":
+	default:
+		return exception.NewBadRequestErr("type must be post or comment")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.LikeService.FindMyLikes(filter, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    data,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get my likes",
+	})
+}
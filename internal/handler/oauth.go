@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// oauthStateCookie menyimpan state yang ditandatangani, dicocokkan dengan state dari callback
+const oauthStateCookie = "oauth_state"
+
+type OAuthHandler interface {
+	ListProviders(c *fiber.Ctx) error
+	GetAuthURL(c *fiber.Ctx) error
+	Callback(c *fiber.Ctx) error
+	FindMyIdentities(c *fiber.Ctx) error
+	GetLinkURL(c *fiber.Ctx) error
+	LinkCallback(c *fiber.Ctx) error
+	UnlinkIdentity(c *fiber.Ctx) error
+}
+
+type OAuthHandlerImpl struct {
+	OAuthService services.OAuthService
+	StateTTL     time.Duration
+}
+
+func NewOAuthHandler(oauthService services.OAuthService, stateTTL time.Duration) OAuthHandler {
+	return &OAuthHandlerImpl{
+		OAuthService: oauthService,
+		StateTTL:     stateTTL,
+	}
+}
+
+// providerName route lama /auth/google/* tidak punya param :provider
+func providerName(c *fiber.Ctx) string {
+	if provider := c.Params("provider"); provider != "" {
+		return provider
+	}
+	return "google"
+}
+
+func (h *OAuthHandlerImpl) setStateCookie(c *fiber.Ctx, value string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Value:    value,
+		Path:     "/api",
+		MaxAge:   int(h.StateTTL.Seconds()),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+func (h *OAuthHandlerImpl) clearStateCookie(c *fiber.Ctx) {
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Value:    "",
+		Path:     "/api",
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
+
+func parseOAuthCallback(c *fiber.Ctx) (dto.OAuthCallbackRequest, error) {
+	var req dto.OAuthCallbackRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return req, exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return req, exception.NewValidationErr(err)
+	}
+
+	return req, nil
+}
+
+func (h *OAuthHandlerImpl) ListProviders(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    h.OAuthService.ListProviders(),
+		Status:  fiber.StatusOK,
+		Message: "Successfully get login providers",
+	})
+}
+
+func (h *OAuthHandlerImpl) GetAuthURL(c *fiber.Ctx) error {
+	url, stateCookie, err := h.OAuthService.BeginAuth(providerName(c), 0)
+	if err != nil {
+		return err
+	}
+
+	h.setStateCookie(c, stateCookie)
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    dto.OAuthURLResponse{URL: url},
+		Status:  fiber.StatusOK,
+		Message: "Successfully get login url",
+	})
+}
+
+func (h *OAuthHandlerImpl) Callback(c *fiber.Ctx) error {
+	req, err := parseOAuthCallback(c)
+	if err != nil {
+		return err
+	}
+
+	stateCookie := c.Cookies(oauthStateCookie)
+	h.clearStateCookie(c)
+
+	response, err := h.OAuthService.HandleLoginCallback(providerName(c), req, stateCookie, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully login",
+	})
+}
+
+func (h *OAuthHandlerImpl) FindMyIdentities(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	identities, err := h.OAuthService.FindMyIdentities(*userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    identities,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get linked accounts",
+	})
+}
+
+func (h *OAuthHandlerImpl) GetLinkURL(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	url, stateCookie, err := h.OAuthService.BeginAuth(providerName(c), int64(userDetail.UserId))
+	if err != nil {
+		return err
+	}
+
+	h.setStateCookie(c, stateCookie)
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    dto.OAuthURLResponse{URL: url},
+		Status:  fiber.StatusOK,
+		Message: "Successfully get link url",
+	})
+}
+
+func (h *OAuthHandlerImpl) LinkCallback(c *fiber.Ctx) error {
+	req, err := parseOAuthCallback(c)
+	if err != nil {
+		return err
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	stateCookie := c.Cookies(oauthStateCookie)
+	h.clearStateCookie(c)
+
+	identity, err := h.OAuthService.HandleLinkCallback(providerName(c), req, stateCookie, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    identity,
+		Status:  fiber.StatusOK,
+		Message: "Successfully link account",
+	})
+}
+
+func (h *OAuthHandlerImpl) UnlinkIdentity(c *fiber.Ctx) error {
+	identityId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid identity ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.OAuthService.UnlinkIdentity(identityId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully unlink account",
+	})
+}
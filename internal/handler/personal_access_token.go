@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PersonalAccessTokenHandler interface {
+	CreateToken(c *fiber.Ctx) error
+	FindMyTokens(c *fiber.Ctx) error
+	RevokeToken(c *fiber.Ctx) error
+}
+
+type PersonalAccessTokenHandlerImpl struct {
+	PersonalAccessTokenService services.PersonalAccessTokenService
+}
+
+func NewPersonalAccessTokenHandler(personalAccessTokenService services.PersonalAccessTokenService) PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandlerImpl{
+		PersonalAccessTokenService: personalAccessTokenService,
+	}
+}
+
+func (h *PersonalAccessTokenHandlerImpl) CreateToken(c *fiber.Ctx) error {
+	var req dto.CreatePersonalAccessTokenRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.PersonalAccessTokenService.Create(req, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusCreated,
+		Message: "Successfully create token, copy it now because it will not be shown again",
+	})
+}
+
+func (h *PersonalAccessTokenHandlerImpl) FindMyTokens(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.PersonalAccessTokenService.FindMine(*userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    tokens,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get tokens",
+	})
+}
+
+func (h *PersonalAccessTokenHandlerImpl) RevokeToken(c *fiber.Ctx) error {
+	tokenId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid token ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.PersonalAccessTokenService.Revoke(tokenId, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully revoke token",
+	})
+}
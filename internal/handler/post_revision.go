@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PostRevisionHandler interface {
+	FindAllRevision(c *fiber.Ctx) error
+	FindDetailRevision(c *fiber.Ctx) error
+	DiffRevision(c *fiber.Ctx) error
+	RestoreRevision(c *fiber.Ctx) error
+}
+
+type PostRevisionHandlerImpl struct {
+	PostRevisionService services.PostRevisionService
+}
+
+func NewPostRevisionHandler(postRevisionService services.PostRevisionService) PostRevisionHandler {
+	return &PostRevisionHandlerImpl{
+		PostRevisionService: postRevisionService,
+	}
+}
+
+func (h *PostRevisionHandlerImpl) FindAllRevision(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := h.PostRevisionService.FindAllRevision(c.Params("slug"), *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    revisions,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get post revisions",
+	})
+}
+
+func (h *PostRevisionHandlerImpl) FindDetailRevision(c *fiber.Ctx) error {
+	revisionId, err := strconv.ParseInt(c.Params("revisionId"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid revision ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	revision, err := h.PostRevisionService.FindDetailRevision(c.Params("slug"), revisionId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    revision,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get detail post revision",
+	})
+}
+
+func (h *PostRevisionHandlerImpl) DiffRevision(c *fiber.Ctx) error {
+	fromId, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid from revision ID")
+	}
+
+	toId, err := strconv.ParseInt(c.Query("to"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid to revision ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	diff, err := h.PostRevisionService.DiffRevision(c.Params("slug"), fromId, toId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    diff,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get diff post revision",
+	})
+}
+
+func (h *PostRevisionHandlerImpl) RestoreRevision(c *fiber.Ctx) error {
+	revisionId, err := strconv.ParseInt(c.Params("revisionId"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid revision ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	revision, err := h.PostRevisionService.RestoreRevision(c.Params("slug"), revisionId, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    revision,
+		Status:  fiber.StatusCreated,
+		Message: "Successfully restore post revision",
+	})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PostWorkflowHandler interface {
+	SubmitPost(c *fiber.Ctx) error
+	ApprovePost(c *fiber.Ctx) error
+	RejectPost(c *fiber.Ctx) error
+	ArchivePost(c *fiber.Ctx) error
+	FindStatusEvents(c *fiber.Ctx) error
+	FindReviewQueue(c *fiber.Ctx) error
+	FindMyReviewHistory(c *fiber.Ctx) error
+}
+
+type PostWorkflowHandlerImpl struct {
+	PostWorkflowService services.PostWorkflowService
+}
+
+func NewPostWorkflowHandler(postWorkflowService services.PostWorkflowService) PostWorkflowHandler {
+	return &PostWorkflowHandlerImpl{
+		PostWorkflowService: postWorkflowService,
+	}
+}
+
+func (h *PostWorkflowHandlerImpl) transition(c *fiber.Ctx, action services.PostAction, message string) error {
+	var reviewBody dto.PostReviewRequest
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&reviewBody); err != nil {
+			return exception.NewBadRequestErr("Invalid request body")
+		}
+	}
+
+	validator := utils.GetValidator()
+	if err := validator.Struct(&reviewBody); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.PostWorkflowService.Transition(c.Params("slug"), action, reviewBody.Comment, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: message,
+	})
+}
+
+func (h *PostWorkflowHandlerImpl) SubmitPost(c *fiber.Ctx) error {
+	return h.transition(c, services.PostActionSubmit, "Successfully submit post for review")
+}
+
+func (h *PostWorkflowHandlerImpl) ApprovePost(c *fiber.Ctx) error {
+	return h.transition(c, services.PostActionApprove, "Successfully approve post")
+}
+
+func (h *PostWorkflowHandlerImpl) RejectPost(c *fiber.Ctx) error {
+	return h.transition(c, services.PostActionReject, "Successfully reject post")
+}
+
+func (h *PostWorkflowHandlerImpl) ArchivePost(c *fiber.Ctx) error {
+	return h.transition(c, services.PostActionArchive, "Successfully archive post")
+}
+
+func (h *PostWorkflowHandlerImpl) FindStatusEvents(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.PostWorkflowService.FindStatusEvents(c.Params("slug"), *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    events,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get post status history",
+	})
+}
+
+func (h *PostWorkflowHandlerImpl) FindReviewQueue(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	sort := c.Query("sort", "posts.updated_at asc")
+	categoryID, _ := strconv.Atoi(c.Query("category_id"))
+	authorID, _ := strconv.Atoi(c.Query("author_id"))
+
+	filter := dto.PostFilterRequest{
+		CategoryID: categoryID,
+		AuthorID:   authorID,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	datas, err := h.PostWorkflowService.FindReviewQueue(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get review queue",
+	})
+}
+
+func (h *PostWorkflowHandlerImpl) FindMyReviewHistory(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+
+	filter := dto.PostStatusEventFilterRequest{
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     "e.created_at desc",
+		},
+	}
+	filter.SetDefaults()
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	datas, err := h.PostWorkflowService.FindMyReviewHistory(filter, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get review history",
+	})
+}
@@ -77,17 +77,21 @@ func (h *PostImpl) GetAllPosts(c *fiber.Ctx) error {
 	// Parse filter parameters
 	title := c.Query("title")
 	categoryID, _ := strconv.Atoi(c.Query("category_id"))
+	includeSubcategories, _ := strconv.Atoi(c.Query("include_subcategories"))
 	authorID, _ := strconv.Atoi(c.Query("author_id"))
 	status, _ := strconv.Atoi(c.Query("status"))
+	tag := c.Query("tag")
 
 	include := c.Query("includes")
 
 	// Create filter params
 	filter := dto.PostFilterRequest{
-		Title:      title,
-		CategoryID: categoryID,
-		AuthorID:   authorID,
-		Status:     status,
+		Title:                title,
+		CategoryID:           categoryID,
+		IncludeSubcategories: includeSubcategories,
+		AuthorID:             authorID,
+		Status:               status,
+		Tag:                  tag,
 		PaginationParams: dto.PaginationParams{
 			Page:     page,
 			PageSize: pageSize,
@@ -113,6 +117,10 @@ func (h *PostImpl) GetAllPosts(c *fiber.Ctx) error {
 			}
 		}
 	}
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		filter.ViewerID = userDetail.UserId
+	}
+
 	utils.Logger.WithFields(logrus.Fields{
 		"filter": filter,
 	}).Info("filter detail posts for users")
@@ -151,6 +159,10 @@ func (h *PostImpl) GetPostBySlug(c *fiber.Ctx) error {
 	filter.IncludeLike = 1
 	filter.IncludeAuthor = 1
 
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		filter.ViewerID = userDetail.UserId
+	}
+
 	utils.Logger.WithFields(logrus.Fields{
 		"filter": filter,
 	}).Info("filter detial post")
@@ -188,19 +200,12 @@ func (h *PostImpl) UpdatePost(c *fiber.Ctx) error {
 
 	updateBody.Slug = slugParam
 
-	valueClaims := c.Locals("users")
-
-	if valueClaims == nil {
-		return exception.NewBadRequestErr("invalid authorization user")
-	}
-
-	userClaim, ok := valueClaims.(*utils.Claims)
-
-	if !ok {
-		return exception.NewBadRequestErr("invalid authorization user.")
+	userClaim, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
 	}
 
-	err := h.PostService.UpdatePost(&updateBody, *userClaim)
+	err = h.PostService.UpdatePost(&updateBody, *userClaim)
 
 	if err != nil {
 		return err
@@ -217,19 +222,12 @@ func (h *PostImpl) UpdatePost(c *fiber.Ctx) error {
 func (h *PostImpl) DeletePost(c *fiber.Ctx) error {
 	slug := c.Params("slug")
 
-	valueClaims := c.Locals("users")
-
-	if valueClaims == nil {
-		return exception.NewBadRequestErr("invalid authorization user")
-	}
-
-	userClaim, ok := valueClaims.(*utils.Claims)
-
-	if !ok {
-		return exception.NewBadRequestErr("invalid authorization user.")
+	userClaim, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
 	}
 
-	err := h.PostService.DeletePost(slug, *userClaim)
+	err = h.PostService.DeletePost(slug, *userClaim)
 
 	if err != nil {
 		return err
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/url"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProfileHandler interface {
+	UpdateMyProfile(c *fiber.Ctx) error
+	UploadMyAvatar(c *fiber.Ctx) error
+	ChangeMyUsername(c *fiber.Ctx) error
+	FindPublicProfile(c *fiber.Ctx) error
+}
+
+type ProfileHandlerImpl struct {
+	ProfileService services.ProfileService
+}
+
+func NewProfileHandler(profileService services.ProfileService) ProfileHandler {
+	return &ProfileHandlerImpl{
+		ProfileService: profileService,
+	}
+}
+
+func (h *ProfileHandlerImpl) UpdateMyProfile(c *fiber.Ctx) error {
+	var req dto.UpdateProfileRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.ProfileService.UpdateProfile(req, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully update profile",
+	})
+}
+
+func (h *ProfileHandlerImpl) UploadMyAvatar(c *fiber.Ctx) error {
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		return exception.NewBadRequestErr("avatar not provided")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.ProfileService.UploadAvatar(file, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully upload avatar",
+	})
+}
+
+func (h *ProfileHandlerImpl) ChangeMyUsername(c *fiber.Ctx) error {
+	var req dto.ChangeUsernameRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.ProfileService.ChangeUsername(req, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully change username",
+	})
+}
+
+func (h *ProfileHandlerImpl) FindPublicProfile(c *fiber.Ctx) error {
+	username, err := url.PathUnescape(c.Params("username"))
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid username")
+	}
+
+	response, redirectTo, err := h.ProfileService.FindPublicProfile(username)
+	if err != nil {
+		return err
+	}
+
+	// link profil lama diarahkan ke username terbaru, sengaja bukan 301 karena username lama
+	// bisa dipakai user lain setelah masa reservasinya habis dan 301 di-cache permanen oleh browser
+	if redirectTo != "" {
+		return c.Redirect("/api/users/@"+url.PathEscape(redirectTo), fiber.StatusFound)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get profile",
+	})
+}
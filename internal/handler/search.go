@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler interface {
+	SearchPosts(c *fiber.Ctx) error
+}
+
+type SearchHandlerImpl struct {
+	SearchService services.SearchService
+}
+
+func NewSearchHandler(searchService services.SearchService) SearchHandler {
+	return &SearchHandlerImpl{
+		SearchService: searchService,
+	}
+}
+
+func (h *SearchHandlerImpl) SearchPosts(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	categoryId, _ := strconv.Atoi(c.Query("category_id"))
+	authorId, _ := strconv.Atoi(c.Query("author_id"))
+
+	filter := dto.SearchFilterRequest{
+		Query:      strings.TrimSpace(c.Query("q")),
+		CategoryID: categoryId,
+		AuthorID:   authorId,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+		},
+	}
+	filter.SetDefaults()
+
+	validator := utils.GetValidator()
+	if err := validator.Struct(&filter); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	datas, err := h.SearchService.Search(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully search posts",
+	})
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SecurityHandler interface {
+	FindLockouts(c *fiber.Ctx) error
+	ClearLockout(c *fiber.Ctx) error
+	FindSecurityLogs(c *fiber.Ctx) error
+	FindJwtKeys(c *fiber.Ctx) error
+	RotateJwtKey(c *fiber.Ctx) error
+	GetJWKS(c *fiber.Ctx) error
+}
+
+type SecurityHandlerImpl struct {
+	LoginGuardService services.LoginGuardService
+	JwtKeyService     services.JwtKeyService
+}
+
+func NewSecurityHandler(loginGuardService services.LoginGuardService, jwtKeyService services.JwtKeyService) SecurityHandler {
+	return &SecurityHandlerImpl{
+		LoginGuardService: loginGuardService,
+		JwtKeyService:     jwtKeyService,
+	}
+}
+
+func (h *SecurityHandlerImpl) FindLockouts(c *fiber.Ctx) error {
+	lockouts, err := h.LoginGuardService.FindLockouts()
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    lockouts,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get login lockouts",
+	})
+}
+
+func (h *SecurityHandlerImpl) ClearLockout(c *fiber.Ctx) error {
+	var req dto.ClearLockoutRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.LoginGuardService.ClearLockout(req.Key, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully clear login lockout",
+	})
+}
+
+func (h *SecurityHandlerImpl) FindSecurityLogs(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+	userId, _ := strconv.ParseInt(c.Query("user_id"), 10, 64)
+
+	filter := dto.SecurityLogFilterRequest{
+		Event:     c.Query("event"),
+		UserID:    userId,
+		IPAddress: c.Query("ip"),
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+		},
+	}
+
+	datas, err := h.LoginGuardService.FindSecurityLogs(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get security logs",
+	})
+}
+
+func (h *SecurityHandlerImpl) FindJwtKeys(c *fiber.Ctx) error {
+	keys, err := h.JwtKeyService.FindKeys()
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    keys,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get jwt keys",
+	})
+}
+
+func (h *SecurityHandlerImpl) RotateJwtKey(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	key, err := h.JwtKeyService.Rotate(*userDetail, sessionMeta(c))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    key,
+		Status:  fiber.StatusCreated,
+		Message: "Successfully rotate jwt key, previous keys stay valid until their tokens expire",
+	})
+}
+
+// GetJWKS dibaca service lain untuk memverifikasi token, formatnya mengikuti standar JWKS jadi tidak dibungkus response umum
+func (h *SecurityHandlerImpl) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	return c.Status(fiber.StatusOK).JSON(h.JwtKeyService.GetJWKS())
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SeriesHandler interface {
+	FindAllSeries(c *fiber.Ctx) error
+	FindSeriesBySlug(c *fiber.Ctx) error
+	CreateSeries(c *fiber.Ctx) error
+	UpdateSeries(c *fiber.Ctx) error
+	SetSeriesPosts(c *fiber.Ctx) error
+	DeleteSeries(c *fiber.Ctx) error
+}
+
+type SeriesHandlerImpl struct {
+	SeriesService services.SeriesService
+}
+
+func NewSeriesHandler(seriesService services.SeriesService) SeriesHandler {
+	return &SeriesHandlerImpl{
+		SeriesService: seriesService,
+	}
+}
+
+func (h *SeriesHandlerImpl) FindAllSeries(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	authorId, _ := strconv.Atoi(c.Query("author_id"))
+
+	filter := dto.SeriesFilterRequest{
+		AuthorID: authorId,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     c.Query("sort", "series.updated_at desc"),
+		},
+	}
+
+	seriesRes, err := h.SeriesService.FindAll(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    seriesRes,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get all series",
+	})
+}
+
+func (h *SeriesHandlerImpl) FindSeriesBySlug(c *fiber.Ctx) error {
+	// progress baca hanya dihitung kalau user login
+	var viewer *utils.Claims
+	if userDetail, err := utils.GetUserClaims(c); err == nil {
+		viewer = userDetail
+	}
+
+	seriesDetail, err := h.SeriesService.FindBySlug(c.Params("slug"), viewer)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    seriesDetail,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get series",
+	})
+}
+
+func (h *SeriesHandlerImpl) CreateSeries(c *fiber.Ctx) error {
+	var reqBody dto.CreateSeriesRequest
+
+	if err := json.Unmarshal(c.Body(), &reqBody); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	if err := utils.GetValidator().Struct(&reqBody); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	series, err := h.SeriesService.Create(reqBody, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    series,
+		Status:  fiber.StatusCreated,
+		Message: "Successfully create series",
+	})
+}
+
+func (h *SeriesHandlerImpl) UpdateSeries(c *fiber.Ctx) error {
+	var reqBody dto.UpdateSeriesRequest
+
+	if err := json.Unmarshal(c.Body(), &reqBody); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	if err := utils.GetValidator().Struct(&reqBody); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.SeriesService.Update(c.Params("slug"), reqBody, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully update series",
+	})
+}
+
+func (h *SeriesHandlerImpl) SetSeriesPosts(c *fiber.Ctx) error {
+	var reqBody dto.SetSeriesPostsRequest
+
+	if err := json.Unmarshal(c.Body(), &reqBody); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	if err := utils.GetValidator().Struct(&reqBody); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.SeriesService.SetPosts(c.Params("slug"), reqBody, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully update series posts",
+	})
+}
+
+func (h *SeriesHandlerImpl) DeleteSeries(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.SeriesService.Delete(c.Params("slug"), *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully delete series",
+	})
+}
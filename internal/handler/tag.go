@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TagHandler interface {
+	FindAllTag(c *fiber.Ctx) error
+	FindPostsByTag(c *fiber.Ctx) error
+}
+
+type TagHandlerImpl struct {
+	TagService services.TagService
+}
+
+func NewTagHandler(tagService services.TagService) TagHandler {
+	return &TagHandlerImpl{
+		TagService: tagService,
+	}
+}
+
+func (h *TagHandlerImpl) FindAllTag(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "20"))
+	sort := c.Query("sort", "post_count desc")
+
+	filter := dto.TagFilterRequest{
+		Name: c.Query("name"),
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	datas, err := h.TagService.FindAllTag(filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get all tags",
+	})
+}
+
+func (h *TagHandlerImpl) FindPostsByTag(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("page_size", "10"))
+	sort := c.Query("sort", "created_at desc")
+	status, _ := strconv.Atoi(c.Query("status"))
+
+	filter := dto.PostFilterRequest{
+		Status: status,
+		PaginationParams: dto.PaginationParams{
+			Page:     page,
+			PageSize: pageSize,
+			Sort:     sort,
+		},
+	}
+	filter.SetDefaults()
+
+	if include := c.Query("includes"); include != "" {
+		for _, v := range strings.Split(include, ",") {
+			if v == "author" {
+				filter.IncludeAuthor = 1
+			}
+			if v == "likes" {
+				filter.IncludeLike = 1
+			}
+			if v == "category" {
+				filter.IncludeCategory = 1
+			}
+		}
+	}
+
+	datas, err := h.TagService.FindPostsByTag(c.Params("slug"), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    datas,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get posts by tag",
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TwoFactorHandler interface {
+	FindStatus(c *fiber.Ctx) error
+	Setup(c *fiber.Ctx) error
+	Enable(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+}
+
+type TwoFactorHandlerImpl struct {
+	TwoFactorService services.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService services.TwoFactorService) TwoFactorHandler {
+	return &TwoFactorHandlerImpl{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (h *TwoFactorHandlerImpl) FindStatus(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	status, err := h.TwoFactorService.FindStatus(int64(userDetail.UserId), userDetail.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    status,
+		Status:  fiber.StatusOK,
+		Message: "Successfully get two factor status",
+	})
+}
+
+func (h *TwoFactorHandlerImpl) Setup(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.TwoFactorService.Setup(int64(userDetail.UserId))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    response,
+		Status:  fiber.StatusOK,
+		Message: "Successfully setup two factor authentication, confirm it with a code from your authenticator app",
+	})
+}
+
+func (h *TwoFactorHandlerImpl) Enable(c *fiber.Ctx) error {
+	var req dto.TwoFactorCodeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.TwoFactorService.Enable(int64(userDetail.UserId), req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    dto.TwoFactorEnableResponse{RecoveryCodes: recoveryCodes},
+		Status:  fiber.StatusOK,
+		Message: "Successfully enable two factor authentication, store the recovery codes in a safe place",
+	})
+}
+
+func (h *TwoFactorHandlerImpl) Disable(c *fiber.Ctx) error {
+	var req dto.TwoFactorDisableRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.TwoFactorService.Disable(int64(userDetail.UserId), userDetail.Role, req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully disable two factor authentication",
+	})
+}
+
+func (h *TwoFactorHandlerImpl) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var req dto.TwoFactorCodeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	validator := utils.GetValidator()
+
+	if err := validator.Struct(&req); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := h.TwoFactorService.RegenerateRecoveryCodes(int64(userDetail.UserId), req.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    dto.TwoFactorEnableResponse{RecoveryCodes: recoveryCodes},
+		Status:  fiber.StatusOK,
+		Message: "Successfully regenerate recovery codes",
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"strconv"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/policy"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/MrBista/blog-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
@@ -25,6 +27,7 @@ type UserHandler interface {
 	CheckFollowStatus(c *fiber.Ctx) error
 	GetMyFollowers(c *fiber.Ctx) error
 	GetMyFollowing(c *fiber.Ctx) error
+	GetMyPermissions(c *fiber.Ctx) error
 }
 
 type UserHandlerImpl struct {
@@ -73,7 +76,30 @@ func (h *UserHandlerImpl) GetAllUser(c *fiber.Ctx) error {
 }
 
 func (h *UserHandlerImpl) DeactiveUser(c *fiber.Ctx) error {
-	panic("not implemented") // TODO: Implement
+	var reqBody dto.BanUsersRequest
+
+	if err := c.BodyParser(&reqBody); err != nil {
+		return exception.NewBadRequestErr("Invalid request body")
+	}
+
+	if err := utils.GetValidator().Struct(&reqBody); err != nil {
+		return exception.NewValidationErr(err)
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.UserService.DeactiveUsers(reqBody.Ids, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Status:  fiber.StatusOK,
+		Message: "Successfully ban users",
+	})
 }
 
 func (h *UserHandlerImpl) GetDetailUser(c *fiber.Ctx) error {
@@ -192,7 +218,12 @@ func (h *UserHandlerImpl) GetListFollower(c *fiber.Ctx) error {
 		return exception.NewBadRequestErr("Invalid user ID")
 	}
 
-	followers, err := h.UserService.GetListFollower(userId)
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	followers, err := h.UserService.GetListFollower(userId, userDetail.UserId)
 	if err != nil {
 		return err
 	}
@@ -212,7 +243,12 @@ func (h *UserHandlerImpl) GetListFollowing(c *fiber.Ctx) error {
 		return exception.NewBadRequestErr("Invalid user ID")
 	}
 
-	following, err := h.UserService.GetListFollowing(userId)
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	following, err := h.UserService.GetListFollowing(userId, userDetail.UserId)
 	if err != nil {
 		return err
 	}
@@ -301,7 +337,7 @@ func (h *UserHandlerImpl) GetMyFollowers(c *fiber.Ctx) error {
 		return err
 	}
 
-	followers, err := h.UserService.GetListFollower(userDetail.UserId)
+	followers, err := h.UserService.GetListFollower(userDetail.UserId, userDetail.UserId)
 	if err != nil {
 		return err
 	}
@@ -319,7 +355,7 @@ func (h *UserHandlerImpl) GetMyFollowing(c *fiber.Ctx) error {
 		return err
 	}
 
-	following, err := h.UserService.GetListFollowing(userDetail.UserId)
+	following, err := h.UserService.GetListFollowing(userDetail.UserId, userDetail.UserId)
 	if err != nil {
 		return err
 	}
@@ -330,3 +366,17 @@ func (h *UserHandlerImpl) GetMyFollowing(c *fiber.Ctx) error {
 		Status:  fiber.StatusOK,
 	})
 }
+
+// GetMyPermissions dipakai frontend untuk menampilkan/menyembunyikan aksi sesuai role user
+func (h *UserHandlerImpl) GetMyPermissions(c *fiber.Ctx) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    policy.PermissionsOf(enum.UserRole(userDetail.Role)),
+		Message: "Successfully retrieved my permissions",
+		Status:  fiber.StatusOK,
+	})
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type UserBlockHandler interface {
+	BlockUser(c *fiber.Ctx) error
+	UnblockUser(c *fiber.Ctx) error
+	MuteUser(c *fiber.Ctx) error
+	UnmuteUser(c *fiber.Ctx) error
+	GetMyBlocks(c *fiber.Ctx) error
+	GetMyMutes(c *fiber.Ctx) error
+}
+
+type UserBlockHandlerImpl struct {
+	UserBlockService services.UserBlockService
+}
+
+func NewUserBlockHandler(userBlockService services.UserBlockService) UserBlockHandler {
+	return &UserBlockHandlerImpl{
+		UserBlockService: userBlockService,
+	}
+}
+
+func (h *UserBlockHandlerImpl) BlockUser(c *fiber.Ctx) error {
+	return h.add(c, enum.UserBlockTypeBlock, "Successfully blocked user")
+}
+
+func (h *UserBlockHandlerImpl) UnblockUser(c *fiber.Ctx) error {
+	return h.remove(c, enum.UserBlockTypeBlock, "Successfully unblocked user")
+}
+
+func (h *UserBlockHandlerImpl) MuteUser(c *fiber.Ctx) error {
+	return h.add(c, enum.UserBlockTypeMute, "Successfully muted user")
+}
+
+func (h *UserBlockHandlerImpl) UnmuteUser(c *fiber.Ctx) error {
+	return h.remove(c, enum.UserBlockTypeMute, "Successfully unmuted user")
+}
+
+func (h *UserBlockHandlerImpl) GetMyBlocks(c *fiber.Ctx) error {
+	return h.findMine(c, enum.UserBlockTypeBlock, "Successfully retrieved blocked users")
+}
+
+func (h *UserBlockHandlerImpl) GetMyMutes(c *fiber.Ctx) error {
+	return h.findMine(c, enum.UserBlockTypeMute, "Successfully retrieved muted users")
+}
+
+func (h *UserBlockHandlerImpl) add(c *fiber.Ctx, blockType enum.UserBlockType, message string) error {
+	targetId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid user ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.UserBlockService.Add(targetId, blockType, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Message: message,
+		Status:  fiber.StatusCreated,
+	})
+}
+
+func (h *UserBlockHandlerImpl) remove(c *fiber.Ctx, blockType enum.UserBlockType, message string) error {
+	targetId, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return exception.NewBadRequestErr("Invalid user ID")
+	}
+
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.UserBlockService.Remove(targetId, blockType, *userDetail); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    true,
+		Message: message,
+		Status:  fiber.StatusOK,
+	})
+}
+
+func (h *UserBlockHandlerImpl) findMine(c *fiber.Ctx, blockType enum.UserBlockType, message string) error {
+	userDetail, err := utils.GetUserClaims(c)
+	if err != nil {
+		return err
+	}
+
+	users, err := h.UserBlockService.FindMine(blockType, *userDetail)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.CommonResponseSuccess{
+		Data:    users,
+		Message: message,
+		Status:  fiber.StatusOK,
+	})
+}
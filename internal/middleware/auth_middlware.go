@@ -3,13 +3,18 @@ package middleware
 import (
 	"strings"
 
+	"github.com/MrBista/blog-api/internal/database"
 	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/policy"
+	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
-func AuthMiddlware() fiber.Handler {
+// AuthMiddlware menerima JWT login dan personal access token. Personal access token hanya diterima
+// di route yang menyebutkan scope, dan token harus punya semua scope tersebut.
+func AuthMiddlware(scopes ...enum.TokenScope) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
 		authHeader := c.Get("Authorization")
@@ -23,13 +28,34 @@ func AuthMiddlware() fiber.Handler {
 			return exception.NewUnAuthorizationErr("Invalid authorization header")
 		}
 
-		tokenString := parts[1]
+		claim, err := authenticate(parts[1])
+		if err != nil {
+			return err
+		}
+
+		if err := checkScopes(claim, scopes); err != nil {
+			return err
+		}
 
-		jwtService := utils.GetJwtService()
-		claim, err := jwtService.VerifyToken(tokenString)
+		c.Locals("user", claim)
+		c.Locals("userId", claim.UserId)
+		c.Locals("role", claim.Role)
 
+		return c.Next()
+	}
+}
+
+// OptionalAuthMiddleware mengisi data user kalau token valid, request tanpa token tetap diteruskan
+func OptionalAuthMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		parts := strings.Split(c.Get("Authorization"), " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return c.Next()
+		}
+
+		claim, err := authenticate(parts[1])
 		if err != nil {
-			return exception.NewUnAuthorizationErr("invalid or expired token")
+			return c.Next()
 		}
 
 		c.Locals("user", claim)
@@ -40,31 +66,102 @@ func AuthMiddlware() fiber.Handler {
 	}
 }
 
-func RoleMiddleare(allowedRoles ...enum.UserRole) fiber.Handler {
-	return func(c *fiber.Ctx) error {
+// authenticate memverifikasi token lalu mengecek session di database,
+// jadi logout, revoke session dan ban user langsung berlaku tanpa menunggu token kadaluarsa
+func authenticate(tokenString string) (*utils.Claims, error) {
+	if strings.HasPrefix(tokenString, utils.PersonalAccessTokenPrefix) {
+		return authenticatePersonalAccessToken(tokenString)
+	}
 
-		roleValue := c.Locals("role")
+	claim, err := utils.GetJwtService().VerifyToken(tokenString)
+	if err != nil || claim.TokenType != utils.AccessTokenType || claim.SessionId == 0 {
+		return nil, exception.NewUnAuthorizationErr("invalid or expired token")
+	}
 
-		if roleValue == nil {
-			return exception.NewForbiddenErr("You dont have permission")
+	session, err := repository.NewUserSessionRepository(database.DB).FindActiveWithUser(claim.SessionId)
+	if err != nil {
+		if customErr, ok := err.(*exception.ErrorCustom); ok && customErr.Code == exception.ERR_NOT_FOUND {
+			return nil, exception.NewUnAuthorizationErr("session has expired or been revoked")
 		}
+		return nil, err
+	}
 
-		role, ok := roleValue.(enum.UserRole)
-		if !ok {
-			if roleInt, ok := roleValue.(int); ok {
-				role = enum.UserRole(roleInt)
-			} else {
-				return exception.NewBadRequestErr("Invalid role type")
-			}
+	if session.UserID != int64(claim.UserId) || session.User == nil || enum.UserStatus(session.User.Status) != enum.UserStatusActive {
+		return nil, exception.NewUnAuthorizationErr("user is not active")
+	}
+
+	// role diambil dari database supaya perubahan role langsung berlaku
+	claim.Role = session.User.Role
+
+	return claim, nil
+}
+
+func authenticatePersonalAccessToken(tokenString string) (*utils.Claims, error) {
+	tokenRepository := repository.NewPersonalAccessTokenRepository(database.DB)
+
+	token, err := tokenRepository.FindActiveByHash(utils.HashToken(tokenString))
+	if err != nil {
+		if customErr, ok := err.(*exception.ErrorCustom); ok && customErr.Code == exception.ERR_NOT_FOUND {
+			return nil, exception.NewUnAuthorizationErr("invalid, expired or revoked token")
 		}
+		return nil, err
+	}
+
+	if token.User == nil || enum.UserStatus(token.User.Status) != enum.UserStatusActive {
+		return nil, exception.NewUnAuthorizationErr("user is not active")
+	}
+
+	if err := tokenRepository.TouchLastUsed(token.ID); err != nil {
+		utils.Logger.Errorf("failed to update last used of token %d %v", token.ID, err)
+	}
+
+	return &utils.Claims{
+		UserId:    int(token.UserID),
+		Role:      token.User.Role,
+		TokenType: utils.PersonalAccessTokenType,
+		Scopes:    strings.Split(token.Scopes, ","),
+	}, nil
+}
+
+func checkScopes(claim *utils.Claims, scopes []enum.TokenScope) error {
+	if claim.TokenType != utils.PersonalAccessTokenType {
+		return nil
+	}
+
+	if len(scopes) == 0 {
+		return exception.NewForbiddenErr("personal access token cannot be used for this endpoint")
+	}
 
-		for _, allowed := range allowedRoles {
-			if role == allowed {
-				return c.Next()
+	for _, scope := range scopes {
+		granted := false
+		for _, owned := range claim.Scopes {
+			if owned == string(scope) {
+				granted = true
+				break
 			}
 		}
+		if !granted {
+			return exception.NewForbiddenErr("token is missing scope " + string(scope))
+		}
+	}
+
+	return nil
+}
+
+// PermissionMiddleware dipasang setelah AuthMiddlware, user harus punya semua permission yang disebut
+func PermissionMiddleware(permissions ...enum.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := utils.GetUserClaims(c)
+		if err != nil {
+			return exception.NewForbiddenErr("You dont have permission")
+		}
 
-		return exception.NewForbiddenErr("You do not have permission to access this resource")
+		for _, permission := range permissions {
+			if err := policy.Authorize(*user, permission); err != nil {
+				return err
+			}
+		}
 
+		return c.Next()
 	}
 }
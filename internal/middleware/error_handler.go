@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strconv"
+
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/utils"
 	"github.com/gofiber/fiber/v2"
@@ -10,6 +12,9 @@ func HandleError(c *fiber.Ctx, err error) error {
 	if customErr, ok := err.(*exception.ErrorCustom); ok {
 		// log.Printf("[ERROR] %s: %s", customErr.Code, customErr.Message)
 		utils.Logger.Errorf("Error code %s with message %s", customErr.Code, customErr.Message)
+		if customErr.RetryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(customErr.RetryAfter))
+		}
 		return c.Status(customErr.GetStatusHttp()).JSON(customErr)
 	}
 
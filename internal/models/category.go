@@ -6,6 +6,9 @@ type Category struct {
 	Slug        string  `gorm:"column:slug;type:varchar(100);unique;not null" json:"slug"`
 	Description *string `gorm:"column:description;type:text" json:"description,omitempty"`
 	ParentID    *int64  `gorm:"column:parent_id" json:"parentId,omitempty"`
+	// counter dijaga saat follow/unfollow dan saat status post berubah, bukan dihitung per request
+	FollowerCount      int64 `gorm:"column:follower_count;not null;default:0" json:"followerCount"`
+	PublishedPostCount int64 `gorm:"column:published_post_count;not null;default:0" json:"publishedPostCount"`
 
 	// Relations
 	// Parent   *Category  `gorm:"foreignKey:ParentID;references:ID" json:"parent,omitempty"`
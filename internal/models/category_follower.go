@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CategoryFollower user yang mengikuti kategori, post baru di kategori ini masuk ke feed-nya
+type CategoryFollower struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID     int64     `gorm:"column:user_id;not null;uniqueIndex:uniq_category_follower,priority:1" json:"userId"`
+	CategoryID int64     `gorm:"column:category_id;not null;uniqueIndex:uniq_category_follower,priority:2;index" json:"categoryId"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (CategoryFollower) TableName() string {
+	return "category_followers"
+}
@@ -3,15 +3,21 @@ package models
 import "time"
 
 type Comment struct {
-	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	PostID    int64     `gorm:"column:post_id;not null" json:"postId"`
-	UserID    *int64    `gorm:"column:user_id" json:"userId,omitempty"`
-	ParentID  *int64    `gorm:"column:parent_id" json:"parentId"`
-	Name      *string   `gorm:"column:name;type:varchar(150)" json:"name,omitempty"`
-	Email     *string   `gorm:"column:email;type:varchar(150)" json:"email,omitempty"`
-	Content   string    `gorm:"column:content;type:text;not null" json:"content"`
-	Status    int8      `gorm:"column:status;type:tinyint;default:1;comment:0=inactive,1=active,2=deleted" json:"status"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	PostID      int64      `gorm:"column:post_id;not null;index:idx_comment_post_parent,priority:1" json:"postId"`
+	UserID      *int64     `gorm:"column:user_id" json:"userId,omitempty"`
+	ParentID    *int64     `gorm:"column:parent_id;index:idx_comment_post_parent,priority:2" json:"parentId"`
+	Name        *string    `gorm:"column:name;type:varchar(150)" json:"name,omitempty"`
+	Email       *string    `gorm:"column:email;type:varchar(150)" json:"email,omitempty"`
+	Content     string     `gorm:"column:content;type:text;not null" json:"content"`
+	Status      int8       `gorm:"column:status;type:tinyint;default:1;index;comment:0=inactive,1=active,2=deleted,3=rejected,4=unconfirmed" json:"status"`
+	EditedAt    *time.Time `gorm:"column:edited_at" json:"editedAt,omitempty"`
+	IPAddress   *string    `gorm:"column:ip_address;type:varchar(45);index" json:"-"`
+	SpamScore   float64    `gorm:"column:spam_score;default:0" json:"-"`
+	SpamReasons *string    `gorm:"column:spam_reasons;type:varchar(500)" json:"-"`
+	ModeratedBy *int64     `gorm:"column:moderated_by" json:"moderatedBy,omitempty"`
+	ModeratedAt *time.Time `gorm:"column:moderated_at" json:"moderatedAt,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
 }
 
 func (c *Comment) TableName() string {
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// DataExport job export data pribadi user, file zip disimpan di luar folder public
+type DataExport struct {
+	ID           int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID       int64      `gorm:"column:user_id;not null;index" json:"userId"`
+	Status       string     `gorm:"column:status;type:varchar(20);not null;index;comment:pending,processing,ready,failed" json:"status"`
+	FilePath     *string    `gorm:"column:file_path;type:varchar(500)" json:"-"`
+	FileSize     int64      `gorm:"column:file_size;default:0" json:"fileSize"`
+	ErrorMessage *string    `gorm:"column:error_message;type:varchar(255)" json:"errorMessage,omitempty"`
+	CompletedAt  *time.Time `gorm:"column:completed_at" json:"completedAt,omitempty"`
+	ExpiresAt    *time.Time `gorm:"column:expires_at;index" json:"expiresAt,omitempty"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (DataExport) TableName() string {
+	return "data_exports"
+}
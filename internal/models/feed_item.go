@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// FeedItem inbox feed per user untuk strategy fan-out-on-write, PublishedAt disalin dari post untuk urutan
+type FeedItem struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID      int64     `gorm:"column:user_id;not null;uniqueIndex:uniq_feed_item,priority:1;index:idx_feed_user_published,priority:1" json:"userId"`
+	PostID      int64     `gorm:"column:post_id;not null;uniqueIndex:uniq_feed_item,priority:2;index" json:"postId"`
+	AuthorID    int64     `gorm:"column:author_id;not null;index" json:"authorId"`
+	PublishedAt time.Time `gorm:"column:published_at;not null;index:idx_feed_user_published,priority:2" json:"publishedAt"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (FeedItem) TableName() string {
+	return "feed_items"
+}
@@ -4,9 +4,9 @@ import "time"
 
 type Like struct {
 	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	UserID     int64     `gorm:"column:user_id;not null" json:"userId"`
-	TargetType int8      `gorm:"column:target_type;type:tinyint;not null;default:1;comment:1=posts,2=comments" json:"targetType"`
-	TargetID   int64     `gorm:"column:target_id;not null" json:"targetId"`
+	UserID     int64     `gorm:"column:user_id;not null;uniqueIndex:uniq_like_user_target,priority:1" json:"userId"`
+	TargetType int8      `gorm:"column:target_type;type:tinyint;not null;default:1;uniqueIndex:uniq_like_user_target,priority:2;index:idx_like_target,priority:1;comment:1=posts,2=comments" json:"targetType"`
+	TargetID   int64     `gorm:"column:target_id;not null;uniqueIndex:uniq_like_user_target,priority:3;index:idx_like_target,priority:2" json:"targetId"`
 	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
 }
 
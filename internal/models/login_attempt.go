@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// LoginAttempt counter login gagal per akun atau per IP, key berbentuk "<scope>:<nilai>"
+type LoginAttempt struct {
+	Key           string     `gorm:"column:attempt_key;type:varchar(191);primaryKey" json:"key"`
+	Failures      int        `gorm:"column:failures;not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `gorm:"column:last_failure_at;not null" json:"lastFailureAt"`
+	LockedUntil   *time.Time `gorm:"column:locked_until;index" json:"lockedUntil,omitempty"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PersonalAccessToken token untuk script/CI, token asli hanya ditampilkan sekali saat dibuat
+type PersonalAccessToken struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID      int64      `gorm:"column:user_id;not null;index" json:"userId"`
+	Name        string     `gorm:"column:name;type:varchar(100);not null" json:"name"`
+	TokenHash   string     `gorm:"column:token_hash;type:char(64);not null;uniqueIndex" json:"-"`
+	TokenPrefix string     `gorm:"column:token_prefix;type:varchar(20);not null;comment:awal token untuk identifikasi di list" json:"tokenPrefix"`
+	Scopes      string     `gorm:"column:scopes;type:varchar(500);not null;comment:dipisah koma" json:"scopes"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null" json:"expiresAt"`
+	LastUsedAt  *time.Time `gorm:"column:last_used_at" json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at;index" json:"revokedAt,omitempty"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
@@ -10,7 +10,7 @@ type Post struct {
 	MainImageURI   *string    `gorm:"column:main_image_uri;type:varchar(500)" json:"mainImageUri,omitempty"`
 	AuthorID       int64      `gorm:"column:author_id;not null" json:"authorId"`
 	CategoryID     *int64     `gorm:"column:category_id" json:"categoryId,omitempty"`
-	Status         uint8      `gorm:"column:status;default:0;comment:0='inactive',1='draft',2='review',3='published',4='archived'" json:"status"`
+	Status         uint8      `gorm:"column:status;default:1;comment:0='inactive',1='draft',2='review',3='published',4='archived'" json:"status"`
 	IsFeatured     bool       `gorm:"column:is_featured;default:false" json:"isFeatured"`
 	ViewCount      int        `gorm:"column:view_count;default:0" json:"viewCount"`
 	SeoTitle       *string    `gorm:"column:seo_title;type:varchar(255)" json:"seoTitle,omitempty"`
@@ -18,12 +18,19 @@ type Post struct {
 	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
 	UpdatedAt      time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
 	PublishedAt    *time.Time `gorm:"column:published_at" json:"publishedAt,omitempty"`
+	ScheduledAt    *time.Time `gorm:"column:scheduled_at;index" json:"scheduledAt,omitempty"`
+	ApprovedBy     *int64     `gorm:"column:approved_by" json:"approvedBy,omitempty"`
+	// AllowGuestComments nil berarti mengikuti setting global comment.allow_guest
+	AllowGuestComments *bool `gorm:"column:allow_guest_comments" json:"allowGuestComments,omitempty"`
+	// FeedFanoutAt waktu post disebar ke inbox follower (feed.strategy write), nil berarti belum
+	FeedFanoutAt *time.Time `gorm:"column:feed_fanout_at;index" json:"-"`
 
 	// Relations
 	Author   *User     `gorm:"foreignKey:AuthorID;references:ID" json:"author,omitempty"`
 	Category *Category `gorm:"foreignKey:CategoryID;references:ID" json:"category,omitempty"`
 	Likes    []Like    `gorm:"foreignKey:TargetID;references:ID" json:"likes,omitempty"`
 	Comments []Comment `gorm:"foreignKey:PostID;references:ID" json:"comments,omitempty"`
+	Tags     []Tag     `gorm:"many2many:post_tags;joinForeignKey:PostID;joinReferences:TagID" json:"tags,omitempty"`
 
 	// LikeCount int64 `gorm:"-" json:"likeCount"`
 	LikeCount int64 `gorm:"->;column:like_count" json:"likeCount"`
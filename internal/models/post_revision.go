@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type PostRevision struct {
+	ID             int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	PostID         int64     `gorm:"column:post_id;not null;uniqueIndex:idx_post_revision_number" json:"postId"`
+	RevisionNumber int       `gorm:"column:revision_number;not null;uniqueIndex:idx_post_revision_number" json:"revisionNumber"`
+	Title          string    `gorm:"column:title;type:varchar(255);not null" json:"title"`
+	Content        string    `gorm:"column:content;type:longtext;not null" json:"content"`
+	EditorID       int64     `gorm:"column:editor_id;not null" json:"editorId"`
+	RestoredFromID *int64    `gorm:"column:restored_from_id" json:"restoredFromId,omitempty"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+
+	// Relations
+	Editor *User `gorm:"foreignKey:EditorID;references:ID" json:"editor,omitempty"`
+}
+
+func (PostRevision) TableName() string {
+	return "post_revisions"
+}
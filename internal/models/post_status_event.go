@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type PostStatusEvent struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	PostID     int64     `gorm:"column:post_id;not null;index" json:"postId"`
+	FromStatus uint8     `gorm:"column:from_status;not null" json:"fromStatus"`
+	ToStatus   uint8     `gorm:"column:to_status;not null" json:"toStatus"`
+	ActorID    *int64    `gorm:"column:actor_id;index;comment:null=system (scheduler)" json:"actorId,omitempty"`
+	Comment    *string   `gorm:"column:comment;type:text" json:"comment,omitempty"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (PostStatusEvent) TableName() string {
+	return "post_status_events"
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// SecurityLog catatan kejadian keamanan seperti login gagal dan lockout
+type SecurityLog struct {
+	ID         int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID     *int64    `gorm:"column:user_id;index" json:"userId,omitempty"`
+	Event      string    `gorm:"column:event;type:varchar(50);not null;index" json:"event"`
+	Identifier string    `gorm:"column:identifier;type:varchar(150)" json:"identifier,omitempty"`
+	IPAddress  string    `gorm:"column:ip_address;type:varchar(45);index" json:"ipAddress,omitempty"`
+	UserAgent  string    `gorm:"column:user_agent;type:varchar(255)" json:"userAgent,omitempty"`
+	Detail     string    `gorm:"column:detail;type:varchar(255)" json:"detail,omitempty"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime;index" json:"createdAt"`
+}
+
+func (SecurityLog) TableName() string {
+	return "security_logs"
+}
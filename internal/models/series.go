@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Series kumpulan post berurutan milik satu author, misalnya tutorial beberapa bagian
+type Series struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	AuthorID    int64     `gorm:"column:author_id;not null;index" json:"authorId"`
+	Title       string    `gorm:"column:title;type:varchar(255);not null" json:"title"`
+	Slug        string    `gorm:"column:slug;type:varchar(255);unique;not null" json:"slug"`
+	Description *string   `gorm:"column:description;type:text" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (Series) TableName() string {
+	return "series"
+}
+
+// SeriesPost satu post hanya bisa masuk ke satu series, Position mulai dari 1
+type SeriesPost struct {
+	ID       int64 `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	SeriesID int64 `gorm:"column:series_id;not null;index:idx_series_position,priority:1" json:"seriesId"`
+	PostID   int64 `gorm:"column:post_id;not null;unique" json:"postId"`
+	Position int   `gorm:"column:position;not null;index:idx_series_position,priority:2" json:"position"`
+}
+
+func (SeriesPost) TableName() string {
+	return "series_posts"
+}
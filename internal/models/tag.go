@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"column:name;type:varchar(100);unique;not null" json:"name"`
+	Slug      string    `gorm:"column:slug;type:varchar(120);unique;not null" json:"slug"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+type PostTag struct {
+	PostID int64 `gorm:"column:post_id;primaryKey" json:"postId"`
+	TagID  int64 `gorm:"column:tag_id;primaryKey;index" json:"tagId"`
+}
+
+func (PostTag) TableName() string {
+	return "post_tags"
+}
@@ -3,20 +3,26 @@ package models
 import "time"
 
 type User struct {
-	ID              int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Name            string     `gorm:"column:name;type:varchar(150);not null" json:"name"`
-	Username        string     `gorm:"column:username;type:varchar(100);unique;not null" json:"username"`
-	Email           string     `gorm:"column:email;type:varchar(150);unique;not null" json:"email"`
-	Password        string     `gorm:"column:password;type:varchar(255)" json:"password"`
-	Bio             *string    `gorm:"column:bio;type:text" json:"bio,omitempty"`
-	ProfileImageURI *string    `gorm:"column:profile_image_uri;type:varchar(500)" json:"profileImageUri,omitempty"`
-	Role            int        `gorm:"column:role;default:0;comment:0=reader,1=editor,2=author,3=admin" json:"role"`
-	IsSubscribed    bool       `gorm:"column:is_subscribed;default:false" json:"isSubscribed"`
-	SubscriptionEnd *time.Time `gorm:"column:subscription_end" json:"subscriptionEnd"`
-	Status          int        `gorm:"column:status;default:0;comment:0=inactive,1=active,2=archived,3=banned" json:"status"`
-	AuthProvider    string     `gorm:"default:'local'"`
-	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
-	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+	ID              int64        `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Name            string       `gorm:"column:name;type:varchar(150);not null" json:"name"`
+	Username        string       `gorm:"column:username;type:varchar(100);unique;not null" json:"username"`
+	Email           string       `gorm:"column:email;type:varchar(150);unique;not null" json:"email"`
+	Password        string       `gorm:"column:password;type:varchar(255)" json:"password"`
+	Bio             *string      `gorm:"column:bio;type:text" json:"bio,omitempty"`
+	ProfileImageURI *string      `gorm:"column:profile_image_uri;type:varchar(500)" json:"profileImageUri,omitempty"`
+	SocialLinks     *SocialLinks `gorm:"column:social_links;type:json;serializer:json" json:"socialLinks,omitempty"`
+	Role            int          `gorm:"column:role;default:0;comment:0=reader,1=editor,2=author,3=admin" json:"role"`
+	IsSubscribed    bool         `gorm:"column:is_subscribed;default:false" json:"isSubscribed"`
+	SubscriptionEnd *time.Time   `gorm:"column:subscription_end" json:"subscriptionEnd"`
+	Status          int          `gorm:"column:status;default:0;comment:0=inactive,1=active,2=archived,3=banned,4=deleted" json:"status"`
+	AuthProvider    string       `gorm:"default:'local'"`
+	EmailVerifiedAt *time.Time   `gorm:"column:email_verified_at" json:"emailVerifiedAt,omitempty"`
+	// UsernameChangedAt dipakai untuk membatasi frekuensi ganti username
+	UsernameChangedAt *time.Time `gorm:"column:username_changed_at" json:"usernameChangedAt,omitempty"`
+	// DeletionScheduledAt diisi saat user minta hapus akun, akun dihapus worker setelah waktu ini
+	DeletionScheduledAt *time.Time `gorm:"column:deletion_scheduled_at;index" json:"deletionScheduledAt,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
 
 	// Relations
 	// Posts []Post `gorm:"foreignKey:AuthorID;references:ID" json:"posts,omitempty"`
@@ -25,3 +31,13 @@ type User struct {
 func (User) TableName() string {
 	return "users"
 }
+
+// SocialLinks disimpan sebagai JSON di kolom social_links
+type SocialLinks struct {
+	Website   string `json:"website,omitempty"`
+	Twitter   string `json:"twitter,omitempty"`
+	Github    string `json:"github,omitempty"`
+	Linkedin  string `json:"linkedin,omitempty"`
+	Instagram string `json:"instagram,omitempty"`
+	Youtube   string `json:"youtube,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserBlock user (UserID) memblokir atau me-mute user lain (TargetID)
+type UserBlock struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    int64     `gorm:"column:user_id;not null;uniqueIndex:uniq_user_block,priority:1" json:"userId"`
+	TargetID  int64     `gorm:"column:target_id;not null;uniqueIndex:uniq_user_block,priority:2;index" json:"targetId"`
+	Type      string    `gorm:"column:type;type:varchar(10);not null;uniqueIndex:uniq_user_block,priority:3;comment:block,mute" json:"type"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// UserIdentity akun provider login (google, github, oidc lain) yang ditautkan ke user
+type UserIdentity struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    int64     `gorm:"column:user_id;not null;index" json:"userId"`
+	Provider  string    `gorm:"column:provider;type:varchar(50);not null;uniqueIndex:uniq_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"column:subject;type:varchar(255);not null;uniqueIndex:uniq_identity_provider_subject" json:"-"`
+	Email     *string   `gorm:"column:email;type:varchar(150)" json:"email,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
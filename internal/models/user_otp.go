@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserOtp kode sekali pakai untuk user, kode disimpan dalam bentuk hash
+type UserOtp struct {
+	ID         int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID     int64      `gorm:"column:user_id;not null;index:idx_user_otp_user_purpose" json:"userId"`
+	Purpose    string     `gorm:"column:purpose;type:varchar(30);not null;index:idx_user_otp_user_purpose" json:"purpose"`
+	CodeHash   string     `gorm:"column:code_hash;type:varchar(255);not null" json:"-"`
+	Attempts   int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null" json:"expiresAt"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at;comment:diisi saat kode dipakai atau diganti kode baru" json:"consumedAt,omitempty"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (UserOtp) TableName() string {
+	return "user_otps"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserSession adalah satu login (satu device), refresh token hanya disimpan dalam bentuk hash
+type UserSession struct {
+	ID                int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID            int64      `gorm:"column:user_id;not null;index" json:"userId"`
+	RefreshTokenHash  string     `gorm:"column:refresh_token_hash;type:char(64);not null" json:"-"`
+	PreviousTokenHash *string    `gorm:"column:previous_token_hash;type:char(64)" json:"-"`
+	UserAgent         string     `gorm:"column:user_agent;type:varchar(255)" json:"userAgent"`
+	IPAddress         string     `gorm:"column:ip_address;type:varchar(45)" json:"ipAddress"`
+	ExpiresAt         time.Time  `gorm:"column:expires_at;not null" json:"expiresAt"`
+	LastUsedAt        time.Time  `gorm:"column:last_used_at" json:"lastUsedAt"`
+	RevokedAt         *time.Time `gorm:"column:revoked_at;index" json:"revokedAt,omitempty"`
+	RevokedReason     *string    `gorm:"column:revoked_reason;type:varchar(100)" json:"revokedReason,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
+
+func (s *UserSession) TableName() string {
+	return "user_sessions"
+}
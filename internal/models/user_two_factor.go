@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// UserTwoFactor konfigurasi TOTP user, EnabledAt nil berarti enrolment belum dikonfirmasi
+type UserTwoFactor struct {
+	ID              int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID          int64      `gorm:"column:user_id;not null;uniqueIndex" json:"userId"`
+	SecretEncrypted string     `gorm:"column:secret_encrypted;type:varchar(255);not null" json:"-"`
+	EnabledAt       *time.Time `gorm:"column:enabled_at" json:"enabledAt,omitempty"`
+	LastUsedStep    int64      `gorm:"column:last_used_step;not null;default:0;comment:time step TOTP terakhir, mencegah kode dipakai ulang" json:"-"`
+	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updatedAt"`
+}
+
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+type UserRecoveryCode struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID    int64      `gorm:"column:user_id;not null;index" json:"userId"`
+	CodeHash  string     `gorm:"column:code_hash;type:char(64);not null;index" json:"-"`
+	UsedAt    *time.Time `gorm:"column:used_at" json:"usedAt,omitempty"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
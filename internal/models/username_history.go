@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UsernameHistory riwayat ganti username, dipakai untuk redirect link profil lama
+type UsernameHistory struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID      int64     `gorm:"column:user_id;not null;index" json:"userId"`
+	OldUsername string    `gorm:"column:old_username;type:varchar(100);not null;index" json:"oldUsername"`
+	NewUsername string    `gorm:"column:new_username;type:varchar(100);not null" json:"newUsername"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"createdAt"`
+}
+
+func (UsernameHistory) TableName() string {
+	return "username_histories"
+}
@@ -0,0 +1,100 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+const githubAPI = "https://api.github.com"
+
+// GitHubProvider GitHub bukan OIDC, data user diambil dari REST API
+type GitHubProvider struct {
+	name        string
+	displayName string
+	config      *oauth2.Config
+}
+
+func newGitHubProvider(name string, displayName string, providerConfig config.OAuthProviderConfig) *GitHubProvider {
+	scopes := providerConfig.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"read:user", "user:email"}
+	}
+
+	return &GitHubProvider{
+		name:        name,
+		displayName: displayName,
+		config: &oauth2.Config{
+			ClientID:     providerConfig.ClientID,
+			ClientSecret: providerConfig.ClientSecret,
+			RedirectURL:  providerConfig.RedirectURL,
+			Scopes:       scopes,
+			Endpoint:     github.Endpoint,
+		},
+	}
+}
+
+func (p *GitHubProvider) Name() string {
+	return p.name
+}
+
+func (p *GitHubProvider) DisplayName() string {
+	return p.displayName
+}
+
+func (p *GitHubProvider) AuthCodeURL(state string) (string, error) {
+	return p.config.AuthCodeURL(state), nil
+}
+
+func (p *GitHubProvider) Exchange(ctx context.Context, code string) (*Identity, error) {
+	ctx = withHTTPClient(ctx)
+
+	token, err := p.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	client := p.config.Client(ctx, token)
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, client, githubAPI+"/user", &user); err != nil {
+		return nil, fmt.Errorf("failed to get github user: %w", err)
+	}
+
+	// email di /user bisa kosong kalau private, jadi ambil email primary yang terverifikasi
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, githubAPI+"/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("failed to get github emails: %w", err)
+	}
+
+	identity := &Identity{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Username: user.Login,
+		Picture:  user.AvatarURL,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,165 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"golang.org/x/oauth2"
+)
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// OIDCProvider provider OpenID Connect generic, endpoint diambil dari discovery document issuer
+type OIDCProvider struct {
+	name        string
+	displayName string
+	config      config.OAuthProviderConfig
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+}
+
+func newOIDCProvider(name string, displayName string, providerConfig config.OAuthProviderConfig) *OIDCProvider {
+	return &OIDCProvider{
+		name:        name,
+		displayName: displayName,
+		config:      providerConfig,
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+func (p *OIDCProvider) DisplayName() string {
+	return p.displayName
+}
+
+func (p *OIDCProvider) AuthCodeURL(state string) (string, error) {
+	oauthConfig, _, err := p.oauthConfig(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	return oauthConfig.AuthCodeURL(state), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string) (*Identity, error) {
+	ctx = withHTTPClient(ctx)
+
+	oauthConfig, discovery, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauthConfig.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	if discovery.UserinfoEndpoint == "" {
+		return nil, errors.New("provider does not expose userinfo endpoint")
+	}
+
+	var claims struct {
+		Subject           string      `json:"sub"`
+		Email             string      `json:"email"`
+		EmailVerified     interface{} `json:"email_verified"`
+		Name              string      `json:"name"`
+		PreferredUsername string      `json:"preferred_username"`
+		Picture           string      `json:"picture"`
+	}
+	if err := getJSON(ctx, oauthConfig.Client(ctx, token), discovery.UserinfoEndpoint, &claims); err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("user info does not contain subject")
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+		Username:      claims.PreferredUsername,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+// oauthConfig melakukan discovery sekali, kalau gagal dicoba lagi di request berikutnya
+func (p *OIDCProvider) oauthConfig(ctx context.Context) (*oauth2.Config, *oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery == nil {
+		discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+
+		var discovery oidcDiscovery
+		if err := getJSON(withHTTPClient(ctx), httpClient, discoveryURL, &discovery); err != nil {
+			return nil, nil, fmt.Errorf("failed to discover %s: %w", p.config.Issuer, err)
+		}
+		if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+			return nil, nil, fmt.Errorf("discovery document of %s is incomplete", p.config.Issuer)
+		}
+		p.discovery = &discovery
+	}
+
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  p.discovery.AuthorizationEndpoint,
+			TokenURL: p.discovery.TokenEndpoint,
+		},
+	}, p.discovery, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// isTrue beberapa provider mengirim email_verified sebagai string
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,112 @@
+// Package oauth berisi provider login pihak ketiga (OIDC generic via discovery dan GitHub)
+// yang didaftarkan dari config oauth.providers.
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"golang.org/x/oauth2"
+)
+
+var ErrProviderNotFound = errors.New("oauth provider not found")
+
+// Identity data user yang dikembalikan provider setelah login berhasil
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	Picture       string
+}
+
+type Provider interface {
+	Name() string
+	DisplayName() string
+	AuthCodeURL(state string) (string, error)
+	Exchange(ctx context.Context, code string) (*Identity, error)
+}
+
+type ProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type Registry struct {
+	providers map[string]Provider
+}
+
+var registry *Registry
+
+// httpClient dipakai untuk discovery, token exchange dan userinfo supaya request ke provider tidak menggantung
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+func InitProviders(providers map[string]config.OAuthProviderConfig) {
+	registry = &Registry{providers: make(map[string]Provider, len(providers))}
+
+	for name, providerConfig := range providers {
+		provider, err := newProvider(name, providerConfig)
+		if err != nil {
+			log.Fatalf("failed to initialize oauth provider %s: %v", name, err)
+		}
+		registry.providers[name] = provider
+	}
+}
+
+func GetRegistry() *Registry {
+	if registry == nil {
+		log.Fatal("oauth providers are not initialized")
+	}
+	return registry
+}
+
+func (r *Registry) Get(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return provider, nil
+}
+
+func (r *Registry) List() []ProviderInfo {
+	result := make([]ProviderInfo, 0, len(r.providers))
+	for _, provider := range r.providers {
+		result = append(result, ProviderInfo{Name: provider.Name(), DisplayName: provider.DisplayName()})
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].Name < result[b].Name })
+	return result
+}
+
+func newProvider(name string, providerConfig config.OAuthProviderConfig) (Provider, error) {
+	if providerConfig.ClientID == "" {
+		return nil, errors.New("client_id is required")
+	}
+
+	displayName := providerConfig.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+
+	switch providerConfig.Type {
+	case config.OAuthProviderOIDC, "":
+		if providerConfig.Issuer == "" {
+			return nil, errors.New("issuer is required for oidc provider")
+		}
+		return newOIDCProvider(name, displayName, providerConfig), nil
+	case config.OAuthProviderGitHub:
+		return newGitHubProvider(name, displayName, providerConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown provider type %q", providerConfig.Type)
+	}
+}
+
+func withHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+}
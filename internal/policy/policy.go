@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// rolePermissions satu-satunya tempat pemetaan role ke permission, cek akses di handler/service
+// memakai permission bukan membandingkan role secara langsung
+var rolePermissions = map[enum.UserRole][]enum.Permission{
+	enum.RoleReader: {},
+	enum.RoleAuthor: {},
+	enum.RoleEditor: {
+		enum.PermissionPostPublish,
+		enum.PermissionPostEditAny,
+		enum.PermissionPostArchiveAny,
+		enum.PermissionCommentModerate,
+		enum.PermissionCommentDeleteAny,
+	},
+	enum.RoleAdmin: {
+		enum.PermissionPostPublish,
+		enum.PermissionPostEditAny,
+		enum.PermissionPostDeleteAny,
+		enum.PermissionPostArchiveAny,
+		enum.PermissionCommentModerate,
+		enum.PermissionCommentDeleteAny,
+		enum.PermissionCategoryManage,
+		enum.PermissionUserManage,
+		enum.PermissionUserBan,
+		enum.PermissionSecurityManage,
+	},
+}
+
+// PermissionsOf daftar permission milik role, role yang tidak dikenal tidak punya permission
+func PermissionsOf(role enum.UserRole) []enum.Permission {
+	permissions := rolePermissions[role]
+
+	result := make([]enum.Permission, len(permissions))
+	copy(result, permissions)
+
+	return result
+}
+
+func HasPermission(role enum.UserRole, permission enum.Permission) bool {
+	for _, owned := range rolePermissions[role] {
+		if owned == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func Can(user utils.Claims, permission enum.Permission) bool {
+	return HasPermission(enum.UserRole(user.Role), permission)
+}
+
+// CanActOn pemilik resource selalu boleh, user lain butuh permission ".any"
+func CanActOn(user utils.Claims, anyPermission enum.Permission, ownerId int64) bool {
+	if ownerId != 0 && ownerId == int64(user.UserId) {
+		return true
+	}
+	return Can(user, anyPermission)
+}
+
+func Authorize(user utils.Claims, permission enum.Permission) error {
+	if !Can(user, permission) {
+		return exception.NewForbiddenErr("You do not have permission to access this resource")
+	}
+	return nil
+}
+
+// AuthorizeOwned seperti CanActOn tapi mengembalikan error forbidden dengan pesan dari pemanggil
+func AuthorizeOwned(user utils.Claims, anyPermission enum.Permission, ownerId int64, message string) error {
+	if !CanActOn(user, anyPermission, ownerId) {
+		return exception.NewForbiddenErr(message)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package qrcode
+
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+// penaltyScore menghitung penalty sesuai spesifikasi untuk memilih mask terbaik
+func (qr *Code) penaltyScore() int {
+	result := 0
+
+	for y := 0; y < qr.Size; y++ {
+		result += linePenalty(func(i int) bool { return qr.Modules[y][i] }, qr.Size)
+	}
+	for x := 0; x < qr.Size; x++ {
+		result += linePenalty(func(i int) bool { return qr.Modules[i][x] }, qr.Size)
+	}
+
+	for y := 0; y < qr.Size-1; y++ {
+		for x := 0; x < qr.Size-1; x++ {
+			color := qr.Modules[y][x]
+			if color == qr.Modules[y][x+1] && color == qr.Modules[y+1][x] && color == qr.Modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	dark := 0
+	for _, row := range qr.Modules {
+		for _, module := range row {
+			if module {
+				dark++
+			}
+		}
+	}
+	total := qr.Size * qr.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+
+	return result
+}
+
+// linePenalty menghitung rule 1 (deretan warna sama) dan rule 3 (pola mirip finder) untuk satu baris/kolom
+func linePenalty(module func(i int) bool, size int) int {
+	result := 0
+
+	runColor, runLen := false, 0
+	for i := 0; i < size; i++ {
+		if i > 0 && module(i) == runColor {
+			runLen++
+			if runLen == 5 {
+				result += penaltyN1
+			} else if runLen > 5 {
+				result++
+			}
+			continue
+		}
+		runColor, runLen = module(i), 1
+	}
+
+	// di luar simbol dianggap terang (quiet zone)
+	at := func(i int) bool { return i >= 0 && i < size && module(i) }
+	finderLike := []bool{true, false, true, true, true, false, true}
+	for start := -4; start+len(finderLike) <= size+4; start++ {
+		matched := true
+		for j, dark := range finderLike {
+			if at(start+j) != dark {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		lightBefore, lightAfter := true, true
+		for j := 1; j <= 4; j++ {
+			lightBefore = lightBefore && !at(start-j)
+			lightAfter = lightAfter && !at(start+len(finderLike)-1+j)
+		}
+		if lightBefore || lightAfter {
+			result += penaltyN3
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,327 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// format bits level M untuk mask 0..7, dari tabel ISO/IEC 18004
+var formatBitsM = [8]int{
+	0b101010000010010,
+	0b101000100100101,
+	0b101111001111100,
+	0b101101101001011,
+	0b100010111111001,
+	0b100000011001110,
+	0b100111110010111,
+	0b100101010100000,
+}
+
+// version information bits dari tabel ISO/IEC 18004
+var versionBits = map[int]int{
+	7:  0x07C94,
+	8:  0x085BC,
+	10: 0x0A4D3,
+}
+
+func TestReedSolomonRemainderGolden(t *testing.T) {
+	// contoh "HELLO WORLD" version 1-M dari thonky.com QR tutorial
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	got := reedSolomonRemainder(data, reedSolomonDivisor(len(want)))
+	if !bytes.Equal(got, want) {
+		t.Fatalf("ecc = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		text    string
+		version int
+	}{
+		{"empty", "", 1},
+		{"short", "hello", 1},
+		{"totp uri", "otpauth://totp/Blog:user%40example.com?algorithm=SHA1&digits=6&issuer=Blog&period=30&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", 8},
+		{"version 7", strings.Repeat("0123456789abcdef", 7), 7},
+		{"version 10", strings.Repeat("x", 200), 10},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			qr, err := Encode(tc.text)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			if want := tc.version*4 + 17; qr.Size != want {
+				t.Fatalf("size = %d, want %d (version %d)", qr.Size, want, tc.version)
+			}
+
+			got, err := decode(qr)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tc.text {
+				t.Fatalf("decoded %q, want %q", got, tc.text)
+			}
+		})
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("a", 2400)); !errors.Is(err, ErrDataTooLong) {
+		t.Fatalf("err = %v, want ErrDataTooLong", err)
+	}
+}
+
+func TestPNG(t *testing.T) {
+	qr, err := Encode("hello")
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	const scale = 3
+	content, err := qr.PNG(scale)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+
+	size := (qr.Size + quietZone*2) * scale
+	if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+		t.Fatalf("bounds = %v, want %dx%d", b, size, size)
+	}
+
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			r, _, _, _ := img.At((x+quietZone)*scale+scale/2, (y+quietZone)*scale+scale/2).RGBA()
+			if dark := r == 0; dark != qr.Modules[y][x] {
+				t.Fatalf("pixel for module (%d,%d) dark = %v, want %v", x, y, dark, qr.Modules[y][x])
+			}
+		}
+	}
+
+	// quiet zone harus putih
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Fatal("quiet zone is not white")
+	}
+}
+
+// decode membaca ulang matrix mengikuti spesifikasi: cek finder dan format/version bits,
+// buka mask, baca codeword zigzag, pisahkan block, cek ecc lalu parse segmen mode byte
+func decode(qr *Code) (string, error) {
+	version := (qr.Size - 17) / 4
+	if version < minVersion || version > maxVersion || version*4+17 != qr.Size {
+		return "", errors.New("invalid size")
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {qr.Size - 7, 0}, {0, qr.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				dist := max(abs(dx-3), abs(dy-3))
+				if qr.Modules[corner[1]+dy][corner[0]+dx] != (dist != 2) {
+					return "", errors.New("finder pattern mismatch")
+				}
+			}
+		}
+	}
+
+	mask, err := readFormat(qr)
+	if err != nil {
+		return "", err
+	}
+
+	if version >= 7 {
+		bits := 0
+		for i := 17; i >= 0; i-- {
+			bits <<= 1
+			if qr.Modules[qr.Size-11+i%3][i/3] {
+				bits |= 1
+			}
+		}
+		if bits>>12 != version {
+			return "", errors.New("version bits mismatch")
+		}
+		if want, ok := versionBits[version]; ok && bits != want {
+			return "", errors.New("version bits differ from spec table")
+		}
+	}
+
+	reserved := newCode(version)
+	reserved.drawFunctionPatterns()
+
+	rawCodewords := numRawDataModules(version) / 8
+	raw := make([]byte, 0, rawCodewords)
+	var cur byte
+	n := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < qr.Size; vert++ {
+			y := vert
+			if upward {
+				y = qr.Size - 1 - vert
+			}
+			for x := right; x > right-2; x-- {
+				if reserved.isFunction[y][x] {
+					continue
+				}
+				bit := qr.Modules[y][x] != maskBit(mask, x, y)
+				cur <<= 1
+				if bit {
+					cur |= 1
+				}
+				n++
+				if n%8 == 0 {
+					raw = append(raw, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+	if len(raw) < rawCodewords {
+		return "", errors.New("not enough codewords")
+	}
+	raw = raw[:rawCodewords]
+
+	numBlocks := numErrorCorrectionBlocks[version]
+	eccLen := eccCodewordsPerBlock[version]
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortDataLen := rawCodewords/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for j := range blocks {
+			if i == shortDataLen && j < numShortBlocks {
+				continue
+			}
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+	var data []byte
+	divisor := reedSolomonDivisor(eccLen)
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+	for _, block := range blocks {
+		dat, ecc := block[:len(block)-eccLen], block[len(block)-eccLen:]
+		if !bytes.Equal(reedSolomonRemainder(dat, divisor), ecc) {
+			return "", errors.New("ecc mismatch")
+		}
+		data = append(data, dat...)
+	}
+
+	reader := bitReader{data: data}
+	if reader.read(4) != 0x4 {
+		return "", errors.New("not byte mode")
+	}
+	length := reader.read(charCountBits(version))
+	if 4+charCountBits(version)+length*8 > len(data)*8 {
+		return "", errors.New("length exceeds capacity")
+	}
+	text := make([]byte, length)
+	for i := range text {
+		text[i] = byte(reader.read(8))
+	}
+	return string(text), nil
+}
+
+func readFormat(qr *Code) (int, error) {
+	first, second := 0, 0
+	for i := 14; i >= 0; i-- {
+		first <<= 1
+		second <<= 1
+
+		var x, y int
+		switch {
+		case i <= 5:
+			x, y = 8, i
+		case i == 6:
+			x, y = 8, 7
+		case i == 7:
+			x, y = 8, 8
+		case i == 8:
+			x, y = 7, 8
+		default:
+			x, y = 14-i, 8
+		}
+		if qr.Modules[y][x] {
+			first |= 1
+		}
+
+		if i < 8 {
+			x, y = qr.Size-1-i, 8
+		} else {
+			x, y = 8, qr.Size-15+i
+		}
+		if qr.Modules[y][x] {
+			second |= 1
+		}
+	}
+
+	if first != second {
+		return 0, errors.New("format copies differ")
+	}
+	if !qr.Modules[qr.Size-8][8] {
+		return 0, errors.New("dark module missing")
+	}
+	for mask, bits := range formatBitsM {
+		if bits == first {
+			return mask, nil
+		}
+	}
+	return 0, errors.New("unknown format bits")
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value <<= 1
+		if (r.data[r.pos>>3]>>(7-uint(r.pos&7)))&1 != 0 {
+			value |= 1
+		}
+		r.pos++
+	}
+	return value
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// AccountRepository query lintas tabel untuk export data pribadi dan penghapusan akun
+type AccountRepository interface {
+	FindDueDeletions(now time.Time, limit int) ([]models.User, error)
+	FindPostsByAuthor(userId int64) ([]models.Post, error)
+	FindPostAssets(postIds []int64) ([]models.PostAsset, error)
+	FindCommentsByUser(userId int64) ([]models.Comment, error)
+	FindLikesByUser(userId int64) ([]models.Like, error)
+	FindFollows(userId int64) ([]models.Follower, error)
+	FindReadingLists(userId int64) ([]models.ReadingList, error)
+	FindSavedPosts(userId int64) ([]models.SavedPost, error)
+	FindSubscriptions(userId int64) ([]models.Subscription, error)
+	DeleteAccount(userId int64, opts dto.AccountDeletionOptions) error
+}
+
+type AccountRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &AccountRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *AccountRepositoryImpl) FindDueDeletions(now time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+
+	if err := r.DB.
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Where("status <> ?", enum.UserStatusDeleted).
+		Order("deletion_scheduled_at asc").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return users, nil
+}
+
+func (r *AccountRepositoryImpl) FindPostsByAuthor(userId int64) ([]models.Post, error) {
+	var posts []models.Post
+
+	if err := r.DB.
+		Preload("Tags").
+		Where("author_id = ?", userId).
+		Order("id asc").
+		Find(&posts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+func (r *AccountRepositoryImpl) FindPostAssets(postIds []int64) ([]models.PostAsset, error) {
+	var assets []models.PostAsset
+	if len(postIds) == 0 {
+		return assets, nil
+	}
+
+	if err := r.DB.
+		Where("post_id IN ?", postIds).
+		Order("post_id asc, order_index asc").
+		Find(&assets).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return assets, nil
+}
+
+func (r *AccountRepositoryImpl) FindCommentsByUser(userId int64) ([]models.Comment, error) {
+	var comments []models.Comment
+
+	if err := r.DB.Where("user_id = ?", userId).Order("id asc").Find(&comments).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return comments, nil
+}
+
+func (r *AccountRepositoryImpl) FindLikesByUser(userId int64) ([]models.Like, error) {
+	var likes []models.Like
+
+	if err := r.DB.Where("user_id = ?", userId).Order("id asc").Find(&likes).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return likes, nil
+}
+
+// FindFollows relasi follow dua arah, user sebagai follower maupun yang di-follow
+func (r *AccountRepositoryImpl) FindFollows(userId int64) ([]models.Follower, error) {
+	var follows []models.Follower
+
+	if err := r.DB.
+		Where("follower_id = ? OR following_id = ?", userId, userId).
+		Order("id asc").
+		Find(&follows).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return follows, nil
+}
+
+func (r *AccountRepositoryImpl) FindReadingLists(userId int64) ([]models.ReadingList, error) {
+	var readingLists []models.ReadingList
+
+	if err := r.DB.Where("user_id = ?", userId).Order("order_index asc, id asc").Find(&readingLists).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return readingLists, nil
+}
+
+func (r *AccountRepositoryImpl) FindSavedPosts(userId int64) ([]models.SavedPost, error) {
+	var savedPosts []models.SavedPost
+
+	if err := r.DB.Where("user_id = ?", userId).Order("id asc").Find(&savedPosts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return savedPosts, nil
+}
+
+func (r *AccountRepositoryImpl) FindSubscriptions(userId int64) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+
+	if err := r.DB.Where("user_id = ?", userId).Order("id asc").Find(&subscriptions).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteAccount menghapus data pribadi user dalam satu transaksi.
+// Komentar tetap tampil atas nama opts.DeletedName, riwayat pembayaran (subscriptions) dan security log tidak dihapus.
+func (r *AccountRepositoryImpl) DeleteAccount(userId int64, opts dto.AccountDeletionOptions) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if opts.DeletePosts {
+			if err := deletePostsByAuthor(tx, userId); err != nil {
+				return err
+			}
+		} else if opts.ReassignTo > 0 {
+			if err := tx.Model(&models.Post{}).
+				Where("author_id = ?", userId).
+				Update("author_id", opts.ReassignTo).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Series{}).
+				Where("author_id = ?", userId).
+				Update("author_id", opts.ReassignTo).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Comment{}).
+			Where("user_id = ?", userId).
+			Updates(map[string]interface{}{
+				"user_id":    nil,
+				"name":       opts.DeletedName,
+				"email":      nil,
+				"ip_address": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userId).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("follower_id = ? OR following_id = ?", userId, userId).Delete(&models.Follower{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? OR target_id = ?", userId, userId).Delete(&models.UserBlock{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.SavedPost{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.ReadingList{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).
+			Where("id IN (?) AND follower_count > 0", tx.Model(&models.CategoryFollower{}).Select("category_id").Where("user_id = ?", userId)).
+			UpdateColumn("follower_count", gorm.Expr("follower_count - 1")).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&models.UserSession{},
+			&models.PersonalAccessToken{},
+			&models.UserIdentity{},
+			&models.UserTwoFactor{},
+			&models.UserRecoveryCode{},
+			&models.UserOtp{},
+			&models.UsernameHistory{},
+			&models.DataExport{},
+			&models.FeedItem{},
+			&models.CategoryFollower{},
+		} {
+			if err := tx.Where("user_id = ?", userId).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("attempt_key = ?", fmt.Sprintf("account:%d", userId)).Delete(&models.LoginAttempt{}).Error; err != nil {
+			return err
+		}
+
+		if opts.Purge {
+			return tx.Delete(&models.User{}, userId).Error
+		}
+
+		// email dan username diganti placeholder unik supaya bisa dipakai daftar ulang
+		return tx.Model(&models.User{}).
+			Where("id = ?", userId).
+			Updates(map[string]interface{}{
+				"name":                  opts.DeletedName,
+				"username":              fmt.Sprintf("deleted_%d", userId),
+				"email":                 fmt.Sprintf("deleted_%d@deleted.invalid", userId),
+				"password":              "",
+				"bio":                   nil,
+				"profile_image_uri":     nil,
+				"social_links":          nil,
+				"role":                  int(enum.RoleReader),
+				"status":                int(enum.UserStatusDeleted),
+				"auth_provider":         "local",
+				"is_subscribed":         false,
+				"subscription_end":      nil,
+				"email_verified_at":     nil,
+				"username_changed_at":   nil,
+				"deletion_scheduled_at": nil,
+			}).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// deletePostsByAuthor menghapus post beserta komentar, like, tag, asset, revisi, simpanan reading list dan series-nya
+func deletePostsByAuthor(tx *gorm.DB, userId int64) error {
+	postIds := tx.Model(&models.Post{}).Select("id").Where("author_id = ?", userId)
+	var categoryIds []int64
+	if err := tx.Model(&models.Post{}).Distinct("category_id").Where("author_id = ? AND category_id IS NOT NULL", userId).Pluck("category_id", &categoryIds).Error; err != nil {
+		return err
+	}
+	commentIds := tx.Model(&models.Comment{}).Select("id").Where("post_id IN (?)", postIds)
+
+	if err := tx.Where("target_type = ? AND target_id IN (?)", enum.LikeTargetComment, commentIds).Delete(&models.Like{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id IN (?)", enum.LikeTargetPost, postIds).Delete(&models.Like{}).Error; err != nil {
+		return err
+	}
+
+	for _, model := range []interface{}{
+		&models.Comment{},
+		&models.PostTag{},
+		&models.PostAsset{},
+		&models.PostRevision{},
+		&models.PostStatusEvent{},
+		&models.SavedPost{},
+		&models.FeedItem{},
+		&models.SeriesPost{},
+	} {
+		if err := tx.Where("post_id IN (?)", postIds).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Where("author_id = ?", userId).Delete(&models.Post{}).Error; err != nil {
+		return err
+	}
+	// isi series selalu post milik author sendiri, jadi series-nya sudah kosong
+	if err := tx.Where("author_id = ?", userId).Delete(&models.Series{}).Error; err != nil {
+		return err
+	}
+
+	if len(categoryIds) == 0 {
+		return nil
+	}
+	return refreshCategoryPostCount(tx, categoryIds)
+}
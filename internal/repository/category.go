@@ -4,9 +4,11 @@ import (
 	"errors"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository interface {
@@ -16,6 +18,11 @@ type CategoryRepository interface {
 	Update(id int, data map[string]interface{}) error
 	DeleteById(id int) error
 	FindByName(name string) (*models.Category, error)
+	FindAllForTree() ([]models.Category, error)
+	FindSubtreeIds(rootId int64) ([]int64, error)
+	SetParent(id int64, parentId *int64) error
+	DeleteWithMode(id int64, mode enum.CategoryDeleteMode) ([]int64, error)
+	RecountAll() error
 }
 
 type CategoryRepositoryImpl struct {
@@ -106,3 +113,201 @@ func (r *CategoryRepositoryImpl) FindByName(name string) (*models.Category, erro
 
 	return &categoryDetail, nil
 }
+
+func (r *CategoryRepositoryImpl) FindAllForTree() ([]models.Category, error) {
+	var categories []models.Category
+
+	if err := r.DB.Order("name asc, id asc").Find(&categories).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return categories, nil
+}
+
+// FindSubtreeIds id kategori beserta seluruh turunannya
+func (r *CategoryRepositoryImpl) FindSubtreeIds(rootId int64) ([]int64, error) {
+	ids, err := findCategorySubtreeIds(r.DB, rootId)
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return ids, nil
+}
+
+// SetParent memindahkan kategori beserta subtree-nya ke bawah parentId (nil = root).
+// Semua baris kategori dikunci supaya dua pemindahan bersamaan tidak bisa membentuk siklus.
+func (r *CategoryRepositoryImpl) SetParent(id int64, parentId *int64) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var categories []models.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&categories).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		parents := make(map[int64]*int64, len(categories))
+		for _, category := range categories {
+			parents[category.ID] = category.ParentID
+		}
+
+		if _, ok := parents[id]; !ok {
+			return exception.NewNotFoundErr("category not found")
+		}
+
+		if parentId != nil {
+			if _, ok := parents[*parentId]; !ok {
+				return exception.NewBadRequestErr("parent category not found")
+			}
+			if isCategoryAncestor(parents, id, *parentId) {
+				return exception.NewBadRequestErr("category cannot be moved under itself or its descendants")
+			}
+		}
+
+		if err := tx.Model(&models.Category{}).Where("id = ?", id).Update("parent_id", parentId).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// DeleteWithMode reparent: sub kategori dan post pindah ke parent kategori yang dihapus (atau jadi root/tanpa kategori).
+// cascade: seluruh subtree dihapus dan post di dalamnya menjadi tanpa kategori.
+// Mengembalikan id post yang kategorinya berubah supaya index pencarian bisa diperbarui.
+func (r *CategoryRepositoryImpl) DeleteWithMode(id int64, mode enum.CategoryDeleteMode) ([]int64, error) {
+	var postIds []int64
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return exception.NewNotFoundErr("category not found")
+			}
+			return exception.NewGormDBErr(err)
+		}
+
+		ids := []int64{id}
+		if mode == enum.CategoryDeleteCascade {
+			subtree, err := findCategorySubtreeIds(tx, id)
+			if err != nil {
+				return exception.NewGormDBErr(err)
+			}
+			ids = subtree
+		} else {
+			if err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Update("parent_id", category.ParentID).Error; err != nil {
+				return exception.NewGormDBErr(err)
+			}
+		}
+
+		var newCategoryId *int64
+		if mode == enum.CategoryDeleteReparent {
+			newCategoryId = category.ParentID
+		}
+
+		if err := tx.Model(&models.Post{}).Where("category_id IN ?", ids).Pluck("id", &postIds).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		if err := tx.Model(&models.Post{}).Where("category_id IN ?", ids).UpdateColumn("category_id", newCategoryId).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		if newCategoryId != nil {
+			if err := refreshCategoryPostCount(tx, []int64{*newCategoryId}); err != nil {
+				return exception.NewGormDBErr(err)
+			}
+		}
+		if err := tx.Where("category_id IN ?", ids).Delete(&models.CategoryFollower{}).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		if mode == enum.CategoryDeleteCascade {
+			// MySQL mengecek FK parent_id per baris sesuai urutan delete, jadi relasi di dalam subtree
+			// diputus dulu supaya parent tidak ditolak karena child-nya belum terhapus
+			if err := tx.Model(&models.Category{}).Where("id IN ?", ids).Update("parent_id", nil).Error; err != nil {
+				return exception.NewGormDBErr(err)
+			}
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&models.Category{}).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return postIds, nil
+}
+
+// findCategorySubtreeIds BFS di memori, jumlah kategori kecil jadi cukup satu query tanpa recursive CTE
+func findCategorySubtreeIds(db *gorm.DB, rootId int64) ([]int64, error) {
+	var categories []models.Category
+	if err := db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	children := make(map[int64][]int64, len(categories))
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []int64{rootId}
+	visited := map[int64]bool{rootId: true}
+	for i := 0; i < len(ids); i++ {
+		for _, childId := range children[ids[i]] {
+			// data lama bisa saja sudah punya siklus, jangan sampai loop tanpa akhir
+			if visited[childId] {
+				continue
+			}
+			visited[childId] = true
+			ids = append(ids, childId)
+		}
+	}
+
+	return ids, nil
+}
+
+// isCategoryAncestor true kalau ancestorId adalah categoryId sendiri atau salah satu leluhurnya
+func isCategoryAncestor(parents map[int64]*int64, ancestorId int64, categoryId int64) bool {
+	visited := map[int64]bool{}
+	current := &categoryId
+
+	for current != nil && !visited[*current] {
+		if *current == ancestorId {
+			return true
+		}
+		visited[*current] = true
+		current = parents[*current]
+	}
+
+	return false
+}
+
+// RecountAll menghitung ulang semua counter kategori, dipakai saat start untuk mengisi data lama dan memperbaiki selisih
+func (r *CategoryRepositoryImpl) RecountAll() error {
+	if err := r.DB.Session(&gorm.Session{AllowGlobalUpdate: true}).
+		Model(&models.Category{}).
+		UpdateColumns(map[string]interface{}{
+			"follower_count": gorm.Expr("(SELECT COUNT(*) FROM category_followers WHERE category_followers.category_id = categories.id)"),
+			"published_post_count": gorm.Expr(
+				"(SELECT COUNT(*) FROM posts WHERE posts.category_id = categories.id AND posts.status = ?)",
+				enum.PostStatusPublished,
+			),
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// refreshCategoryPostCount menghitung ulang published_post_count kategori yang terdampak perubahan post.
+// categoryIds boleh slice id atau subquery; hitung ulang dipilih daripada +1/-1 supaya transisi status apa pun tetap akurat.
+func refreshCategoryPostCount(db *gorm.DB, categoryIds interface{}) error {
+	return db.Model(&models.Category{}).
+		Where("id IN (?)", categoryIds).
+		UpdateColumn("published_post_count", gorm.Expr(
+			"(SELECT COUNT(*) FROM posts WHERE posts.category_id = categories.id AND posts.status = ?)",
+			enum.PostStatusPublished,
+		)).Error
+}
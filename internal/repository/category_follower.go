@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CategoryFollowerRepository interface {
+	// Follow false berarti user sudah mengikuti kategori ini sebelumnya
+	Follow(userId int64, categoryId int64) (bool, error)
+	// Unfollow false berarti user memang tidak mengikuti kategori ini
+	Unfollow(userId int64, categoryId int64) (bool, error)
+	FindAllByUserId(userId int64) ([]dto.FollowedCategoryResponse, error)
+}
+
+type CategoryFollowerRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewCategoryFollowerRepository(db *gorm.DB) CategoryFollowerRepository {
+	return &CategoryFollowerRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Follow follower_count hanya bertambah kalau baris follow benar-benar baru
+func (r *CategoryFollowerRepositoryImpl) Follow(userId int64, categoryId int64) (bool, error) {
+	created := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.CategoryFollower{
+			UserID:     userId,
+			CategoryID: categoryId,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		created = true
+		return tx.Model(&models.Category{}).
+			Where("id = ?", categoryId).
+			UpdateColumn("follower_count", gorm.Expr("follower_count + 1")).Error
+	})
+	if err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return created, nil
+}
+
+func (r *CategoryFollowerRepositoryImpl) Unfollow(userId int64, categoryId int64) (bool, error) {
+	deleted := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND category_id = ?", userId, categoryId).Delete(&models.CategoryFollower{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		deleted = true
+		return tx.Model(&models.Category{}).
+			Where("id = ? AND follower_count > 0", categoryId).
+			UpdateColumn("follower_count", gorm.Expr("follower_count - 1")).Error
+	})
+	if err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return deleted, nil
+}
+
+func (r *CategoryFollowerRepositoryImpl) FindAllByUserId(userId int64) ([]dto.FollowedCategoryResponse, error) {
+	categories := make([]dto.FollowedCategoryResponse, 0)
+
+	if err := r.DB.
+		Table("category_followers cf").
+		Select(
+			"c.id",
+			"c.name",
+			"c.slug",
+			"COALESCE(c.description, '') AS `desc`",
+			"COALESCE(c.parent_id, 0) AS parent_id",
+			"c.follower_count",
+			"c.published_post_count",
+			"cf.created_at AS followed_at",
+		).
+		Joins("JOIN categories c ON c.id = cf.category_id").
+		Where("cf.user_id = ?", userId).
+		Order("cf.created_at desc").
+		Scan(&categories).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return categories, nil
+}
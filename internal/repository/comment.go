@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"github.com/MrBista/blog-api/internal/utils"
@@ -11,6 +14,21 @@ import (
 type CommentRepository interface {
 	FindAllCommentByPostId(filter dto.CommentFilterRequest) (*dto.PaginationResult, error)
 	Create(comment *models.Comment) error
+	FindById(postId int64, id int64) (*models.Comment, error)
+	GetCommentById(id int64) (*models.Comment, error)
+	Update(id int64, data map[string]interface{}) error
+
+	FindTopLevelComments(filter dto.CommentTreeFilterRequest) ([]dto.CommentWithUserResponse, int64, error)
+	FindRepliesByParentIds(parentIds []int64, limit int, viewerId int) ([]dto.CommentWithUserResponse, error)
+	FindRepliesAfter(parentId int64, afterId int64, limit int, viewerId int) ([]dto.CommentWithUserResponse, error)
+	CountRepliesByParentIds(parentIds []int64, viewerId int) (map[int64]int64, error)
+
+	CountRecentByAuthor(userId *int64, email *string, since time.Time, excludeId int64) (int64, error)
+	CountDuplicateByAuthor(userId *int64, email *string, content string, since time.Time, excludeId int64) (int64, error)
+	CountApprovedByAuthor(userId *int64, email *string) (int64, error)
+	CountRecentGuestByIP(ip string, since time.Time) (int64, error)
+	FindModerationQueue(filter dto.CommentModerationFilterRequest) (*dto.PaginationResult, error)
+	UpdateStatusByIds(ids []int64, fromStatus enum.CommentStatus, data map[string]interface{}) (int64, error)
 }
 
 type CommentRepositoryImpl struct {
@@ -23,6 +41,20 @@ func NewCommentRepository(db *gorm.DB) CommentRepository {
 	}
 }
 
+// visibleCommentStatuses komentar yang dihapus tetap ditampilkan sebagai tombstone supaya thread tidak putus
+var visibleCommentStatuses = []enum.CommentStatus{enum.CommentStatusActive, enum.CommentStatusDeleted}
+
+func commentSelectClause(viewerId int) []string {
+	selectClause := []string{
+		"comments.*",
+		"(SELECT COUNT(*) FROM likes WHERE likes.target_id = comments.id AND likes.target_type = 2) AS like_count",
+	}
+	if viewerId != 0 {
+		selectClause = append(selectClause, likedByMeSelect(enum.LikeTargetComment, "comments.id", viewerId))
+	}
+	return selectClause
+}
+
 func (r *CommentRepositoryImpl) FindAllCommentByPostId(filter dto.CommentFilterRequest) (*dto.PaginationResult, error) {
 	var comments []dto.CommentWithUserResponse
 	var total int64
@@ -36,18 +68,141 @@ func (r *CommentRepositoryImpl) FindAllCommentByPostId(filter dto.CommentFilterR
 	if filter.ParentId != 0 {
 		baseQuery = baseQuery.Where("parent_id = ?", filter.ParentId)
 	} else {
-		baseQuery = baseQuery.Where("parent_id IS NOT NULL")
+		baseQuery = baseQuery.Where("parent_id IS NULL")
 	}
+	baseQuery = baseQuery.Where("status IN ?", visibleCommentStatuses)
+	baseQuery = excludeHiddenUsers(baseQuery, "comments.user_id", filter.ViewerID)
 
 	if err := baseQuery.Count(&total).Error; err != nil {
 		return nil, exception.NewGormDBErr(err)
 	}
 
-	query := applyPagination(baseQuery, filter.PaginationParams)
+	query := applyPagination(baseQuery.Select(commentSelectClause(filter.ViewerID)), filter.PaginationParams)
 	if err := query.Find(&comments).Error; err != nil {
 		return nil, exception.NewGormDBErr(err)
 	}
 
+	if err := r.attachCommentUsers(comments); err != nil {
+		return nil, err
+	}
+
+	for i := range comments {
+		comments[i].MaskDeleted()
+	}
+
+	return dto.NewPaginationResult(comments, total, filter.Page, filter.PageSize, "comments"), nil
+}
+
+func (r *CommentRepositoryImpl) FindTopLevelComments(filter dto.CommentTreeFilterRequest) ([]dto.CommentWithUserResponse, int64, error) {
+	comments := make([]dto.CommentWithUserResponse, 0)
+	var total int64
+
+	baseQuery := r.DB.Model(&models.Comment{}).
+		Where("post_id = ? AND parent_id IS NULL", filter.PostId).
+		Where("status IN ?", visibleCommentStatuses)
+	baseQuery = excludeHiddenUsers(baseQuery, "comments.user_id", filter.ViewerID)
+
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, exception.NewGormDBErr(err)
+	}
+
+	query := applyPagination(baseQuery.Select(commentSelectClause(filter.ViewerID)), filter.PaginationParams)
+	if err := query.Find(&comments).Error; err != nil {
+		return nil, 0, exception.NewGormDBErr(err)
+	}
+
+	if err := r.attachCommentUsers(comments); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// FindRepliesByParentIds mengambil maksimal limit balasan pertama untuk setiap parent dalam satu query
+func (r *CommentRepositoryImpl) FindRepliesByParentIds(parentIds []int64, limit int, viewerId int) ([]dto.CommentWithUserResponse, error) {
+	comments := make([]dto.CommentWithUserResponse, 0)
+	if len(parentIds) == 0 {
+		return comments, nil
+	}
+
+	selectClause := append(
+		commentSelectClause(viewerId),
+		"ROW_NUMBER() OVER (PARTITION BY comments.parent_id ORDER BY comments.id) AS reply_rank",
+	)
+
+	ranked := r.DB.Model(&models.Comment{}).
+		Select(selectClause).
+		Where("parent_id IN ?", parentIds).
+		Where("status IN ?", visibleCommentStatuses)
+	ranked = excludeHiddenUsers(ranked, "comments.user_id", viewerId)
+
+	if err := r.DB.
+		Table("(?) AS ranked", ranked).
+		Where("reply_rank <= ?", limit).
+		Order("id").
+		Find(&comments).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	if err := r.attachCommentUsers(comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepositoryImpl) FindRepliesAfter(parentId int64, afterId int64, limit int, viewerId int) ([]dto.CommentWithUserResponse, error) {
+	comments := make([]dto.CommentWithUserResponse, 0)
+
+	query := r.DB.Model(&models.Comment{}).
+		Select(commentSelectClause(viewerId)).
+		Where("parent_id = ? AND id > ?", parentId, afterId).
+		Where("status IN ?", visibleCommentStatuses)
+
+	if err := excludeHiddenUsers(query, "comments.user_id", viewerId).
+		Order("id").
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	if err := r.attachCommentUsers(comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+func (r *CommentRepositoryImpl) CountRepliesByParentIds(parentIds []int64, viewerId int) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(parentIds))
+	if len(parentIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ParentID int64
+		Total    int64
+	}
+
+	query := r.DB.Model(&models.Comment{}).
+		Select("parent_id, COUNT(*) AS total").
+		Where("parent_id IN ?", parentIds).
+		Where("status IN ?", visibleCommentStatuses)
+
+	if err := excludeHiddenUsers(query, "comments.user_id", viewerId).
+		Group("parent_id").
+		Scan(&rows).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	for _, row := range rows {
+		counts[row.ParentID] = row.Total
+	}
+
+	return counts, nil
+}
+
+func (r *CommentRepositoryImpl) attachCommentUsers(comments []dto.CommentWithUserResponse) error {
 	userIDs := []int64{}
 	for _, comment := range comments {
 		if comment.UserID != nil {
@@ -55,29 +210,31 @@ func (r *CommentRepositoryImpl) FindAllCommentByPostId(filter dto.CommentFilterR
 		}
 	}
 
-	if len(userIDs) > 0 {
-		var users []dto.UserBriefResponse
-		if err := r.DB.Table("users").
-			Where("id IN ?", userIDs).
-			Find(&users).Error; err != nil {
-			return nil, exception.NewGormDBErr(err)
-		}
+	if len(userIDs) == 0 {
+		return nil
+	}
 
-		userMap := make(map[int64]*dto.UserBriefResponse)
-		for i := range users {
-			userMap[users[i].ID] = &users[i]
-		}
+	var users []dto.UserBriefResponse
+	if err := r.DB.Table("users").
+		Where("id IN ?", userIDs).
+		Find(&users).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
 
-		for i := range comments {
-			if comments[i].UserID != nil {
-				if user, exists := userMap[*comments[i].UserID]; exists {
-					comments[i].User = user
-				}
+	userMap := make(map[int64]*dto.UserBriefResponse)
+	for i := range users {
+		userMap[users[i].ID] = &users[i]
+	}
+
+	for i := range comments {
+		if comments[i].UserID != nil {
+			if user, exists := userMap[*comments[i].UserID]; exists {
+				comments[i].User = user
 			}
 		}
 	}
 
-	return dto.NewPaginationResult(comments, total, filter.Page, filter.PageSize, "comments"), nil
+	return nil
 }
 
 func (r *CommentRepositoryImpl) Create(comment *models.Comment) error {
@@ -88,3 +245,155 @@ func (r *CommentRepositoryImpl) Create(comment *models.Comment) error {
 
 	return nil
 }
+
+func (r *CommentRepositoryImpl) FindById(postId int64, id int64) (*models.Comment, error) {
+	var comment models.Comment
+
+	if err := r.DB.Where("id = ? AND post_id = ?", id, postId).First(&comment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("comment not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &comment, nil
+}
+
+func (r *CommentRepositoryImpl) Update(id int64, data map[string]interface{}) error {
+	if err := r.DB.Model(&models.Comment{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// authorScope membatasi query ke komentar milik user login atau tamu dengan email yang sama
+func authorScope(db *gorm.DB, userId *int64, email *string) *gorm.DB {
+	if userId != nil {
+		return db.Where("user_id = ?", *userId)
+	}
+	if email != nil {
+		return db.Where("user_id IS NULL AND email = ?", *email)
+	}
+	return db.Where("1 = 0")
+}
+
+func (r *CommentRepositoryImpl) CountRecentByAuthor(userId *int64, email *string, since time.Time, excludeId int64) (int64, error) {
+	var total int64
+
+	query := authorScope(r.DB.Model(&models.Comment{}), userId, email).
+		Where("created_at >= ? AND id <> ?", since, excludeId)
+
+	if err := query.Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+func (r *CommentRepositoryImpl) CountDuplicateByAuthor(userId *int64, email *string, content string, since time.Time, excludeId int64) (int64, error) {
+	var total int64
+
+	query := authorScope(r.DB.Model(&models.Comment{}), userId, email).
+		Where("created_at >= ? AND content = ? AND id <> ?", since, content, excludeId)
+
+	if err := query.Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+func (r *CommentRepositoryImpl) CountApprovedByAuthor(userId *int64, email *string) (int64, error) {
+	var total int64
+
+	query := authorScope(r.DB.Model(&models.Comment{}), userId, email).
+		Where("status = ?", enum.CommentStatusActive)
+
+	if err := query.Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+func (r *CommentRepositoryImpl) CountRecentGuestByIP(ip string, since time.Time) (int64, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.Comment{}).
+		Where("user_id IS NULL AND ip_address = ? AND created_at >= ?", ip, since).
+		Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+func (r *CommentRepositoryImpl) FindModerationQueue(filter dto.CommentModerationFilterRequest) (*dto.PaginationResult, error) {
+	comments := make([]dto.CommentModerationResponse, 0)
+	var total int64
+
+	query := r.DB.Model(&models.Comment{}).
+		Joins("JOIN posts p ON p.id = comments.post_id").
+		Where("comments.status = ?", filter.Status)
+
+	if filter.PostId != 0 {
+		query = query.Where("comments.post_id = ?", filter.PostId)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	query = query.Select(
+		"comments.*",
+		"p.title AS post_title",
+		"p.slug AS post_slug",
+	)
+
+	query = applyPagination(query, filter.PaginationParams)
+
+	if err := query.Find(&comments).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	userComments := make([]dto.CommentWithUserResponse, 0, len(comments))
+	for _, comment := range comments {
+		userComments = append(userComments, comment.CommentWithUserResponse)
+	}
+	if err := r.attachCommentUsers(userComments); err != nil {
+		return nil, err
+	}
+	for i := range comments {
+		comments[i].User = userComments[i].User
+		comments[i].GuestEmail = comments[i].Email
+	}
+
+	return dto.NewPaginationResult(comments, total, filter.Page, filter.PageSize, "comments"), nil
+}
+
+// UpdateStatusByIds hanya mengubah komentar yang statusnya masih fromStatus, jadi aman kalau dua moderator bertindak bersamaan
+func (r *CommentRepositoryImpl) UpdateStatusByIds(ids []int64, fromStatus enum.CommentStatus, data map[string]interface{}) (int64, error) {
+	res := r.DB.Model(&models.Comment{}).
+		Where("id IN ? AND status = ?", ids, fromStatus).
+		Updates(data)
+
+	if res.Error != nil {
+		return 0, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
+
+func (r *CommentRepositoryImpl) GetCommentById(id int64) (*models.Comment, error) {
+	var comment models.Comment
+
+	if err := r.DB.Where("id = ?", id).First(&comment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("comment not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &comment, nil
+}
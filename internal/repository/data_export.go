@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type DataExportRepository interface {
+	Create(export *models.DataExport) error
+	FindLatestByUserId(userId int64) (*models.DataExport, error)
+	FindAllByUserId(userId int64) ([]models.DataExport, error)
+	FindPending(staleBefore time.Time, limit int) ([]models.DataExport, error)
+	Claim(id int64, staleBefore time.Time) (bool, error)
+	MarkReady(id int64, filePath string, fileSize int64, expiresAt time.Time) error
+	MarkFailed(id int64, message string) error
+	FindExpired(now time.Time, limit int) ([]models.DataExport, error)
+	Delete(id int64) error
+}
+
+type DataExportRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &DataExportRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *DataExportRepositoryImpl) Create(export *models.DataExport) error {
+	if err := r.DB.Create(export).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *DataExportRepositoryImpl) FindLatestByUserId(userId int64) (*models.DataExport, error) {
+	var export models.DataExport
+
+	if err := r.DB.
+		Where("user_id = ?", userId).
+		Order("id desc").
+		First(&export).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("data export not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &export, nil
+}
+
+func (r *DataExportRepositoryImpl) FindAllByUserId(userId int64) ([]models.DataExport, error) {
+	var exports []models.DataExport
+
+	if err := r.DB.Where("user_id = ?", userId).Find(&exports).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return exports, nil
+}
+
+// FindPending job yang belum dikerjakan, termasuk yang macet di processing (misal server mati di tengah jalan)
+func (r *DataExportRepositoryImpl) FindPending(staleBefore time.Time, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+
+	if err := r.DB.
+		Where("status = ? OR (status = ? AND updated_at < ?)", enum.DataExportPending, enum.DataExportProcessing, staleBefore).
+		Order("id asc").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return exports, nil
+}
+
+// Claim mengubah status ke processing, false kalau job sudah diambil proses lain
+func (r *DataExportRepositoryImpl) Claim(id int64, staleBefore time.Time) (bool, error) {
+	resTx := r.DB.Model(&models.DataExport{}).
+		Where("id = ? AND (status = ? OR (status = ? AND updated_at < ?))", id, enum.DataExportPending, enum.DataExportProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     enum.DataExportProcessing,
+			"updated_at": time.Now(),
+		})
+
+	if resTx.Error != nil {
+		return false, exception.NewGormDBErr(resTx.Error)
+	}
+
+	return resTx.RowsAffected == 1, nil
+}
+
+func (r *DataExportRepositoryImpl) MarkReady(id int64, filePath string, fileSize int64, expiresAt time.Time) error {
+	now := time.Now()
+
+	if err := r.DB.Model(&models.DataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       enum.DataExportReady,
+			"file_path":    filePath,
+			"file_size":    fileSize,
+			"completed_at": now,
+			"expires_at":   expiresAt,
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *DataExportRepositoryImpl) MarkFailed(id int64, message string) error {
+	now := time.Now()
+
+	if err := r.DB.Model(&models.DataExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":        enum.DataExportFailed,
+			"error_message": message,
+			"completed_at":  now,
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *DataExportRepositoryImpl) FindExpired(now time.Time, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+
+	if err := r.DB.
+		Where("expires_at IS NOT NULL AND expires_at < ?", now).
+		Order("id asc").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return exports, nil
+}
+
+func (r *DataExportRepositoryImpl) Delete(id int64) error {
+	if err := r.DB.Delete(&models.DataExport{}, id).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// FeedRepository feed "following": fan-out-on-read dihitung dari followers/category_followers,
+// fan-out-on-write dibaca dari inbox feed_items yang diisi worker
+type FeedRepository interface {
+	FindFromFollows(filter dto.FeedFilterRequest) ([]dto.PostResponse, error)
+	FindFromInbox(filter dto.FeedFilterRequest) ([]dto.PostResponse, error)
+
+	FindPostsToFanout(since time.Time, limit int) ([]models.Post, error)
+	Fanout(post models.Post, now time.Time) error
+	BackfillAuthor(userId int64, authorId int64, since time.Time, limit int) error
+	RemoveAuthor(userId int64, authorId int64) error
+	BackfillCategory(userId int64, categoryId int64, since time.Time, limit int) error
+	RemoveCategory(userId int64, categoryId int64) error
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+type FeedRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) FeedRepository {
+	return &FeedRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *FeedRepositoryImpl) FindFromFollows(filter dto.FeedFilterRequest) ([]dto.PostResponse, error) {
+	posts := make([]dto.PostResponse, 0)
+
+	query := r.DB.Model(&models.Post{}).
+		Where("posts.status = ? AND posts.published_at IS NOT NULL", enum.PostStatusPublished).
+		Where("posts.author_id <> ?", filter.ViewerID).
+		Where(
+			"(posts.author_id IN (SELECT following_id FROM followers WHERE follower_id = ?) OR posts.category_id IN (SELECT category_id FROM category_followers WHERE user_id = ?))",
+			filter.ViewerID, filter.ViewerID,
+		)
+	query = excludeHiddenUsers(query, "posts.author_id", filter.ViewerID)
+	query = applyFeedCursor(query, "posts.published_at", "posts.id", filter)
+
+	if err := feedSelect(query, "posts.published_at", filter.ViewerID).
+		Order("posts.published_at desc, posts.id desc").
+		Limit(filter.Limit).
+		Scan(&posts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+// FindFromInbox urutan memakai published_at salinan di feed_items supaya index (user_id, published_at) terpakai
+func (r *FeedRepositoryImpl) FindFromInbox(filter dto.FeedFilterRequest) ([]dto.PostResponse, error) {
+	posts := make([]dto.PostResponse, 0)
+
+	query := r.DB.Table("feed_items fi").
+		Joins("JOIN posts ON posts.id = fi.post_id").
+		Where("fi.user_id = ?", filter.ViewerID).
+		Where("posts.status = ?", enum.PostStatusPublished)
+	query = excludeHiddenUsers(query, "fi.author_id", filter.ViewerID)
+	query = applyFeedCursor(query, "fi.published_at", "fi.post_id", filter)
+
+	if err := feedSelect(query, "fi.published_at", filter.ViewerID).
+		Order("fi.published_at desc, fi.post_id desc").
+		Limit(filter.Limit).
+		Scan(&posts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+func (r *FeedRepositoryImpl) FindPostsToFanout(since time.Time, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	if err := r.DB.
+		Where("status = ? AND feed_fanout_at IS NULL", enum.PostStatusPublished).
+		Where("published_at IS NOT NULL AND published_at >= ?", since).
+		Order("published_at asc, id asc").
+		Limit(limit).
+		Find(&posts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+// Fanout menyalin post ke inbox follower author dan follower kategorinya, lalu menandai post sudah disebar.
+// INSERT IGNORE membuat Fanout aman diulang kalau worker berhenti di tengah jalan.
+func (r *FeedRepositoryImpl) Fanout(post models.Post, now time.Time) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT IGNORE INTO feed_items (user_id, post_id, author_id, published_at, created_at)
+			SELECT follower_id, ?, ?, ?, ? FROM followers
+			WHERE following_id = ? AND follower_id <> ?`,
+			post.ID, post.AuthorID, post.PublishedAt, now,
+			post.AuthorID, post.AuthorID,
+		).Error; err != nil {
+			return err
+		}
+
+		if post.CategoryID != nil {
+			if err := tx.Exec(`
+				INSERT IGNORE INTO feed_items (user_id, post_id, author_id, published_at, created_at)
+				SELECT user_id, ?, ?, ?, ? FROM category_followers
+				WHERE category_id = ? AND user_id <> ?`,
+				post.ID, post.AuthorID, post.PublishedAt, now,
+				*post.CategoryID, post.AuthorID,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.Post{}).Where("id = ?", post.ID).UpdateColumn("feed_fanout_at", now).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// BackfillAuthor mengisi inbox dengan post terbaru author yang baru di-follow
+func (r *FeedRepositoryImpl) BackfillAuthor(userId int64, authorId int64, since time.Time, limit int) error {
+	if err := r.DB.Exec(`
+		INSERT IGNORE INTO feed_items (user_id, post_id, author_id, published_at, created_at)
+		SELECT ?, id, author_id, published_at, ? FROM posts
+		WHERE author_id = ? AND status = ? AND published_at IS NOT NULL AND published_at >= ?
+		ORDER BY published_at DESC
+		LIMIT ?`,
+		userId, time.Now(),
+		authorId, enum.PostStatusPublished, since,
+		limit,
+	).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// RemoveAuthor post yang masih masuk lewat kategori yang di-follow tetap disimpan
+func (r *FeedRepositoryImpl) RemoveAuthor(userId int64, authorId int64) error {
+	if err := r.DB.
+		Where("user_id = ? AND author_id = ?", userId, authorId).
+		Where("post_id NOT IN (?)", r.DB.
+			Table("posts p").
+			Select("p.id").
+			Joins("JOIN category_followers cf ON cf.category_id = p.category_id").
+			Where("cf.user_id = ? AND p.author_id = ?", userId, authorId),
+		).
+		Delete(&models.FeedItem{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// BackfillCategory mengisi inbox dengan post terbaru kategori yang baru di-follow, post milik user sendiri dilewati
+func (r *FeedRepositoryImpl) BackfillCategory(userId int64, categoryId int64, since time.Time, limit int) error {
+	if err := r.DB.Exec(`
+		INSERT IGNORE INTO feed_items (user_id, post_id, author_id, published_at, created_at)
+		SELECT ?, id, author_id, published_at, ? FROM posts
+		WHERE category_id = ? AND author_id <> ? AND status = ? AND published_at IS NOT NULL AND published_at >= ?
+		ORDER BY published_at DESC
+		LIMIT ?`,
+		userId, time.Now(),
+		categoryId, userId, enum.PostStatusPublished, since,
+		limit,
+	).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// RemoveCategory post dari author yang masih di-follow tetap disimpan
+func (r *FeedRepositoryImpl) RemoveCategory(userId int64, categoryId int64) error {
+	if err := r.DB.
+		Where("user_id = ?", userId).
+		Where("post_id IN (?)", r.DB.Model(&models.Post{}).Select("id").Where("category_id = ?", categoryId)).
+		Where("author_id NOT IN (?)", r.DB.Model(&models.Follower{}).Select("following_id").Where("follower_id = ?", userId)).
+		Delete(&models.FeedItem{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *FeedRepositoryImpl) DeleteOlderThan(before time.Time) (int64, error) {
+	res := r.DB.Where("published_at < ?", before).Delete(&models.FeedItem{})
+
+	if res.Error != nil {
+		return 0, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected, nil
+}
+
+// applyFeedCursor keyset pagination (published_at, id) supaya post baru tidak menggeser halaman berikutnya
+func applyFeedCursor(db *gorm.DB, publishedColumn string, idColumn string, filter dto.FeedFilterRequest) *gorm.DB {
+	if filter.AfterPublishedAt == nil {
+		return db
+	}
+
+	return db.Where(
+		"("+publishedColumn+" < ? OR ("+publishedColumn+" = ? AND "+idColumn+" < ?))",
+		*filter.AfterPublishedAt, *filter.AfterPublishedAt, filter.AfterID,
+	)
+}
+
+func feedSelect(db *gorm.DB, publishedColumn string, viewerId int) *gorm.DB {
+	return db.
+		Joins("LEFT JOIN users AS author on author.id = posts.author_id").
+		Joins("LEFT JOIN categories as c on c.id = posts.category_id").
+		Select([]string{
+			"posts.id",
+			"posts.title",
+			"posts.slug",
+			"posts.content",
+			"posts.main_image_uri",
+			"posts.status",
+			"posts.created_at",
+			"posts.updated_at",
+			publishedColumn + " AS published_at",
+			"posts.allow_guest_comments",
+			"posts.author_id",
+			"author.name AS AuthorDetail_name",
+			"author.id AS AuthorDetail_id",
+			"c.name AS CategoryDetail_name",
+			"c.id AS CategoryDetail_id",
+			"c.slug AS CategoryDetail_slug",
+			"c.description AS CategoryDetail_desc",
+			"c.parent_id AS CategoryDetail_parentId",
+			"c.follower_count AS CategoryDetail_follower_count",
+			"c.published_post_count AS CategoryDetail_published_post_count",
+			"(SELECT COUNT(*) FROM likes WHERE likes.target_id = posts.id AND likes.target_type = 1) AS like_count",
+			likedByMeSelect(enum.LikeTargetPost, "posts.id", viewerId),
+		})
+}
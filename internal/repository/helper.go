@@ -14,3 +14,12 @@ func applyPagination(db *gorm.DB, params dto.PaginationParams) *gorm.DB {
 
 	return query
 }
+
+// excludeHiddenUsers menyembunyikan baris yang column-nya user yang diblokir atau di-mute viewer (0 = anonim)
+func excludeHiddenUsers(db *gorm.DB, column string, viewerId int) *gorm.DB {
+	if viewerId == 0 {
+		return db
+	}
+
+	return db.Where("("+column+" IS NULL OR "+column+" NOT IN (SELECT target_id FROM user_blocks WHERE user_id = ?))", viewerId)
+}
@@ -1,14 +1,107 @@
 package repository
 
 import (
+	"fmt"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LikeRepository interface {
 	Create(like models.Like) error
+	Delete(userId int64, targetType enum.LikeTarget, targetId int64) error
+	CountByTarget(targetType enum.LikeTarget, targetId int64) (int64, error)
+	FindAllByUser(filter dto.LikeFilterRequest) (*dto.PaginationResult, error)
 }
 
 type LikeRepositoryImpl struct {
 	DB *gorm.DB
 }
+
+func NewLikeRepository(db *gorm.DB) LikeRepository {
+	return &LikeRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create idempotent, like yang sudah ada di-skip oleh unique index (user, target_type, target_id)
+func (r *LikeRepositoryImpl) Create(like models.Like) error {
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&like).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *LikeRepositoryImpl) Delete(userId int64, targetType enum.LikeTarget, targetId int64) error {
+	if err := r.DB.
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userId, targetType, targetId).
+		Delete(&models.Like{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *LikeRepositoryImpl) CountByTarget(targetType enum.LikeTarget, targetId int64) (int64, error) {
+	var total int64
+
+	if err := r.DB.
+		Model(&models.Like{}).
+		Where("target_type = ? AND target_id = ?", targetType, targetId).
+		Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+func (r *LikeRepositoryImpl) FindAllByUser(filter dto.LikeFilterRequest) (*dto.PaginationResult, error) {
+	likes := make([]dto.LikeResponse, 0)
+	var total int64
+
+	query := r.DB.
+		Table("likes").
+		Joins("LEFT JOIN comments cm ON likes.target_type = ? AND cm.id = likes.target_id", enum.LikeTargetComment).
+		Joins("JOIN posts p ON p.id = CASE WHEN likes.target_type = ? THEN likes.target_id ELSE cm.post_id END", enum.LikeTargetPost).
+		Where("likes.user_id = ?", filter.UserID)
+
+	if filter.TargetType != 0 {
+		query = query.Where("likes.target_type = ?", filter.TargetType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	query = query.Select(
+		"likes.id",
+		"likes.target_type",
+		"likes.target_id",
+		"p.id AS post_id",
+		"p.title AS post_title",
+		"p.slug AS post_slug",
+		"cm.content AS comment_content",
+		"likes.created_at",
+	)
+
+	query = applyPagination(query, filter.PaginationParams)
+
+	if err := query.Scan(&likes).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return dto.NewPaginationResult(likes, total, filter.Page, filter.PageSize, "likes"), nil
+}
+
+// likedByMeSelect membuat kolom liked_by_me untuk viewer, viewerId berupa angka jadi aman di-format langsung
+func likedByMeSelect(targetType enum.LikeTarget, targetColumn string, viewerId int) string {
+	return fmt.Sprintf(
+		"EXISTS(SELECT 1 FROM likes lm WHERE lm.target_id = %s AND lm.target_type = %d AND lm.user_id = %d) AS liked_by_me",
+		targetColumn, targetType, viewerId,
+	)
+}
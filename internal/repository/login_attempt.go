@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	FindByKey(key string) (*models.LoginAttempt, error)
+	FindLocked(now time.Time) ([]models.LoginAttempt, error)
+	RegisterFailure(key string, now time.Time, window time.Duration, lockAfter int, lockFor time.Duration) (*models.LoginAttempt, error)
+	Delete(key string) (bool, error)
+}
+
+type LoginAttemptRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *LoginAttemptRepositoryImpl) FindByKey(key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+
+	if err := r.DB.Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("login attempt not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &attempt, nil
+}
+
+func (r *LoginAttemptRepositoryImpl) FindLocked(now time.Time) ([]models.LoginAttempt, error) {
+	attempts := make([]models.LoginAttempt, 0)
+
+	if err := r.DB.
+		Where("locked_until > ?", now).
+		Order("locked_until desc").
+		Find(&attempts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return attempts, nil
+}
+
+// RegisterFailure menambah counter dalam satu statement supaya aman dipanggil bersamaan dari beberapa process.
+// Counter mulai dari 1 lagi kalau kegagalan terakhir sudah lewat window, dan dikunci begitu mencapai lockAfter.
+// Urutan assignment penting: MySQL memakai nilai failures yang baru saat menghitung locked_until.
+func (r *LoginAttemptRepositoryImpl) RegisterFailure(key string, now time.Time, window time.Duration, lockAfter int, lockFor time.Duration) (*models.LoginAttempt, error) {
+	lockedUntil := now.Add(lockFor)
+
+	err := r.DB.Exec(`
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at, locked_until, updated_at)
+		VALUES (?, 1, ?, IF(1 >= ?, ?, NULL), ?)
+		ON DUPLICATE KEY UPDATE
+			failures = IF(last_failure_at < ?, 1, failures + 1),
+			locked_until = IF(failures >= ?, ?, locked_until),
+			last_failure_at = VALUES(last_failure_at),
+			updated_at = VALUES(updated_at)`,
+		key, now, lockAfter, lockedUntil, now,
+		now.Add(-window),
+		lockAfter, lockedUntil,
+	).Error
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return r.FindByKey(key)
+}
+
+func (r *LoginAttemptRepositoryImpl) Delete(key string) (bool, error) {
+	res := r.DB.Where("attempt_key = ?", key).Delete(&models.LoginAttempt{})
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
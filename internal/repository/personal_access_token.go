@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+// lastUsedThrottle supaya last_used_at tidak di-update di setiap request
+const lastUsedThrottle = time.Minute
+
+type PersonalAccessTokenRepository interface {
+	Create(token *models.PersonalAccessToken) error
+	FindActiveByHash(tokenHash string) (*models.PersonalAccessToken, error)
+	FindAllByUserId(userId int64) ([]models.PersonalAccessToken, error)
+	RevokeByUser(id int64, userId int64) (bool, error)
+	TouchLastUsed(id int64) error
+}
+
+type PersonalAccessTokenRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *PersonalAccessTokenRepositoryImpl) Create(token *models.PersonalAccessToken) error {
+	if err := r.DB.Create(token).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *PersonalAccessTokenRepositoryImpl) FindActiveByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+
+	err := r.DB.
+		Preload("User").
+		Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&token).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("token not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &token, nil
+}
+
+func (r *PersonalAccessTokenRepositoryImpl) FindAllByUserId(userId int64) ([]models.PersonalAccessToken, error) {
+	tokens := make([]models.PersonalAccessToken, 0)
+
+	if err := r.DB.
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Order("created_at desc").
+		Find(&tokens).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return tokens, nil
+}
+
+func (r *PersonalAccessTokenRepositoryImpl) RevokeByUser(id int64, userId int64) (bool, error) {
+	res := r.DB.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userId).
+		Update("revoked_at", time.Now())
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *PersonalAccessTokenRepositoryImpl) TouchLastUsed(id int64) error {
+	now := time.Now()
+
+	if err := r.DB.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-lastUsedThrottle)).
+		UpdateColumn("last_used_at", now).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
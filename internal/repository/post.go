@@ -5,10 +5,12 @@ import (
 	"time"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"github.com/MrBista/blog-api/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PostRepository interface {
@@ -16,7 +18,7 @@ type PostRepository interface {
 	FindAllPostWithPaging(filter dto.PostFilterRequest) (*dto.PaginationResult, error)
 	GetDetailPost(slug string) (*models.Post, error)
 	GetPostById(id int64) (*models.Post, error)
-	CreatePost(post *models.Post) error
+	CreatePost(post *models.Post, tagIds []int64) error
 	UpdatePost(slug string, data map[string]interface{}) error
 	DeletePost(slug string) error
 	GetDetailPostWithFilter(slug string, filter dto.PostFilterRequest) (*dto.PostResponse, error)
@@ -25,6 +27,16 @@ type PostRepository interface {
 
 	CountPostByUserThisMonth(userId int) (int64, error)
 
+	FindDueScheduledPosts(now time.Time, limit int) ([]models.Post, error)
+	PublishScheduledPost(id int64, now time.Time) (bool, error)
+	UpdatePostStatus(id int64, fromStatus enum.PostStatus, data map[string]interface{}) (bool, error)
+	ApplyPostUpdate(id int64, fromStatus enum.PostStatus, update PostUpdate) error
+
+	FindPostForIndex(id int64) (*models.Post, error)
+	FindPublishedPostsAfter(afterId int64, limit int) ([]models.Post, error)
+	FindPublishedPostVersionsAfter(afterId int64, limit int) ([]models.Post, error)
+	FindPublishedPostsByIds(ids []int64) ([]models.Post, error)
+
 	GetReadingLists(userID int64) ([]dto.ReadingListDTO, error)
 	GetReadingListByID(userID, listID int64) (*dto.ReadingListDTO, error)
 	CreateReadingList(readingListModel *models.ReadingList) error
@@ -99,7 +111,7 @@ func (r *PostRepositoryImpl) GetDetailPostWithFilter(slug string, filter dto.Pos
 	// tx := r.DB.Take(&post, "slug like ?", "%"+slug+"%")
 
 	query := r.DB.Model(&models.Post{})
-	query = query.Where("slug = ?", slug)
+	query = query.Where("posts.slug = ?", slug)
 
 	if filter.IncludeAuthor == 1 {
 		query = query.Joins("LEFT JOIN users AS author on author.Id = posts.author_id")
@@ -118,6 +130,9 @@ func (r *PostRepositoryImpl) GetDetailPostWithFilter(slug string, filter dto.Pos
 		"posts.status",
 		"posts.created_at",
 		"posts.updated_at",
+		"posts.published_at",
+		"posts.scheduled_at",
+		"posts.allow_guest_comments",
 		"posts.author_id", // Tetap ambil author_id dari tabel post
 	}
 
@@ -136,6 +151,8 @@ func (r *PostRepositoryImpl) GetDetailPostWithFilter(slug string, filter dto.Pos
 			"c.slug AS CategoryDetail_slug",
 			"c.description AS CategoryDetail_desc",
 			"c.parent_id AS CategoryDetail_parentId",
+			"c.follower_count AS CategoryDetail_follower_count",
+			"c.published_post_count AS CategoryDetail_published_post_count",
 		)
 	}
 
@@ -145,6 +162,10 @@ func (r *PostRepositoryImpl) GetDetailPostWithFilter(slug string, filter dto.Pos
 		selectClause = append(selectClause, likeSubQuery)
 	}
 
+	if filter.ViewerID != 0 {
+		selectClause = append(selectClause, likedByMeSelect(enum.LikeTargetPost, "posts.id", filter.ViewerID))
+	}
+
 	query = query.Select(selectClause)
 
 	if err := query.Scan(&post).Error; err != nil {
@@ -155,14 +176,21 @@ func (r *PostRepositoryImpl) GetDetailPostWithFilter(slug string, filter dto.Pos
 
 }
 
-func (r *PostRepositoryImpl) CreatePost(post *models.Post) error {
-	txRes := r.DB.Create(post)
+// CreatePost menyimpan post dan tag-nya dalam satu transaksi supaya tidak ada post tanpa tag yang diminta
+func (r *PostRepositoryImpl) CreatePost(post *models.Post, tagIds []int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
 
-	if txRes.Error != nil {
-		return exception.NewGormDBErr(txRes.Error)
-	}
+		if len(tagIds) > 0 {
+			if err := replacePostTags(tx, post.ID, tagIds); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 func (r *PostRepositoryImpl) UpdatePost(slug string, data map[string]interface{}) error {
@@ -177,10 +205,27 @@ func (r *PostRepositoryImpl) UpdatePost(slug string, data map[string]interface{}
 }
 
 func (r *PostRepositoryImpl) DeletePost(slug string) error {
-	rxRes := r.DB.Where("slug = ?", slug).Delete(&models.Post{})
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var categoryIds []int64
+		if err := tx.Model(&models.Post{}).Where("slug = ? AND category_id IS NOT NULL", slug).Pluck("category_id", &categoryIds).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("post_id IN (?)", tx.Model(&models.Post{}).Select("id").Where("slug = ?", slug)).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("slug = ?", slug).Delete(&models.Post{}).Error; err != nil {
+			return err
+		}
 
-	if rxRes.Error != nil {
-		return exception.NewGormDBErr(rxRes.Error)
+		if len(categoryIds) == 0 {
+			return nil
+		}
+		return refreshCategoryPostCount(tx, categoryIds)
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
 	}
 
 	return nil
@@ -194,19 +239,41 @@ func (r *PostRepositoryImpl) FindAllPostWithPaging(filter dto.PostFilterRequest)
 	query := r.DB.Model(&models.Post{})
 
 	if filter.AuthorID != 0 {
-		query.Where("author_id = ?", filter.AuthorID)
+		query = query.Where("posts.author_id = ?", filter.AuthorID)
+	} else {
+		// feed umum tidak menampilkan post dari user yang diblokir/di-mute viewer, halaman author tetap utuh
+		query = excludeHiddenUsers(query, "posts.author_id", filter.ViewerID)
 	}
 
-	if filter.CategoryID != 0 {
-		query.Where("category_id = ?", filter.CategoryID)
+	if filter.CategoryID != 0 && filter.IncludeSubcategories == 1 {
+		categoryIds, err := findCategorySubtreeIds(r.DB, int64(filter.CategoryID))
+		if err != nil {
+			return nil, exception.NewGormDBErr(err)
+		}
+		query = query.Where("posts.category_id IN ?", categoryIds)
+	} else if filter.CategoryID != 0 {
+		query = query.Where("posts.category_id = ?", filter.CategoryID)
 	}
 
 	if filter.Title != "" {
-		query.Where("title like ?", "%"+filter.Title+"%")
+		query = query.Where("posts.title like ?", "%"+filter.Title+"%")
 	}
 
 	if filter.Status != 0 {
-		query.Where("status = ?", filter.Status)
+		query = query.Where("posts.status = ?", filter.Status)
+	}
+
+	if filter.Unapproved == 1 {
+		query = query.Where("posts.approved_by IS NULL")
+	}
+
+	if filter.Tag != "" {
+		query = query.Where("posts.id IN (?)", r.DB.
+			Table("post_tags pt").
+			Select("pt.post_id").
+			Joins("JOIN tags t ON t.id = pt.tag_id").
+			Where("t.slug = ?", filter.Tag),
+		)
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -230,6 +297,9 @@ func (r *PostRepositoryImpl) FindAllPostWithPaging(filter dto.PostFilterRequest)
 		"posts.status",
 		"posts.created_at",
 		"posts.updated_at",
+		"posts.published_at",
+		"posts.scheduled_at",
+		"posts.allow_guest_comments",
 		"posts.author_id", // Tetap ambil author_id dari tabel post
 	}
 
@@ -248,6 +318,8 @@ func (r *PostRepositoryImpl) FindAllPostWithPaging(filter dto.PostFilterRequest)
 			"c.slug AS CategoryDetail_slug",
 			"c.description AS CategoryDetail_desc",
 			"c.parent_id AS CategoryDetail_parentId",
+			"c.follower_count AS CategoryDetail_follower_count",
+			"c.published_post_count AS CategoryDetail_published_post_count",
 		)
 	}
 
@@ -257,6 +329,10 @@ func (r *PostRepositoryImpl) FindAllPostWithPaging(filter dto.PostFilterRequest)
 		selectClause = append(selectClause, likeSubQuery)
 	}
 
+	if filter.ViewerID != 0 {
+		selectClause = append(selectClause, likedByMeSelect(enum.LikeTargetPost, "posts.id", filter.ViewerID))
+	}
+
 	query = query.Select(selectClause)
 
 	query = applyPagination(query, filter.PaginationParams)
@@ -298,6 +374,224 @@ func (r *PostRepositoryImpl) CountPostByUserThisMonth(userId int) (int64, error)
 	return total, nil
 }
 
+func (r *PostRepositoryImpl) FindDueScheduledPosts(now time.Time, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := r.DB.
+		Where("scheduled_at IS NOT NULL AND scheduled_at <= ?", now).
+		Where("status = ? AND approved_by IS NOT NULL", enum.PostStatusReview).
+		Order("scheduled_at").
+		Limit(limit).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+// PublishScheduledPost memakai update bersyarat, jadi kalau beberapa proses (prefork) mengambil
+// post yang sama hanya satu yang berhasil dan mendapat true
+func (r *PostRepositoryImpl) PublishScheduledPost(id int64, now time.Time) (bool, error) {
+	published := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Post{}).
+			Where("id = ?", id).
+			Where("scheduled_at IS NOT NULL AND scheduled_at <= ?", now).
+			Where("status = ? AND approved_by IS NOT NULL", enum.PostStatusReview).
+			Updates(map[string]interface{}{
+				"status":       enum.PostStatusPublished,
+				"published_at": now,
+				"scheduled_at": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		published = result.RowsAffected > 0
+		if !published {
+			return nil
+		}
+		return refreshCategoryPostCount(tx, postCategorySubQuery(tx, id))
+	})
+	if err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return published, nil
+}
+
+// UpdatePostStatus hanya mengubah post yang statusnya masih fromStatus, false berarti status sudah berubah
+func (r *PostRepositoryImpl) UpdatePostStatus(id int64, fromStatus enum.PostStatus, data map[string]interface{}) (bool, error) {
+	updated := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&models.Post{}).
+			Where("id = ? AND status = ?", id, fromStatus).
+			Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		updated = result.RowsAffected > 0
+		if !updated {
+			return nil
+		}
+		return refreshCategoryPostCount(tx, postCategorySubQuery(tx, id))
+	})
+	if err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return updated, nil
+}
+
+// PostUpdate perubahan dari UpdatePost yang harus tersimpan bersama atau tidak sama sekali
+type PostUpdate struct {
+	Data map[string]interface{}
+	// StatusEvent diisi kalau Data juga mengubah status
+	StatusEvent *models.PostStatusEvent
+	// Revision nomornya dialokasikan di dalam transaksi, Baseline dipakai kalau post belum punya revisi
+	Revision *models.PostRevision
+	Baseline *models.PostRevision
+	// TagIDs nil berarti tag tidak diubah
+	TagIDs []int64
+}
+
+// ApplyPostUpdate mengunci baris post, memastikan statusnya masih fromStatus, lalu menyimpan
+// perubahan kolom, event status, revisi dan tag dalam satu transaksi
+func (r *PostRepositoryImpl) ApplyPostUpdate(id int64, fromStatus enum.PostStatus, update PostUpdate) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockPostStatus(tx, id, fromStatus); err != nil {
+			return err
+		}
+
+		if len(update.Data) > 0 {
+			if err := tx.Model(&models.Post{}).Where("id = ?", id).Updates(update.Data).Error; err != nil {
+				return exception.NewGormDBErr(err)
+			}
+		}
+
+		if update.StatusEvent != nil {
+			if err := refreshCategoryPostCount(tx, postCategorySubQuery(tx, id)); err != nil {
+				return exception.NewGormDBErr(err)
+			}
+			if err := tx.Create(update.StatusEvent).Error; err != nil {
+				return exception.NewGormDBErr(err)
+			}
+		}
+
+		if update.Revision != nil {
+			if err := insertPostRevision(tx, update.Baseline, update.Revision); err != nil {
+				return err
+			}
+		}
+
+		if update.TagIDs != nil {
+			if err := replacePostTags(tx, id, update.TagIDs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// lockPostStatus mengambil row lock post (SELECT ... FOR UPDATE) sampai transaksi selesai
+func lockPostStatus(tx *gorm.DB, id int64, fromStatus enum.PostStatus) error {
+	var post models.Post
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		Where("id = ?", id).
+		First(&post).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return exception.NewNotFoundErr("post not found")
+		}
+		return exception.NewGormDBErr(err)
+	}
+
+	if enum.PostStatus(post.Status) != fromStatus {
+		return exception.NewBusnissLogicErr("post status has been changed by someone else, please reload")
+	}
+
+	return nil
+}
+
+func postCategorySubQuery(db *gorm.DB, postId int64) *gorm.DB {
+	return db.Model(&models.Post{}).Select("category_id").Where("id = ?", postId)
+}
+
+func (r *PostRepositoryImpl) FindPostForIndex(id int64) (*models.Post, error) {
+	var post models.Post
+
+	if err := r.DB.Preload("Author").Preload("Category").Where("id = ?", id).First(&post).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("post not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &post, nil
+}
+
+func (r *PostRepositoryImpl) FindPublishedPostsAfter(afterId int64, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := r.DB.
+		Preload("Author").
+		Preload("Category").
+		Where("id > ? AND status = ?", afterId, enum.PostStatusPublished).
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+// FindPublishedPostVersionsAfter hanya mengambil id dan updated_at untuk rekonsiliasi index pencarian
+func (r *PostRepositoryImpl) FindPublishedPostVersionsAfter(afterId int64, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := r.DB.
+		Select("id", "updated_at").
+		Where("id > ? AND status = ?", afterId, enum.PostStatusPublished).
+		Order("id").
+		Limit(limit).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+func (r *PostRepositoryImpl) FindPublishedPostsByIds(ids []int64) ([]models.Post, error) {
+	var posts []models.Post
+
+	err := r.DB.
+		Preload("Author").
+		Preload("Category").
+		Where("id IN ? AND status = ?", ids, enum.PostStatusPublished).
+		Find(&posts).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
 func (r *PostRepositoryImpl) GetReadingLists(userID int64) ([]dto.ReadingListDTO, error) {
 	var results []dto.ReadingListDTO
 
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostRevisionRepository interface {
+	Create(revision *models.PostRevision) error
+	FindAllByPostId(postId int64) ([]dto.PostRevisionResponse, error)
+	FindById(postId int64, revisionId int64) (*models.PostRevision, error)
+	CountByPostId(postId int64) (int64, error)
+}
+
+type PostRevisionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPostRevisionRepository(db *gorm.DB) PostRevisionRepository {
+	return &PostRevisionRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *PostRevisionRepositoryImpl) Create(revision *models.PostRevision) error {
+	if err := r.DB.Create(revision).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *PostRevisionRepositoryImpl) FindAllByPostId(postId int64) ([]dto.PostRevisionResponse, error) {
+	revisions := make([]dto.PostRevisionResponse, 0)
+
+	err := r.DB.
+		Table("post_revisions pr").
+		Select(`
+			pr.id,
+			pr.post_id,
+			pr.revision_number,
+			pr.title,
+			pr.editor_id,
+			u.name as editor_name,
+			pr.restored_from_id,
+			pr.created_at
+		`).
+		Joins("LEFT JOIN users u ON u.id = pr.editor_id").
+		Where("pr.post_id = ?", postId).
+		Order("pr.revision_number DESC").
+		Scan(&revisions).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return revisions, nil
+}
+
+func (r *PostRevisionRepositoryImpl) FindById(postId int64, revisionId int64) (*models.PostRevision, error) {
+	var revision models.PostRevision
+
+	err := r.DB.
+		Preload("Editor").
+		Where("post_id = ? AND id = ?", postId, revisionId).
+		First(&revision).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("revision not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &revision, nil
+}
+
+func (r *PostRevisionRepositoryImpl) CountByPostId(postId int64) (int64, error) {
+	var count int64
+
+	if err := r.DB.Model(&models.PostRevision{}).Where("post_id = ?", postId).Count(&count).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return count, nil
+}
+
+// insertPostRevision memberi nomor MAX+1 lalu menyimpan revisi, baseline disimpan lebih dulu
+// sebagai revisi pertama kalau post belum punya revisi. Nomor hanya aman dari balapan kalau
+// tx sudah memegang row lock post (lockPostStatus).
+func insertPostRevision(tx *gorm.DB, baseline *models.PostRevision, revision *models.PostRevision) error {
+	var latest int
+	err := tx.
+		Model(&models.PostRevision{}).
+		Select("COALESCE(MAX(revision_number), 0)").
+		Where("post_id = ?", revision.PostID).
+		Scan(&latest).Error
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	if latest == 0 && baseline != nil {
+		baseline.RevisionNumber = 1
+		if err := tx.Create(baseline).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		latest = baseline.RevisionNumber
+	}
+
+	revision.RevisionNumber = latest + 1
+	if err := tx.Create(revision).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
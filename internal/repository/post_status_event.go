@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type PostStatusEventRepository interface {
+	Create(event *models.PostStatusEvent) error
+	FindAllByPostId(postId int64) ([]dto.PostStatusEventResponse, error)
+	FindAllWithPaging(filter dto.PostStatusEventFilterRequest) (*dto.PaginationResult, error)
+}
+
+type PostStatusEventRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewPostStatusEventRepository(db *gorm.DB) PostStatusEventRepository {
+	return &PostStatusEventRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *PostStatusEventRepositoryImpl) Create(event *models.PostStatusEvent) error {
+	if err := r.DB.Create(event).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *PostStatusEventRepositoryImpl) baseQuery() *gorm.DB {
+	return r.DB.
+		Table("post_status_events e").
+		Select(`
+			e.id,
+			e.post_id,
+			p.title as post_title,
+			p.slug as post_slug,
+			e.from_status,
+			e.to_status,
+			e.actor_id,
+			u.name as actor_name,
+			e.comment,
+			e.created_at
+		`).
+		Joins("INNER JOIN posts p ON p.id = e.post_id").
+		Joins("LEFT JOIN users u ON u.id = e.actor_id")
+}
+
+func (r *PostStatusEventRepositoryImpl) FindAllByPostId(postId int64) ([]dto.PostStatusEventResponse, error) {
+	events := make([]dto.PostStatusEventResponse, 0)
+
+	err := r.baseQuery().
+		Where("e.post_id = ?", postId).
+		Order("e.created_at DESC, e.id DESC").
+		Scan(&events).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return events, nil
+}
+
+func (r *PostStatusEventRepositoryImpl) FindAllWithPaging(filter dto.PostStatusEventFilterRequest) (*dto.PaginationResult, error) {
+	events := make([]dto.PostStatusEventResponse, 0)
+	var total int64
+
+	countQuery := r.DB.Model(&models.PostStatusEvent{})
+	query := r.baseQuery()
+
+	if filter.ActorID != 0 {
+		countQuery = countQuery.Where("actor_id = ?", filter.ActorID)
+		query = query.Where("e.actor_id = ?", filter.ActorID)
+	}
+
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	query = applyPagination(query, filter.PaginationParams)
+
+	if err := query.Scan(&events).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return dto.NewPaginationResult(events, total, filter.Page, filter.PageSize, "events"), nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type SecurityLogRepository interface {
+	Create(log *models.SecurityLog) error
+	FindAllWithPagination(filter dto.SecurityLogFilterRequest) (*dto.PaginationResult, error)
+}
+
+type SecurityLogRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSecurityLogRepository(db *gorm.DB) SecurityLogRepository {
+	return &SecurityLogRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *SecurityLogRepositoryImpl) Create(log *models.SecurityLog) error {
+	if err := r.DB.Create(log).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *SecurityLogRepositoryImpl) FindAllWithPagination(filter dto.SecurityLogFilterRequest) (*dto.PaginationResult, error) {
+	logs := make([]models.SecurityLog, 0)
+	var total int64
+
+	query := r.DB.Model(&models.SecurityLog{})
+
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	if err := query.Order("created_at desc").
+		Offset(filter.GetOffset()).
+		Limit(filter.PageSize).
+		Find(&logs).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return dto.NewPaginationResult(logs, total, filter.Page, filter.PageSize, "logs"), nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type SeriesRepository interface {
+	FindAll(filter dto.SeriesFilterRequest) (*dto.PaginationResult, error)
+	FindBySlug(slug string) (*models.Series, error)
+	FindSummaryById(id int64) (*dto.SeriesResponse, error)
+	FindByPostId(postId int64) (*models.Series, error)
+	FindParts(seriesId int64, viewerId int, publishedOnly bool) ([]dto.SeriesPartResponse, error)
+	FindPostsByIds(postIds []int64) ([]models.Post, error)
+	// FindPostsInOtherSeries post dari postIds yang sudah masuk series lain
+	FindPostsInOtherSeries(seriesId int64, postIds []int64) ([]int64, error)
+	Create(series *models.Series, postIds []int64) error
+	Update(id int64, data map[string]interface{}) error
+	ReplacePosts(seriesId int64, postIds []int64) error
+	Delete(id int64) error
+}
+
+type SeriesRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &SeriesRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *SeriesRepositoryImpl) FindAll(filter dto.SeriesFilterRequest) (*dto.PaginationResult, error) {
+	series := make([]dto.SeriesResponse, 0)
+	var total int64
+
+	query := r.DB.Model(&models.Series{})
+
+	if filter.AuthorID != 0 {
+		query = query.Where("series.author_id = ?", filter.AuthorID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	query = seriesSummarySelect(query)
+	query = applyPagination(query, filter.PaginationParams)
+
+	if err := query.Scan(&series).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return dto.NewPaginationResult(series, total, filter.Page, filter.PageSize, "series"), nil
+}
+
+func (r *SeriesRepositoryImpl) FindBySlug(slug string) (*models.Series, error) {
+	var series models.Series
+
+	if err := r.DB.Where("slug = ?", slug).First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewNotFoundErr("series not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &series, nil
+}
+
+func (r *SeriesRepositoryImpl) FindSummaryById(id int64) (*dto.SeriesResponse, error) {
+	var series dto.SeriesResponse
+
+	res := seriesSummarySelect(r.DB.Model(&models.Series{}).Where("series.id = ?", id)).Scan(&series)
+	if res.Error != nil {
+		return nil, exception.NewGormDBErr(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, exception.NewNotFoundErr("series not found")
+	}
+
+	return &series, nil
+}
+
+func (r *SeriesRepositoryImpl) FindByPostId(postId int64) (*models.Series, error) {
+	var series models.Series
+
+	if err := r.DB.
+		Joins("JOIN series_posts sp ON sp.series_id = series.id").
+		Where("sp.post_id = ?", postId).
+		First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewNotFoundErr("series not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &series, nil
+}
+
+// FindParts bagian series berurutan, publishedOnly untuk pembaca selain pemilik series
+func (r *SeriesRepositoryImpl) FindParts(seriesId int64, viewerId int, publishedOnly bool) ([]dto.SeriesPartResponse, error) {
+	parts := make([]dto.SeriesPartResponse, 0)
+
+	selectClause := []string{
+		"sp.position",
+		"p.id AS post_id",
+		"p.title",
+		"p.slug",
+		"p.status",
+		"p.published_at",
+	}
+	if viewerId != 0 {
+		selectClause = append(selectClause, fmt.Sprintf(
+			"EXISTS(SELECT 1 FROM saved_posts s WHERE s.post_id = p.id AND s.user_id = %d AND s.is_read = true) AS is_read",
+			viewerId,
+		))
+	}
+
+	query := r.DB.
+		Table("series_posts sp").
+		Joins("JOIN posts p ON p.id = sp.post_id").
+		Where("sp.series_id = ?", seriesId)
+
+	if publishedOnly {
+		query = query.Where("p.status = ?", enum.PostStatusPublished)
+	}
+
+	if err := query.Select(selectClause).Order("sp.position asc").Scan(&parts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return parts, nil
+}
+
+func (r *SeriesRepositoryImpl) FindPostsByIds(postIds []int64) ([]models.Post, error) {
+	var posts []models.Post
+	if len(postIds) == 0 {
+		return posts, nil
+	}
+
+	if err := r.DB.Select("id", "author_id", "status").Where("id IN ?", postIds).Find(&posts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return posts, nil
+}
+
+func (r *SeriesRepositoryImpl) FindPostsInOtherSeries(seriesId int64, postIds []int64) ([]int64, error) {
+	var conflicts []int64
+	if len(postIds) == 0 {
+		return conflicts, nil
+	}
+
+	if err := r.DB.Model(&models.SeriesPost{}).
+		Where("post_id IN ? AND series_id <> ?", postIds, seriesId).
+		Pluck("post_id", &conflicts).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return conflicts, nil
+}
+
+func (r *SeriesRepositoryImpl) Create(series *models.Series, postIds []int64) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+
+		return insertSeriesPosts(tx, series.ID, postIds)
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *SeriesRepositoryImpl) Update(id int64, data map[string]interface{}) error {
+	if err := r.DB.Model(&models.Series{}).Where("id = ?", id).Updates(data).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *SeriesRepositoryImpl) ReplacePosts(seriesId int64, postIds []int64) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", seriesId).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+
+		if err := insertSeriesPosts(tx, seriesId, postIds); err != nil {
+			return err
+		}
+
+		// updated_at series ikut berubah supaya daftar series bisa diurutkan berdasarkan aktivitas terakhir
+		return tx.Model(&models.Series{}).Where("id = ?", seriesId).Update("updated_at", gorm.Expr("NOW()")).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+// Delete post di dalam series tidak ikut terhapus
+func (r *SeriesRepositoryImpl) Delete(id int64) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Series{}, id).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func insertSeriesPosts(tx *gorm.DB, seriesId int64, postIds []int64) error {
+	if len(postIds) == 0 {
+		return nil
+	}
+
+	seriesPosts := make([]models.SeriesPost, 0, len(postIds))
+	for i, postId := range postIds {
+		seriesPosts = append(seriesPosts, models.SeriesPost{
+			SeriesID: seriesId,
+			PostID:   postId,
+			Position: i + 1,
+		})
+	}
+
+	return tx.Create(&seriesPosts).Error
+}
+
+// seriesSummarySelect PostCount hanya menghitung bagian yang sudah terbit
+func seriesSummarySelect(db *gorm.DB) *gorm.DB {
+	return db.
+		Joins("LEFT JOIN users AS author ON author.id = series.author_id").
+		Select([]string{
+			"series.id",
+			"series.title",
+			"series.slug",
+			"series.description",
+			"series.author_id",
+			"author.name AS author_name",
+			fmt.Sprintf(
+				"(SELECT COUNT(*) FROM series_posts sp JOIN posts p ON p.id = sp.post_id WHERE sp.series_id = series.id AND p.status = %d) AS post_count",
+				enum.PostStatusPublished,
+			),
+			"series.created_at",
+			"series.updated_at",
+		})
+}
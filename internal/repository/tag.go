@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	FindAll(filter dto.TagFilterRequest) (*dto.PaginationResult, error)
+	FindBySlug(slug string) (*models.Tag, error)
+	FindByPostId(postId int64) ([]dto.TagResponse, error)
+	FindOrCreateByNames(names []string) ([]models.Tag, error)
+	ReplacePostTags(postId int64, tagIds []int64) error
+}
+
+type TagRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &TagRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *TagRepositoryImpl) FindAll(filter dto.TagFilterRequest) (*dto.PaginationResult, error) {
+	tags := make([]dto.TagResponse, 0)
+	var total int64
+
+	query := r.DB.Model(&models.Tag{})
+
+	if filter.Name != "" {
+		query = query.Where("tags.name LIKE ?", "%"+filter.Name+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	query = query.Select(
+		"tags.id",
+		"tags.name",
+		"tags.slug",
+		"(SELECT COUNT(*) FROM post_tags pt WHERE pt.tag_id = tags.id) AS post_count",
+	)
+
+	query = applyPagination(query, filter.PaginationParams)
+
+	if err := query.Scan(&tags).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return dto.NewPaginationResult(tags, total, filter.Page, filter.PageSize, "tags"), nil
+}
+
+func (r *TagRepositoryImpl) FindBySlug(slug string) (*models.Tag, error) {
+	var tag models.Tag
+
+	if err := r.DB.Where("slug = ?", slug).First(&tag).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("tag not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepositoryImpl) FindByPostId(postId int64) ([]dto.TagResponse, error) {
+	tags := make([]dto.TagResponse, 0)
+
+	err := r.DB.
+		Table("tags").
+		Select("tags.id, tags.name, tags.slug").
+		Joins("JOIN post_tags pt ON pt.tag_id = tags.id").
+		Where("pt.post_id = ?", postId).
+		Order("tags.name").
+		Scan(&tags).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return tags, nil
+}
+
+// FindOrCreateByNames mengembalikan tag sesuai nama, tag yang belum ada akan dibuat
+func (r *TagRepositoryImpl) FindOrCreateByNames(names []string) ([]models.Tag, error) {
+	bySlug := make(map[string]models.Tag)
+	slugs := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := utils.Slugify(name)
+		if slug == "" {
+			continue
+		}
+		if _, exists := bySlug[slug]; exists {
+			continue
+		}
+		bySlug[slug] = models.Tag{Name: name, Slug: slug}
+		slugs = append(slugs, slug)
+	}
+
+	if len(slugs) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	newTags := make([]models.Tag, 0, len(slugs))
+	for _, slug := range slugs {
+		newTags = append(newTags, bySlug[slug])
+	}
+
+	// tag yang sudah ada di-skip, jadi aman kalau dua request membuat tag yang sama
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	var tags []models.Tag
+	if err := r.DB.Where("slug IN ?", slugs).Find(&tags).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepositoryImpl) ReplacePostTags(postId int64, tagIds []int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return replacePostTags(tx, postId, tagIds)
+	})
+}
+
+func replacePostTags(tx *gorm.DB, postId int64, tagIds []int64) error {
+	if err := tx.Where("post_id = ?", postId).Delete(&models.PostTag{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	postTags := make([]models.PostTag, 0, len(tagIds))
+	for _, tagId := range tagIds {
+		postTags = append(postTags, models.PostTag{PostID: postId, TagID: tagId})
+	}
+
+	if err := tx.Create(&postTags).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
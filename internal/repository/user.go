@@ -2,8 +2,10 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"gorm.io/gorm"
@@ -18,14 +20,18 @@ type UserRepository interface {
 	FindByEmailOrUsername(email string, username string) (*models.User, error)
 	FindAllUser() ([]models.User, error)
 	FindAllUserWithPagination(filter dto.UserFilterRequest) (*dto.PaginationResult, error)
-	DeactiveUsers(ids []int) error
+	DeactiveUsers(ids []int, reason string) error
 	Create(user *models.User) error
 	Update(user *models.User) error
+	MarkEmailVerified(userId int64) error
+	UpdatePassword(userId int64, oldPasswordHash string, newPasswordHash string) (bool, error)
+	UpdateProfile(user *models.User, columns ...string) error
+	ChangeUsername(userId int64, oldUsername string, newUsername string) (bool, error)
 
 	CreateFollower(follower *models.Follower) error
 	DeleteFollower(followingId int, userId int) error
-	GetListFollowing(userId int) ([]dto.UserFollowingDTO, error)
-	GetListFollower(userId int) ([]dto.UserFollowerDTO, error)
+	GetListFollowing(userId int, viewerId int) ([]dto.UserFollowingDTO, error)
+	GetListFollower(userId int, viewerId int) ([]dto.UserFollowerDTO, error)
 	CountFollowing(userId int) (int64, error)
 	CheckIsFollowing(followerId int, followingId int) (bool, error)
 	CountFollower(userId int) (int64, error)
@@ -57,6 +63,9 @@ func (r *UserRepositoryImpl) FindByIdentifier(identifier string) (*models.User,
 	resTx := r.DB.Where("email = ?", identifier).Or("username = ?", identifier).First(&user)
 
 	if resTx.Error != nil {
+		if resTx.Error == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("user not found")
+		}
 		return nil, exception.NewGormDBErr(resTx.Error)
 	}
 
@@ -88,11 +97,92 @@ func (r *UserRepositoryImpl) Update(user *models.User) error {
 	return r.DB.Save(user).Error
 }
 
+// MarkEmailVerified mengaktifkan user yang masih inactive, status lain (banned, dll) tidak diubah
+func (r *UserRepositoryImpl) MarkEmailVerified(userId int64) error {
+	resTx := r.DB.Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", userId).
+		Updates(map[string]interface{}{
+			"email_verified_at": time.Now(),
+			"status":            gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", enum.UserStatusInactive, enum.UserStatusActive),
+		})
+
+	if resTx.Error != nil {
+		return exception.NewGormDBErr(resTx.Error)
+	}
+
+	return nil
+}
+
+// UpdatePassword hanya berhasil kalau password belum diganti request lain sejak dibaca
+func (r *UserRepositoryImpl) UpdatePassword(userId int64, oldPasswordHash string, newPasswordHash string) (bool, error) {
+	resTx := r.DB.Model(&models.User{}).
+		Where("id = ? AND COALESCE(password, '') = ?", userId, oldPasswordHash).
+		Update("password", newPasswordHash)
+
+	if resTx.Error != nil {
+		return false, exception.NewGormDBErr(resTx.Error)
+	}
+
+	return resTx.RowsAffected == 1, nil
+}
+
+// UpdateProfile hanya menyimpan kolom yang disebut, lewat struct supaya serializer json ikut dipakai
+func (r *UserRepositoryImpl) UpdateProfile(user *models.User, columns ...string) error {
+	resTx := r.DB.Model(user).
+		Select(columns).
+		Updates(user)
+
+	if resTx.Error != nil {
+		return exception.NewGormDBErr(resTx.Error)
+	}
+
+	return nil
+}
+
+// ChangeUsername hanya berhasil kalau username masih sama dengan oldUsername, riwayat disimpan di transaksi yang sama
+func (r *UserRepositoryImpl) ChangeUsername(userId int64, oldUsername string, newUsername string) (bool, error) {
+	changed := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		resTx := tx.Model(&models.User{}).
+			Where("id = ? AND username = ?", userId, oldUsername).
+			Updates(map[string]interface{}{
+				"username":            newUsername,
+				"username_changed_at": time.Now(),
+			})
+		if resTx.Error != nil {
+			return resTx.Error
+		}
+		if resTx.RowsAffected != 1 {
+			return nil
+		}
+
+		if err := tx.Create(&models.UsernameHistory{
+			UserID:      userId,
+			OldUsername: oldUsername,
+			NewUsername: newUsername,
+		}).Error; err != nil {
+			return err
+		}
+
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return changed, nil
+}
+
 func (r *UserRepositoryImpl) FindById(id int) (*models.User, error) {
 	var user models.User
 	resTx := r.DB.Where("id = ?", id).Take(&user)
 
 	if resTx.Error != nil {
+		if resTx.Error == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("user not found")
+		}
 		return nil, exception.NewGormDBErr(resTx.Error)
 	}
 
@@ -157,13 +247,25 @@ func (r *UserRepositoryImpl) FindAllUserWithPagination(filter dto.UserFilterRequ
 	return dto.NewPaginationResult(users, total, filter.Page, filter.PageSize, "users"), nil
 }
 
-func (r *UserRepositoryImpl) DeactiveUsers(ids []int) error {
-	resTx := r.DB.Model(&models.User{}).
-		Where("id in ?", ids).
-		Update("status", 0)
-
-	if resTx.Error != nil {
-		return exception.NewGormDBErr(resTx.Error)
+// DeactiveUsers mem-ban user dan mencabut semua sesi login-nya, akun yang sudah dihapus tidak disentuh.
+// Status banned (bukan inactive) supaya tidak bisa aktif lagi lewat verifikasi email.
+func (r *UserRepositoryImpl) DeactiveUsers(ids []int, reason string) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Where("id IN ? AND status <> ?", ids, enum.UserStatusDeleted).
+			Update("status", enum.UserStatusBanned).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.UserSession{}).
+			Where("user_id IN ? AND revoked_at IS NULL", ids).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"revoked_reason": reason,
+			}).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
 	}
 
 	return nil
@@ -193,7 +295,7 @@ func (r *UserRepositoryImpl) GetDetailUser(userId int) (*dto.UserResponse, error
 	if err := r.
 		DB.
 		Table("users").
-		Select("id", "name", "username", "email", "bio", "role").
+		Select("id", "name", "username", "email", "bio", "profile_image_uri", "role").
 		Where("id = ?", userId).
 		Scan(&userDetail).Error; err != nil {
 		return nil, exception.NewGormDBErr(err)
@@ -202,14 +304,16 @@ func (r *UserRepositoryImpl) GetDetailUser(userId int) (*dto.UserResponse, error
 	return &userDetail, nil
 }
 
-func (r *UserRepositoryImpl) GetListFollower(userId int) ([]dto.UserFollowerDTO, error) {
+func (r *UserRepositoryImpl) GetListFollower(userId int, viewerId int) ([]dto.UserFollowerDTO, error) {
 	var followers []dto.UserFollowerDTO
 
-	err := r.DB.
+	query := r.DB.
 		Table("followers").
 		Select("users.id, users.name, users.username, users.email, users.profile_image_uri, users.bio, followers.created_at as followed_at").
 		Joins("JOIN users ON users.id = followers.follower_id").
-		Where("followers.following_id = ?", userId).
+		Where("followers.following_id = ?", userId)
+
+	err := excludeHiddenUsers(query, "users.id", viewerId).
 		Scan(&followers).Error
 
 	if err != nil {
@@ -219,14 +323,16 @@ func (r *UserRepositoryImpl) GetListFollower(userId int) ([]dto.UserFollowerDTO,
 	return followers, nil
 }
 
-func (r *UserRepositoryImpl) GetListFollowing(userId int) ([]dto.UserFollowingDTO, error) {
+func (r *UserRepositoryImpl) GetListFollowing(userId int, viewerId int) ([]dto.UserFollowingDTO, error) {
 	var following []dto.UserFollowingDTO
 
-	err := r.DB.
+	query := r.DB.
 		Table("followers").
 		Select("users.id, users.name, users.username, users.email, users.profile_image_uri, users.bio, followers.created_at as followed_at").
 		Joins("JOIN users ON users.id = followers.following_id").
-		Where("followers.follower_id = ?", userId).
+		Where("followers.follower_id = ?", userId)
+
+	err := excludeHiddenUsers(query, "users.id", viewerId).
 		Scan(&following).Error
 
 	if err != nil {
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserBlockRepository interface {
+	Create(block models.UserBlock) error
+	Delete(userId int64, targetId int64, blockType enum.UserBlockType) error
+	Exists(userId int64, targetId int64, blockType enum.UserBlockType) (bool, error)
+	IsBlockedEither(userId int64, otherId int64) (bool, error)
+	FindAllByUserId(userId int64, blockType enum.UserBlockType) ([]dto.UserBlockResponse, error)
+}
+
+type UserBlockRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserBlockRepository(db *gorm.DB) UserBlockRepository {
+	return &UserBlockRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create idempotent, block juga memutus follow dua arah dalam transaksi yang sama
+func (r *UserBlockRepositoryImpl) Create(block models.UserBlock) error {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block).Error; err != nil {
+			return err
+		}
+
+		if enum.UserBlockType(block.Type) != enum.UserBlockTypeBlock {
+			return nil
+		}
+
+		return tx.
+			Where("(follower_id = ? AND following_id = ?) OR (follower_id = ? AND following_id = ?)", block.UserID, block.TargetID, block.TargetID, block.UserID).
+			Delete(&models.Follower{}).Error
+	})
+	if err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserBlockRepositoryImpl) Delete(userId int64, targetId int64, blockType enum.UserBlockType) error {
+	if err := r.DB.
+		Where("user_id = ? AND target_id = ? AND type = ?", userId, targetId, blockType).
+		Delete(&models.UserBlock{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserBlockRepositoryImpl) Exists(userId int64, targetId int64, blockType enum.UserBlockType) (bool, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.UserBlock{}).
+		Where("user_id = ? AND target_id = ? AND type = ?", userId, targetId, blockType).
+		Count(&total).Error; err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return total > 0, nil
+}
+
+// IsBlockedEither true kalau salah satu user memblokir yang lain
+func (r *UserBlockRepositoryImpl) IsBlockedEither(userId int64, otherId int64) (bool, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.UserBlock{}).
+		Where("type = ?", enum.UserBlockTypeBlock).
+		Where("(user_id = ? AND target_id = ?) OR (user_id = ? AND target_id = ?)", userId, otherId, otherId, userId).
+		Count(&total).Error; err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return total > 0, nil
+}
+
+func (r *UserBlockRepositoryImpl) FindAllByUserId(userId int64, blockType enum.UserBlockType) ([]dto.UserBlockResponse, error) {
+	blocks := make([]dto.UserBlockResponse, 0)
+
+	if err := r.DB.
+		Table("user_blocks").
+		Select("users.id, users.name, users.username, users.profile_image_uri, user_blocks.created_at").
+		Joins("JOIN users ON users.id = user_blocks.target_id").
+		Where("user_blocks.user_id = ? AND user_blocks.type = ?", userId, blockType).
+		Order("user_blocks.id desc").
+		Scan(&blocks).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository interface {
+	FindByProviderSubject(provider string, subject string) (*models.UserIdentity, error)
+	FindAllByUserId(userId int64) ([]models.UserIdentity, error)
+	Create(identity *models.UserIdentity) error
+	CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error
+	UpdateEmail(id int64, email *string) error
+	DeleteByUser(id int64, userId int64) (bool, error)
+	CountByUserId(userId int64) (int64, error)
+}
+
+type UserIdentityRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &UserIdentityRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *UserIdentityRepositoryImpl) FindByProviderSubject(provider string, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+
+	if err := r.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("identity not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &identity, nil
+}
+
+func (r *UserIdentityRepositoryImpl) FindAllByUserId(userId int64) ([]models.UserIdentity, error) {
+	identities := make([]models.UserIdentity, 0)
+
+	if err := r.DB.Where("user_id = ?", userId).Order("created_at asc").Find(&identities).Error; err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return identities, nil
+}
+
+func (r *UserIdentityRepositoryImpl) Create(identity *models.UserIdentity) error {
+	if err := r.DB.Create(identity).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepositoryImpl) CreateUserWithIdentity(user *models.User, identity *models.UserIdentity) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		identity.UserID = user.ID
+		if err := tx.Create(identity).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		return nil
+	})
+}
+
+func (r *UserIdentityRepositoryImpl) UpdateEmail(id int64, email *string) error {
+	if err := r.DB.Model(&models.UserIdentity{}).Where("id = ?", id).Update("email", email).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepositoryImpl) DeleteByUser(id int64, userId int64) (bool, error) {
+	res := r.DB.Where("id = ? AND user_id = ?", id, userId).Delete(&models.UserIdentity{})
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *UserIdentityRepositoryImpl) CountByUserId(userId int64) (int64, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.UserIdentity{}).Where("user_id = ?", userId).Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
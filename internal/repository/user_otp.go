@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserOtpRepository interface {
+	Create(otp *models.UserOtp) error
+	FindLatestActive(userId int64, purpose string) (*models.UserOtp, error)
+	FindLatest(userId int64, purpose string) (*models.UserOtp, error)
+	IncrementAttempts(id int64, maxAttempts int) (bool, error)
+	Consume(id int64) (bool, error)
+}
+
+type UserOtpRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserOtpRepository(db *gorm.DB) UserOtpRepository {
+	return &UserOtpRepositoryImpl{
+		DB: db,
+	}
+}
+
+// Create menonaktifkan kode lama dengan purpose yang sama supaya hanya kode terbaru yang berlaku
+func (r *UserOtpRepositoryImpl) Create(otp *models.UserOtp) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserOtp{}).
+			Where("user_id = ? AND purpose = ? AND consumed_at IS NULL", otp.UserID, otp.Purpose).
+			Update("consumed_at", time.Now()).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		if err := tx.Create(otp).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		return nil
+	})
+}
+
+func (r *UserOtpRepositoryImpl) FindLatestActive(userId int64, purpose string) (*models.UserOtp, error) {
+	var otp models.UserOtp
+
+	err := r.DB.
+		Where("user_id = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?", userId, purpose, time.Now()).
+		Order("id desc").
+		First(&otp).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("otp not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &otp, nil
+}
+
+// FindLatest dipakai untuk cooldown kirim ulang, termasuk kode yang sudah dipakai/kadaluarsa
+func (r *UserOtpRepositoryImpl) FindLatest(userId int64, purpose string) (*models.UserOtp, error) {
+	var otp models.UserOtp
+
+	err := r.DB.
+		Where("user_id = ? AND purpose = ?", userId, purpose).
+		Order("id desc").
+		First(&otp).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("otp not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &otp, nil
+}
+
+// IncrementAttempts memesan satu percobaan sebelum kode dicocokkan, false kalau jatah percobaan sudah habis
+func (r *UserOtpRepositoryImpl) IncrementAttempts(id int64, maxAttempts int) (bool, error) {
+	res := r.DB.Model(&models.UserOtp{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+// Consume menandai kode sudah dipakai, false kalau sudah dipakai request lain
+func (r *UserOtpRepositoryImpl) Consume(id int64) (bool, error) {
+	res := r.DB.Model(&models.UserOtp{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", time.Now())
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
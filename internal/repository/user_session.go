@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository interface {
+	Create(session *models.UserSession) error
+	FindById(id int64) (*models.UserSession, error)
+	FindActiveWithUser(id int64) (*models.UserSession, error)
+	FindActiveByUserId(userId int64) ([]dto.UserSessionResponse, error)
+	Rotate(id int64, currentHash string, newHash string, expiresAt time.Time, ipAddress string) (bool, error)
+	Revoke(id int64, reason string) error
+	RevokeByUser(userId int64, sessionId int64, reason string) (bool, error)
+	RevokeAllByUserId(userId int64, reason string) error
+	RevokeOthersByUserId(userId int64, exceptSessionId int64, reason string) error
+}
+
+type UserSessionRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepository {
+	return &UserSessionRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *UserSessionRepositoryImpl) Create(session *models.UserSession) error {
+	if err := r.DB.Create(session).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserSessionRepositoryImpl) FindById(id int64) (*models.UserSession, error) {
+	var session models.UserSession
+
+	if err := r.DB.Where("id = ?", id).First(&session).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("session not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &session, nil
+}
+
+// FindActiveWithUser dipakai middleware, session yang dicabut/kadaluarsa dianggap tidak ada
+func (r *UserSessionRepositoryImpl) FindActiveWithUser(id int64) (*models.UserSession, error) {
+	var session models.UserSession
+
+	err := r.DB.
+		Preload("User").
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		First(&session).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("session not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &session, nil
+}
+
+func (r *UserSessionRepositoryImpl) FindActiveByUserId(userId int64) ([]dto.UserSessionResponse, error) {
+	sessions := make([]dto.UserSessionResponse, 0)
+
+	err := r.DB.
+		Model(&models.UserSession{}).
+		Select("id", "user_agent", "ip_address", "created_at", "last_used_at", "expires_at").
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userId, time.Now()).
+		Order("last_used_at desc").
+		Scan(&sessions).Error
+
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return sessions, nil
+}
+
+// Rotate hanya berhasil kalau hash yang dikirim masih hash terbaru, jadi dua refresh bersamaan tidak bisa sama-sama lolos
+func (r *UserSessionRepositoryImpl) Rotate(id int64, currentHash string, newHash string, expiresAt time.Time, ipAddress string) (bool, error) {
+	res := r.DB.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": currentHash,
+			"expires_at":          expiresAt,
+			"last_used_at":        time.Now(),
+			"ip_address":          ipAddress,
+		})
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *UserSessionRepositoryImpl) Revoke(id int64, reason string) error {
+	if err := r.DB.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserSessionRepositoryImpl) RevokeByUser(userId int64, sessionId int64, reason string) (bool, error) {
+	res := r.DB.Model(&models.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionId, userId).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		})
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *UserSessionRepositoryImpl) RevokeAllByUserId(userId int64, reason string) error {
+	if err := r.DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userId).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
+
+func (r *UserSessionRepositoryImpl) RevokeOthersByUserId(userId int64, exceptSessionId int64, reason string) error {
+	if err := r.DB.Model(&models.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userId, exceptSessionId).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserTwoFactorRepository interface {
+	FindByUserId(userId int64) (*models.UserTwoFactor, error)
+	UpsertPending(userId int64, secretEncrypted string) error
+	Enable(userId int64, step int64, recoveryCodes []models.UserRecoveryCode) (bool, error)
+	MarkStepUsed(userId int64, step int64) (bool, error)
+	Delete(userId int64) error
+	ReplaceRecoveryCodes(userId int64, recoveryCodes []models.UserRecoveryCode) error
+	UseRecoveryCode(userId int64, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userId int64) (int64, error)
+	FindNotEncryptedWith(keyId string, afterId int64, limit int) ([]models.UserTwoFactor, error)
+	ReplaceSecret(id int64, oldSecret string, newSecret string) (bool, error)
+}
+
+type UserTwoFactorRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUserTwoFactorRepository(db *gorm.DB) UserTwoFactorRepository {
+	return &UserTwoFactorRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *UserTwoFactorRepositoryImpl) FindByUserId(userId int64) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+
+	if err := r.DB.Where("user_id = ?", userId).First(&twoFactor).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("two factor is not configured")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &twoFactor, nil
+}
+
+// UpsertPending menyimpan secret baru yang belum aktif, hanya boleh menimpa enrolment yang belum dikonfirmasi
+func (r *UserTwoFactorRepositoryImpl) UpsertPending(userId int64, secretEncrypted string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND enabled_at IS NULL", userId).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+
+		twoFactor := models.UserTwoFactor{
+			UserID:          userId,
+			SecretEncrypted: secretEncrypted,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&twoFactor).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		if twoFactor.ID == 0 {
+			return exception.NewBusnissLogicErr("two factor authentication is already enabled")
+		}
+
+		return nil
+	})
+}
+
+// Enable mengaktifkan enrolment pending sekaligus menyimpan recovery code dalam satu transaksi
+func (r *UserTwoFactorRepositoryImpl) Enable(userId int64, step int64, recoveryCodes []models.UserRecoveryCode) (bool, error) {
+	enabled := false
+
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.UserTwoFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userId).
+			Updates(map[string]interface{}{
+				"enabled_at":     time.Now(),
+				"last_used_step": step,
+			})
+		if res.Error != nil {
+			return exception.NewGormDBErr(res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := replaceRecoveryCodes(tx, userId, recoveryCodes); err != nil {
+			return err
+		}
+
+		enabled = true
+		return nil
+	})
+
+	return enabled, err
+}
+
+// MarkStepUsed gagal kalau step yang sama (atau lebih baru) sudah pernah dipakai
+func (r *UserTwoFactorRepositoryImpl) MarkStepUsed(userId int64, step int64) (bool, error) {
+	res := r.DB.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL AND last_used_step < ?", userId, step).
+		Update("last_used_step", step)
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *UserTwoFactorRepositoryImpl) Delete(userId int64) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return exception.NewGormDBErr(err)
+		}
+		return nil
+	})
+}
+
+func (r *UserTwoFactorRepositoryImpl) ReplaceRecoveryCodes(userId int64, recoveryCodes []models.UserRecoveryCode) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userId, recoveryCodes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userId int64, recoveryCodes []models.UserRecoveryCode) error {
+	if err := tx.Where("user_id = ?", userId).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+	if err := tx.Create(&recoveryCodes).Error; err != nil {
+		return exception.NewGormDBErr(err)
+	}
+	return nil
+}
+
+func (r *UserTwoFactorRepositoryImpl) UseRecoveryCode(userId int64, codeHash string) (bool, error) {
+	res := r.DB.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Limit(1).
+		Update("used_at", time.Now())
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (r *UserTwoFactorRepositoryImpl) CountUnusedRecoveryCodes(userId int64) (int64, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userId).
+		Count(&total).Error; err != nil {
+		return 0, exception.NewGormDBErr(err)
+	}
+
+	return total, nil
+}
+
+// FindNotEncryptedWith mencari secret yang belum memakai key id aktif, dipakai setelah rotasi key
+func (r *UserTwoFactorRepositoryImpl) FindNotEncryptedWith(keyId string, afterId int64, limit int) ([]models.UserTwoFactor, error) {
+	var twoFactors []models.UserTwoFactor
+
+	err := r.DB.
+		Where("id > ? AND LEFT(secret_encrypted, ?) <> ?", afterId, len(keyId)+1, keyId+":").
+		Order("id").
+		Limit(limit).
+		Find(&twoFactors).Error
+	if err != nil {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return twoFactors, nil
+}
+
+// ReplaceSecret hanya menimpa kalau secret belum berubah (misal user setup ulang di saat yang sama)
+func (r *UserTwoFactorRepositoryImpl) ReplaceSecret(id int64, oldSecret string, newSecret string) (bool, error) {
+	res := r.DB.Model(&models.UserTwoFactor{}).
+		Where("id = ? AND secret_encrypted = ?", id, oldSecret).
+		Update("secret_encrypted", newSecret)
+
+	if res.Error != nil {
+		return false, exception.NewGormDBErr(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type UsernameHistoryRepository interface {
+	FindLatestByOldUsername(username string) (*models.UsernameHistory, error)
+	IsReserved(username string, exceptUserId int64, since time.Time) (bool, error)
+}
+
+type UsernameHistoryRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func NewUsernameHistoryRepository(db *gorm.DB) UsernameHistoryRepository {
+	return &UsernameHistoryRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *UsernameHistoryRepositoryImpl) FindLatestByOldUsername(username string) (*models.UsernameHistory, error) {
+	var history models.UsernameHistory
+
+	if err := r.DB.
+		Where("old_username = ?", username).
+		Order("created_at desc, id desc").
+		First(&history).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, exception.NewNotFoundErr("username history not found")
+		}
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	return &history, nil
+}
+
+// IsReserved true kalau username baru saja dilepas user lain, supaya link lama tidak langsung diambil orang
+func (r *UsernameHistoryRepositoryImpl) IsReserved(username string, exceptUserId int64, since time.Time) (bool, error) {
+	var total int64
+
+	if err := r.DB.Model(&models.UsernameHistory{}).
+		Where("old_username = ? AND user_id <> ? AND created_at > ?", username, exceptUserId, since).
+		Count(&total).Error; err != nil {
+		return false, exception.NewGormDBErr(err)
+	}
+
+	return total > 0, nil
+}
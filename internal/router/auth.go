@@ -1,7 +1,10 @@
 package router
 
 import (
+	"github.com/MrBista/blog-api/internal/config"
 	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/oauth"
 	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/gofiber/fiber/v2"
@@ -10,16 +13,65 @@ import (
 
 func SetAuthRoute(router fiber.Router, db *gorm.DB) {
 
-	authRepository := repository.NewUserRepository(db)
-	authService := services.NewAutService(authRepository)
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := newAuthHandler(db)
 
 	authRoute := router.Group("/auth")
 
 	authRoute.Post("/login", authHandler.LoginUser)
 	authRoute.Post("/register", authHandler.RegisterUser)
+	authRoute.Post("/verify", authHandler.ConfirmOtp)
+	authRoute.Post("/resend-otp", authHandler.ResendOtp)
+	authRoute.Post("/forgot-password", authHandler.ForgotPassword)
+	authRoute.Post("/reset-password", authHandler.ResetPassword)
+	authRoute.Post("/refresh", authHandler.RefreshToken)
+	authRoute.Post("/logout", middleware.AuthMiddlware(), authHandler.Logout)
+
+	// langkah kedua login untuk user dengan 2FA
+	authRoute.Post("/2fa/verify", authHandler.VerifyTwoFactorLogin)
+	authRoute.Post("/2fa/setup", authHandler.SetupPendingTwoFactor)
+	authRoute.Post("/2fa/enable", authHandler.EnablePendingTwoFactor)
+
+	oauthHandler := newOAuthHandler(db)
+
+	authRoute.Get("/oauth/providers", oauthHandler.ListProviders)
+	authRoute.Get("/oauth/:provider/url", oauthHandler.GetAuthURL)
+	authRoute.Post("/oauth/:provider/callback", oauthHandler.Callback)
+
+	// route lama login Google, sama dengan /oauth/google/*
+	authRoute.Get("/google/url", oauthHandler.GetAuthURL)
+	authRoute.Post("/google/callback", oauthHandler.Callback)
+
+}
+
+func newAuthHandler(db *gorm.DB) handler.AuthHandler {
+	authService, sessionService := newAuthService(db)
+
+	return handler.NewAuthHandler(authService, sessionService)
+}
+
+func newAuthService(db *gorm.DB) (services.AuthService, services.SessionService) {
+	authRepository := repository.NewUserRepository(db)
+	sessionService := services.NewSessionService(repository.NewUserSessionRepository(db), authRepository)
+	otpService := services.NewOtpService(repository.NewUserOtpRepository(db), &config.AppConfig.Otp)
+	mailer := services.NewMailer(&config.AppConfig.Mail)
+	authService := services.NewAutService(authRepository, sessionService, otpService, newTwoFactorService(db), newLoginGuardService(db), mailer)
+
+	return authService, sessionService
+}
+
+func newOAuthHandler(db *gorm.DB) handler.OAuthHandler {
+	authService, _ := newAuthService(db)
+	oauthService := services.NewOAuthService(oauth.GetRegistry(), repository.NewUserRepository(db), repository.NewUserIdentityRepository(db), authService, &config.AppConfig.OAuth)
+
+	return handler.NewOAuthHandler(oauthService, config.AppConfig.OAuth.GetStateTTL())
+}
+
+func newTwoFactorService(db *gorm.DB) services.TwoFactorService {
+	return services.NewTwoFactorService(repository.NewUserTwoFactorRepository(db), repository.NewUserRepository(db), newLoginGuardService(db), &config.AppConfig.TwoFactor)
+}
 
-	authRoute.Get("/google/url", authHandler.GetGoogleAuthURL)
-	authRoute.Post("/google/callback", authHandler.GoogleCallback)
+func newLoginGuardService(db *gorm.DB) services.LoginGuardService {
+	store := services.NewLoginAttemptStore(&config.AppConfig.LoginGuard, repository.NewLoginAttemptRepository(db))
 
+	return services.NewLoginGuardService(store, repository.NewSecurityLogRepository(db), &config.AppConfig.LoginGuard)
 }
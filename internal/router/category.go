@@ -5,22 +5,36 @@ import (
 	"github.com/MrBista/blog-api/internal/handler"
 	"github.com/MrBista/blog-api/internal/middleware"
 	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/search"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
 func SetupCategoryRouter(route fiber.Router, db *gorm.DB) {
-	categoryRepository := repository.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepository, db)
-	categoryHandler := handler.NewCategoryHandler(categoryService)
+	categoryHandler := handler.NewCategoryHandler(newCategoryService(db))
 
 	categoryRouter := route.Group("/categories")
 
+	// harus di atas /:id agar tidak bentrok
+	categoryRouter.Get("/tree", categoryHandler.FindCategoryTree)
 	categoryRouter.Get("/:id", middleware.AuthMiddlware(), categoryHandler.FindCategoryById)
-	categoryRouter.Put("/:id", middleware.AuthMiddlware(), middleware.RoleMiddleare(enum.RoleAdmin), categoryHandler.UpdateCategory)
-	categoryRouter.Delete("/:id", middleware.AuthMiddlware(), middleware.RoleMiddleare(enum.RoleAdmin), categoryHandler.DeleteCategory)
+	categoryRouter.Post("/:id/follow", middleware.AuthMiddlware(), categoryHandler.FollowCategory)
+	categoryRouter.Delete("/:id/follow", middleware.AuthMiddlware(), categoryHandler.UnfollowCategory)
+	categoryRouter.Put("/:id/move", middleware.AuthMiddlware(enum.ScopeCategoriesWrite), middleware.PermissionMiddleware(enum.PermissionCategoryManage), categoryHandler.MoveCategory)
+	categoryRouter.Put("/:id", middleware.AuthMiddlware(enum.ScopeCategoriesWrite), middleware.PermissionMiddleware(enum.PermissionCategoryManage), categoryHandler.UpdateCategory)
+	categoryRouter.Delete("/:id", middleware.AuthMiddlware(enum.ScopeCategoriesWrite), middleware.PermissionMiddleware(enum.PermissionCategoryManage), categoryHandler.DeleteCategory)
 	categoryRouter.Get("/", categoryHandler.FindAllCategory)
-	categoryRouter.Post("/", middleware.AuthMiddlware(), middleware.RoleMiddleare(enum.RoleAdmin), categoryHandler.CreateCategory)
+	categoryRouter.Post("/", middleware.AuthMiddlware(enum.ScopeCategoriesWrite), middleware.PermissionMiddleware(enum.PermissionCategoryManage), categoryHandler.CreateCategory)
 
 }
+
+func newCategoryService(db *gorm.DB) services.CategoryService {
+	return services.NewCategoryService(
+		repository.NewCategoryRepository(db),
+		repository.NewCategoryFollowerRepository(db),
+		services.NewSearchService(search.GetSearchIndex(), repository.NewPostRepository(db)),
+		newFeedService(db),
+		db,
+	)
+}
@@ -1,22 +1,53 @@
 package router
 
 import (
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/handler"
 	"github.com/MrBista/blog-api/internal/middleware"
 	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/spam"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
-func SetCommentRoute(router fiber.Router, db *gorm.DB) {
-	commentRoute := router.Group("/:postId/comments", middleware.AuthMiddlware())
-
+func newCommentHandler(db *gorm.DB) handler.CommentHandler {
 	commentRepository := repository.NewCommentRepository(db)
-	commentService := services.NewCommentService(commentRepository, db)
-	commentHandler := handler.NewCommentHandler(commentService)
+	commentConfig := &config.AppConfig.Comment
+	spamChecker := spam.NewHeuristicChecker(commentRepository, spam.HeuristicConfig{
+		MaxLinks:       commentConfig.MaxLinks,
+		BlockedWords:   commentConfig.BlockedWords,
+		VelocityLimit:  commentConfig.VelocityLimit,
+		VelocityWindow: commentConfig.VelocityWindow,
+	})
+	mailer := services.NewMailer(&config.AppConfig.Mail)
+	commentService := services.NewCommentService(commentRepository, repository.NewUserBlockRepository(db), db, commentConfig, spamChecker, mailer, config.AppConfig.AppMain.GetBaseUrl())
+
+	return handler.NewCommentHandler(commentService)
+}
+
+func SetCommentRoute(router fiber.Router, db *gorm.DB, likeHandler handler.LikeHandler) {
+	commentRoute := router.Group("/:postId/comments")
+
+	commentHandler := newCommentHandler(db)
+
+	// baca komentar terbuka untuk pembaca tanpa akun
+	commentRoute.Get("/", middleware.OptionalAuthMiddleware(), commentHandler.FindAllComment)
+	commentRoute.Get("/tree", middleware.OptionalAuthMiddleware(), commentHandler.FindCommentTree)
+	commentRoute.Get("/:id/replies", middleware.OptionalAuthMiddleware(), commentHandler.FindReplies)
+
+	commentRoute.Post("/", middleware.AuthMiddlware(enum.ScopeCommentsWrite), commentHandler.CreateComment)
+	commentRoute.Post("/guest", commentHandler.CreateGuestComment)
+	commentRoute.Put("/:id", middleware.AuthMiddlware(enum.ScopeCommentsWrite), commentHandler.UpdateComment)
+	commentRoute.Delete("/:id", middleware.AuthMiddlware(enum.ScopeCommentsWrite), commentHandler.DeleteComment)
+	commentRoute.Post("/:id/like", middleware.AuthMiddlware(), likeHandler.LikeComment)
+	commentRoute.Delete("/:id/like", middleware.AuthMiddlware(), likeHandler.UnlikeComment)
+
+}
 
-	commentRoute.Get("/", commentHandler.FindAllComment)
-	commentRoute.Post("/", commentHandler.CreateComment)
+func SetupCommentRoute(router fiber.Router, db *gorm.DB) {
+	commentHandler := newCommentHandler(db)
 
+	router.Get("/comments/confirm", commentHandler.ConfirmGuestComment)
 }
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupFeedRoute(router fiber.Router, db *gorm.DB) {
+	feedHandler := handler.NewFeedHandler(newFeedService(db))
+
+	router.Get("/feed", middleware.AuthMiddlware(), feedHandler.FindMyFeed)
+}
+
+func newFeedService(db *gorm.DB) services.FeedService {
+	return services.NewFeedService(repository.NewFeedRepository(db), &config.AppConfig.Feed)
+}
@@ -0,0 +1,24 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupModerationRoute(router fiber.Router, db *gorm.DB) {
+	commentRepository := repository.NewCommentRepository(db)
+	commentModerationService := services.NewCommentModerationService(commentRepository)
+	commentModerationHandler := handler.NewCommentModerationHandler(commentModerationService)
+
+	moderationRouter := router.Group("/moderation", middleware.AuthMiddlware(enum.ScopeCommentsModerate), middleware.PermissionMiddleware(enum.PermissionCommentModerate))
+
+	moderationRouter.Get("/comments", commentModerationHandler.FindQueue)
+	moderationRouter.Post("/comments/bulk", commentModerationHandler.BulkModerate)
+	moderationRouter.Post("/comments/:id/approve", commentModerationHandler.ApproveComment)
+	moderationRouter.Post("/comments/:id/reject", commentModerationHandler.RejectComment)
+}
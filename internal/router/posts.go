@@ -1,9 +1,11 @@
 package router
 
 import (
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/handler"
 	"github.com/MrBista/blog-api/internal/middleware"
 	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/search"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -13,20 +15,49 @@ func SetupPostRoute(router fiber.Router, db *gorm.DB) {
 	postStroage := services.NewLocalStorage("./public", "/public")
 	postRepository := repository.NewPostRepository(db)
 	categoryRepository := repository.NewCategoryRepository(db)
-	postService := services.NewPostService(postRepository, categoryRepository, postStroage)
+	tagRepository := repository.NewTagRepository(db)
+	postRevisionRepository := repository.NewPostRevisionRepository(db)
+	postStatusEventRepository := repository.NewPostStatusEventRepository(db)
+	searchService := services.NewSearchService(search.GetSearchIndex(), postRepository)
+	postWorkflowService := services.NewPostWorkflowService(postRepository, postStatusEventRepository, searchService)
+	postWorkflowHandler := handler.NewPostWorkflowHandler(postWorkflowService)
+	postService := services.NewPostService(postRepository, categoryRepository, tagRepository, postRevisionRepository, postWorkflowService, searchService, postStroage, repository.NewSeriesRepository(db))
 	handlerPost := handler.NewHandlerPost(postService)
+	postRevisionService := services.NewPostRevisionService(postRepository, postRevisionRepository, searchService)
+	postRevisionHandler := handler.NewPostRevisionHandler(postRevisionService)
+	likeService := services.NewLikeService(repository.NewLikeRepository(db), postRepository, repository.NewCommentRepository(db))
+	likeHandler := handler.NewLikeHandler(likeService)
 
 	postRouter := router.Group("/posts")
 
-	postRouter.Post("/uploads", middleware.AuthMiddlware(), handlerPost.SaveFileTemp)
-	postRouter.Get("/", handlerPost.GetAllPosts)
-	postRouter.Get("/:slug", middleware.AuthMiddlware(), handlerPost.GetPostBySlug)
-	postRouter.Delete("/:slug", middleware.AuthMiddlware(), handlerPost.DeletePost)
-	postRouter.Post("/", middleware.AuthMiddlware(), handlerPost.CreatePost)
-	postRouter.Put("/:slug", middleware.AuthMiddlware(), handlerPost.UpdatePost)
+	postRouter.Post("/uploads", middleware.AuthMiddlware(enum.ScopePostsWrite), handlerPost.SaveFileTemp)
+	postRouter.Get("/", middleware.OptionalAuthMiddleware(), handlerPost.GetAllPosts)
+	postRouter.Get("/:slug", middleware.AuthMiddlware(enum.ScopePostsRead), handlerPost.GetPostBySlug)
+	postRouter.Delete("/:slug", middleware.AuthMiddlware(enum.ScopePostsWrite), handlerPost.DeletePost)
+	postRouter.Post("/", middleware.AuthMiddlware(enum.ScopePostsWrite), handlerPost.CreatePost)
+	postRouter.Put("/:slug", middleware.AuthMiddlware(enum.ScopePostsWrite), handlerPost.UpdatePost)
 
-	SetCommentRoute(postRouter, db)
+	// Editorial workflow
+	postRouter.Post("/:slug/submit", middleware.AuthMiddlware(enum.ScopePostsWrite), postWorkflowHandler.SubmitPost)
+	postRouter.Post("/:slug/approve", middleware.AuthMiddlware(enum.ScopePostsReview), middleware.PermissionMiddleware(enum.PermissionPostPublish), postWorkflowHandler.ApprovePost)
+	postRouter.Post("/:slug/reject", middleware.AuthMiddlware(enum.ScopePostsReview), middleware.PermissionMiddleware(enum.PermissionPostPublish), postWorkflowHandler.RejectPost)
+	postRouter.Post("/:slug/archive", middleware.AuthMiddlware(enum.ScopePostsWrite), postWorkflowHandler.ArchivePost)
+	postRouter.Get("/:slug/status-events", middleware.AuthMiddlware(enum.ScopePostsRead), postWorkflowHandler.FindStatusEvents)
+
+	// Likes
+	postRouter.Post("/:slug/like", middleware.AuthMiddlware(), likeHandler.LikePost)
+	postRouter.Delete("/:slug/like", middleware.AuthMiddlware(), likeHandler.UnlikePost)
+
+	// Revision history
+	postRouter.Get("/:slug/revisions", middleware.AuthMiddlware(enum.ScopePostsRead), postRevisionHandler.FindAllRevision)
+	postRouter.Get("/:slug/revisions/diff", middleware.AuthMiddlware(enum.ScopePostsRead), postRevisionHandler.DiffRevision)
+	postRouter.Get("/:slug/revisions/:revisionId", middleware.AuthMiddlware(enum.ScopePostsRead), postRevisionHandler.FindDetailRevision)
+	postRouter.Post("/:slug/revisions/:revisionId/restore", middleware.AuthMiddlware(enum.ScopePostsWrite), postRevisionHandler.RestoreRevision)
+
+	SetCommentRoute(postRouter, db, likeHandler)
 
 	SetupReadingListRoutes(router, postService)
 
+	SetupReviewRoute(router, postWorkflowHandler)
+
 }
@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupReviewRoute(router fiber.Router, postWorkflowHandler handler.PostWorkflowHandler) {
+	reviewRoute := router.Group("/reviews", middleware.AuthMiddlware(enum.ScopePostsReview), middleware.PermissionMiddleware(enum.PermissionPostPublish))
+
+	reviewRoute.Get("/queue", postWorkflowHandler.FindReviewQueue)
+	reviewRoute.Get("/history", postWorkflowHandler.FindMyReviewHistory)
+}
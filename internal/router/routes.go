@@ -18,6 +18,7 @@ func SetupAllRoutes(app *fiber.App) {
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
 
 	app.Post("/webhook/xendit", subscriptionHandler.WebhookPayment)
+	SetupWellKnownRoute(app, database.DB)
 	subscription := router.Group("/subscriptions", middleware.AuthMiddlware())
 
 	subscription.Post("/", subscriptionHandler.CreateSubscription)
@@ -25,6 +26,13 @@ func SetupAllRoutes(app *fiber.App) {
 	SetupPostRoute(router, database.DB)
 	SetAuthRoute(router, database.DB)
 	SetupCategoryRouter(router, database.DB)
+	SetupTagRouter(router, database.DB)
+	SetupSearchRouter(router, database.DB)
+	SetupModerationRoute(router, database.DB)
+	SetupSecurityRoute(router, database.DB)
+	SetupCommentRoute(router, database.DB)
 	SetUserRoute(router, database.DB)
+	SetupFeedRoute(router, database.DB)
+	SetupSeriesRoute(router, database.DB)
 	// SetCommentRoute(router, database.DB)
 }
@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/search"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupSearchRouter(route fiber.Router, db *gorm.DB) {
+	postRepository := repository.NewPostRepository(db)
+	searchService := services.NewSearchService(search.GetSearchIndex(), postRepository)
+	searchHandler := handler.NewSearchHandler(searchService)
+
+	route.Get("/search", searchHandler.SearchPosts)
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupSecurityRoute(router fiber.Router, db *gorm.DB) {
+	securityHandler := newSecurityHandler(db)
+
+	securityRouter := router.Group("/security", middleware.AuthMiddlware(), middleware.PermissionMiddleware(enum.PermissionSecurityManage))
+
+	securityRouter.Get("/lockouts", securityHandler.FindLockouts)
+	securityRouter.Post("/lockouts/clear", securityHandler.ClearLockout)
+	securityRouter.Get("/logs", securityHandler.FindSecurityLogs)
+	securityRouter.Get("/jwt-keys", securityHandler.FindJwtKeys)
+	securityRouter.Post("/jwt-keys/rotate", securityHandler.RotateJwtKey)
+}
+
+// SetupWellKnownRoute route standar di luar /api, dipakai service lain untuk memverifikasi token
+func SetupWellKnownRoute(app *fiber.App, db *gorm.DB) {
+	securityHandler := newSecurityHandler(db)
+
+	app.Get("/.well-known/jwks.json", securityHandler.GetJWKS)
+}
+
+func newSecurityHandler(db *gorm.DB) handler.SecurityHandler {
+	jwtKeyService := services.NewJwtKeyService(utils.GetJwtService(), repository.NewSecurityLogRepository(db))
+
+	return handler.NewSecurityHandler(newLoginGuardService(db), jwtKeyService)
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/middleware"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupSeriesRoute(router fiber.Router, db *gorm.DB) {
+	seriesService := services.NewSeriesService(repository.NewSeriesRepository(db))
+	seriesHandler := handler.NewSeriesHandler(seriesService)
+
+	seriesRouter := router.Group("/series")
+
+	seriesRouter.Get("/", seriesHandler.FindAllSeries)
+	seriesRouter.Post("/", middleware.AuthMiddlware(enum.ScopePostsWrite), seriesHandler.CreateSeries)
+	seriesRouter.Get("/:slug", middleware.OptionalAuthMiddleware(), seriesHandler.FindSeriesBySlug)
+	seriesRouter.Put("/:slug/posts", middleware.AuthMiddlware(enum.ScopePostsWrite), seriesHandler.SetSeriesPosts)
+	seriesRouter.Put("/:slug", middleware.AuthMiddlware(enum.ScopePostsWrite), seriesHandler.UpdateSeries)
+	seriesRouter.Delete("/:slug", middleware.AuthMiddlware(enum.ScopePostsWrite), seriesHandler.DeleteSeries)
+}
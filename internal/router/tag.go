@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/MrBista/blog-api/internal/handler"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupTagRouter(route fiber.Router, db *gorm.DB) {
+	tagRepository := repository.NewTagRepository(db)
+	postRepository := repository.NewPostRepository(db)
+	tagService := services.NewTagService(tagRepository, postRepository)
+	tagHandler := handler.NewTagHandler(tagService)
+
+	tagRouter := route.Group("/tags")
+
+	tagRouter.Get("/", tagHandler.FindAllTag)
+	tagRouter.Get("/:slug/posts", tagHandler.FindPostsByTag)
+}
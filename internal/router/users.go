@@ -5,6 +5,7 @@ import (
 	"github.com/MrBista/blog-api/internal/handler"
 	"github.com/MrBista/blog-api/internal/middleware"
 	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/search"
 	"github.com/MrBista/blog-api/internal/services"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -14,23 +15,71 @@ func SetUserRoute(router fiber.Router, db *gorm.DB) {
 	userRoute := router.Group("/users")
 
 	userRepository := repository.NewUserRepository(db)
-	userService := services.NewUserService(userRepository, db)
+	userBlockRepository := repository.NewUserBlockRepository(db)
+	userService := services.NewUserService(userRepository, userBlockRepository, newFeedService(db), db)
 	userHandler := handler.NewUserHandler(userService)
+	likeService := services.NewLikeService(repository.NewLikeRepository(db), repository.NewPostRepository(db), repository.NewCommentRepository(db))
+	likeHandler := handler.NewLikeHandler(likeService)
+	authHandler := newAuthHandler(db)
+	twoFactorHandler := handler.NewTwoFactorHandler(newTwoFactorService(db))
+	oauthHandler := newOAuthHandler(db)
+	tokenHandler := handler.NewPersonalAccessTokenHandler(services.NewPersonalAccessTokenService(repository.NewPersonalAccessTokenRepository(db)))
+	profileService := services.NewProfileService(userRepository, repository.NewUsernameHistoryRepository(db), services.NewLocalStorage("./public", "/public"))
+	profileHandler := handler.NewProfileHandler(profileService)
+	accountHandler := handler.NewAccountHandler(newAccountService(db))
+	userBlockHandler := handler.NewUserBlockHandler(services.NewUserBlockService(userBlockRepository, userRepository))
+	categoryHandler := handler.NewCategoryHandler(newCategoryService(db))
 
 	userRoute.Get("/", middleware.AuthMiddlware(), userHandler.GetAllUser)
-	userRoute.Post("/", middleware.AuthMiddlware(), middleware.RoleMiddleare(enum.RoleAdmin), userHandler.CreateUser)
-	userRoute.Put("/deactive", middleware.AuthMiddlware(), middleware.RoleMiddleare(enum.RoleAdmin), userHandler.DeactiveUser)
+	userRoute.Post("/", middleware.AuthMiddlware(), middleware.PermissionMiddleware(enum.PermissionUserManage), userHandler.CreateUser)
+	userRoute.Put("/deactive", middleware.AuthMiddlware(), middleware.PermissionMiddleware(enum.PermissionUserBan), userHandler.DeactiveUser)
 
 	// My followers & following (harus di atas /:id agar tidak bentrok)
+	userRoute.Put("/me", middleware.AuthMiddlware(), profileHandler.UpdateMyProfile)
+	userRoute.Post("/me/avatar", middleware.AuthMiddlware(), profileHandler.UploadMyAvatar)
+	userRoute.Put("/me/username", middleware.AuthMiddlware(), profileHandler.ChangeMyUsername)
+	userRoute.Delete("/me", middleware.AuthMiddlware(), accountHandler.DeleteMyAccount)
+	userRoute.Delete("/me/deletion", middleware.AuthMiddlware(), accountHandler.CancelMyDeletion)
+	userRoute.Get("/me/export", middleware.AuthMiddlware(), accountHandler.RequestMyExport)
+	userRoute.Get("/me/export/:id/download", middleware.AuthMiddlware(), accountHandler.DownloadMyExport)
 	userRoute.Get("/me/followers", middleware.AuthMiddlware(), userHandler.GetMyFollowers)
 	userRoute.Get("/me/following", middleware.AuthMiddlware(), userHandler.GetMyFollowing)
+	userRoute.Get("/me/categories", middleware.AuthMiddlware(), categoryHandler.FindMyCategories)
+	userRoute.Get("/me/blocks", middleware.AuthMiddlware(), userBlockHandler.GetMyBlocks)
+	userRoute.Get("/me/mutes", middleware.AuthMiddlware(), userBlockHandler.GetMyMutes)
+	userRoute.Get("/me/permissions", middleware.AuthMiddlware(), userHandler.GetMyPermissions)
+	userRoute.Get("/me/likes", middleware.AuthMiddlware(), likeHandler.FindMyLikes)
+	userRoute.Get("/me/sessions", middleware.AuthMiddlware(), authHandler.FindMySessions)
+	userRoute.Delete("/me/sessions/:id", middleware.AuthMiddlware(), authHandler.RevokeMySession)
+	userRoute.Put("/me/password", middleware.AuthMiddlware(), authHandler.ChangePassword)
+	// personal access token hanya bisa dikelola dari login biasa
+	userRoute.Get("/me/tokens", middleware.AuthMiddlware(), tokenHandler.FindMyTokens)
+	userRoute.Post("/me/tokens", middleware.AuthMiddlware(), tokenHandler.CreateToken)
+	userRoute.Delete("/me/tokens/:id", middleware.AuthMiddlware(), tokenHandler.RevokeToken)
+	userRoute.Get("/me/identities", middleware.AuthMiddlware(), oauthHandler.FindMyIdentities)
+	userRoute.Get("/me/identities/:provider/url", middleware.AuthMiddlware(), oauthHandler.GetLinkURL)
+	userRoute.Post("/me/identities/:provider/callback", middleware.AuthMiddlware(), oauthHandler.LinkCallback)
+	userRoute.Delete("/me/identities/:id", middleware.AuthMiddlware(), oauthHandler.UnlinkIdentity)
+	userRoute.Get("/me/2fa", middleware.AuthMiddlware(), twoFactorHandler.FindStatus)
+	userRoute.Post("/me/2fa/setup", middleware.AuthMiddlware(), twoFactorHandler.Setup)
+	userRoute.Post("/me/2fa/enable", middleware.AuthMiddlware(), twoFactorHandler.Enable)
+	userRoute.Post("/me/2fa/disable", middleware.AuthMiddlware(), twoFactorHandler.Disable)
+	userRoute.Post("/me/2fa/recovery-codes", middleware.AuthMiddlware(), twoFactorHandler.RegenerateRecoveryCodes)
 
+	// profil publik by username, username lama diredirect ke yang baru
+	userRoute.Get("/@:username", profileHandler.FindPublicProfile)
 	userRoute.Get("/:id", middleware.AuthMiddlware(), userHandler.GetDetailUser)
 
 	// Follow/Unfollow user
 	userRoute.Post("/:id/follow", middleware.AuthMiddlware(), userHandler.FollowUser)
 	userRoute.Delete("/:id/follow", middleware.AuthMiddlware(), userHandler.UnfollowUser)
 
+	// Block/mute user
+	userRoute.Post("/:id/block", middleware.AuthMiddlware(), userBlockHandler.BlockUser)
+	userRoute.Delete("/:id/block", middleware.AuthMiddlware(), userBlockHandler.UnblockUser)
+	userRoute.Post("/:id/mute", middleware.AuthMiddlware(), userBlockHandler.MuteUser)
+	userRoute.Delete("/:id/mute", middleware.AuthMiddlware(), userBlockHandler.UnmuteUser)
+
 	// Check follow status
 	userRoute.Get("/:id/follow/status", middleware.AuthMiddlware(), userHandler.CheckFollowStatus)
 
@@ -42,3 +91,15 @@ func SetUserRoute(router fiber.Router, db *gorm.DB) {
 	userRoute.Get("/:id/followers/count", middleware.AuthMiddlware(), userHandler.GetFollowerCount)
 	userRoute.Get("/:id/following/count", middleware.AuthMiddlware(), userHandler.GetFollowingCount)
 }
+
+func newAccountService(db *gorm.DB) services.AccountService {
+	return services.NewAccountService(
+		repository.NewUserRepository(db),
+		repository.NewUserIdentityRepository(db),
+		repository.NewAccountRepository(db),
+		repository.NewDataExportRepository(db),
+		repository.NewSecurityLogRepository(db),
+		services.NewSearchService(search.GetSearchIndex(), repository.NewPostRepository(db)),
+		services.NewLocalStorage("./public", "/public"),
+	)
+}
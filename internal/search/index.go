@@ -0,0 +1,87 @@
+package search
+
+import (
+	"log"
+	"time"
+)
+
+// Document adalah representasi post yang disimpan di index
+type Document struct {
+	ID           int64
+	Title        string
+	Slug         string
+	Content      string
+	CategoryID   int64
+	CategoryName string
+	AuthorID     int64
+	AuthorName   string
+	PublishedAt  *time.Time
+	// UpdatedAt dibandingkan dengan updated_at post saat rekonsiliasi untuk mencari dokumen basi
+	UpdatedAt time.Time
+}
+
+type Query struct {
+	Text       string
+	CategoryID int64
+	AuthorID   int64
+	Offset     int
+	Limit      int
+}
+
+type Hit struct {
+	PostID       int64      `json:"postId"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Snippet      string     `json:"snippet"`
+	Score        float64    `json:"score"`
+	CategoryID   int64      `json:"categoryId,omitempty"`
+	CategoryName string     `json:"categoryName,omitempty"`
+	AuthorID     int64      `json:"authorId"`
+	AuthorName   string     `json:"authorName"`
+	PublishedAt  *time.Time `json:"publishedAt,omitempty"`
+}
+
+type FacetCount struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Facets struct {
+	Categories []FacetCount `json:"categories"`
+	Authors    []FacetCount `json:"authors"`
+}
+
+type Result struct {
+	Hits   []Hit
+	Total  int64
+	Facets Facets
+}
+
+type SearchIndex interface {
+	Index(doc Document) error
+	IndexMany(docs []Document) error
+	Delete(id int64) error
+	DeleteMany(ids []int64) error
+	Search(query Query) (*Result, error)
+	Count() int
+	// Versions mengembalikan UpdatedAt setiap dokumen per id, dipakai rekonsiliasi dengan database
+	Versions() (map[int64]time.Time, error)
+}
+
+var searchIndex SearchIndex
+
+func InitSearchIndex(path string) {
+	index, err := NewLocalIndex(path)
+	if err != nil {
+		log.Fatalf("failed to open search index %v", err)
+	}
+	searchIndex = index
+}
+
+func GetSearchIndex() SearchIndex {
+	if searchIndex == nil {
+		log.Fatal("search index is not initialized")
+	}
+	return searchIndex
+}
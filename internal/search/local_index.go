@@ -0,0 +1,423 @@
+package search
+
+import (
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	titleBoost    = 3
+	bm25K1        = 1.2
+	bm25B         = 0.75
+	snippetLength = 200
+)
+
+type indexedDoc struct {
+	Doc   Document
+	Terms map[string]int
+	Len   int
+}
+
+// LocalIndex adalah inverted index di memory yang disimpan ke disk sebagai snapshot
+// ditambah journal append-only. Dengan Prefork setiap process punya salinan sendiri,
+// jadi sebelum baca/tulis process hanya menerapkan entry journal yang belum dibaca;
+// snapshot baru ditulis ulang saat journal sudah sebesar isi index (compaction).
+// Penulisan dikunci dengan exclusive file lock, pembacaan dengan shared lock.
+type LocalIndex struct {
+	path     string
+	mu       sync.RWMutex
+	docs     map[int64]*indexedDoc
+	postings map[string]map[int64]struct{}
+	totalLen int
+
+	generation  int64
+	snapshotMod time.Time
+	logOffset   int64
+	logEntries  int
+}
+
+func NewLocalIndex(path string) (*LocalIndex, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	index := &LocalIndex{
+		path:     path,
+		docs:     make(map[int64]*indexedDoc),
+		postings: make(map[string]map[int64]struct{}),
+	}
+
+	if err := index.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+func (i *LocalIndex) Count() int {
+	_ = i.reloadIfChanged()
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+func (i *LocalIndex) Index(doc Document) error {
+	return i.mutate(logRecord{Docs: []Document{doc}})
+}
+
+// IndexMany menyimpan banyak dokumen dengan satu kali tulis ke disk, dipakai saat reindex
+func (i *LocalIndex) IndexMany(docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	return i.mutate(logRecord{Docs: docs})
+}
+
+func (i *LocalIndex) Delete(id int64) error {
+	return i.mutate(logRecord{Deleted: []int64{id}})
+}
+
+func (i *LocalIndex) DeleteMany(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return i.mutate(logRecord{Deleted: ids})
+}
+
+func (i *LocalIndex) Versions() (map[int64]time.Time, error) {
+	if err := i.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	versions := make(map[int64]time.Time, len(i.docs))
+	for id, doc := range i.docs {
+		versions[id] = doc.Doc.UpdatedAt
+	}
+	return versions, nil
+}
+
+func (i *LocalIndex) Search(query Query) (*Result, error) {
+	if err := i.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	terms := uniqueTerms(tokenize(query.Text))
+	result := &Result{Hits: []Hit{}, Facets: Facets{Categories: []FacetCount{}, Authors: []FacetCount{}}}
+	if len(terms) == 0 || len(i.docs) == 0 {
+		return result, nil
+	}
+
+	avgLen := float64(i.totalLen) / float64(len(i.docs))
+	scores := make(map[int64]float64)
+
+	for _, term := range terms {
+		posting := i.postings[term]
+		if len(posting) == 0 {
+			continue
+		}
+
+		df := float64(len(posting))
+		idf := math.Log(1 + (float64(len(i.docs))-df+0.5)/(df+0.5))
+
+		for id := range posting {
+			doc := i.docs[id]
+			tf := float64(doc.Terms[term])
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(doc.Len)/avgLen)
+			scores[id] += idf * (tf * (bm25K1 + 1)) / (tf + norm)
+		}
+	}
+
+	// facet dihitung sebelum filter supaya client tetap bisa lihat jumlah kategori/penulis lain
+	categoryFacets := make(map[int64]*FacetCount)
+	authorFacets := make(map[int64]*FacetCount)
+	matched := make([]int64, 0, len(scores))
+
+	for id := range scores {
+		doc := i.docs[id].Doc
+
+		if doc.CategoryID != 0 {
+			if _, ok := categoryFacets[doc.CategoryID]; !ok {
+				categoryFacets[doc.CategoryID] = &FacetCount{ID: doc.CategoryID, Name: doc.CategoryName}
+			}
+			categoryFacets[doc.CategoryID].Count++
+		}
+		if _, ok := authorFacets[doc.AuthorID]; !ok {
+			authorFacets[doc.AuthorID] = &FacetCount{ID: doc.AuthorID, Name: doc.AuthorName}
+		}
+		authorFacets[doc.AuthorID].Count++
+
+		if query.CategoryID != 0 && doc.CategoryID != query.CategoryID {
+			continue
+		}
+		if query.AuthorID != 0 && doc.AuthorID != query.AuthorID {
+			continue
+		}
+		matched = append(matched, id)
+	}
+
+	sort.Slice(matched, func(a, b int) bool {
+		if scores[matched[a]] != scores[matched[b]] {
+			return scores[matched[a]] > scores[matched[b]]
+		}
+		return matched[a] > matched[b]
+	})
+
+	result.Total = int64(len(matched))
+	result.Facets.Categories = sortedFacets(categoryFacets)
+	result.Facets.Authors = sortedFacets(authorFacets)
+
+	start := query.Offset
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := len(matched)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+
+	termSet := make(map[string]struct{}, len(terms))
+	for _, term := range terms {
+		termSet[term] = struct{}{}
+	}
+
+	for _, id := range matched[start:end] {
+		doc := i.docs[id].Doc
+		result.Hits = append(result.Hits, Hit{
+			PostID:       doc.ID,
+			Title:        highlight(doc.Title, termSet),
+			Slug:         doc.Slug,
+			Snippet:      snippet(plainText(doc.Content), termSet),
+			Score:        math.Round(scores[id]*1000) / 1000,
+			CategoryID:   doc.CategoryID,
+			CategoryName: doc.CategoryName,
+			AuthorID:     doc.AuthorID,
+			AuthorName:   doc.AuthorName,
+			PublishedAt:  doc.PublishedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// newIndexedDoc menggabungkan term judul (dengan boost) dan konten menjadi satu bobot
+func newIndexedDoc(doc Document) *indexedDoc {
+	titleTerms, titleLen := termFrequencies(doc.Title)
+	terms, contentLen := termFrequencies(plainText(doc.Content))
+
+	for term, tf := range titleTerms {
+		terms[term] += tf * titleBoost
+	}
+
+	return &indexedDoc{Doc: doc, Terms: terms, Len: titleLen*titleBoost + contentLen}
+}
+
+func (i *LocalIndex) addDoc(doc *indexedDoc) {
+	i.docs[doc.Doc.ID] = doc
+	i.totalLen += doc.Len
+	for term := range doc.Terms {
+		if i.postings[term] == nil {
+			i.postings[term] = make(map[int64]struct{})
+		}
+		i.postings[term][doc.Doc.ID] = struct{}{}
+	}
+}
+
+func (i *LocalIndex) removeDoc(id int64) {
+	existing, ok := i.docs[id]
+	if !ok {
+		return
+	}
+
+	for term := range existing.Terms {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	i.totalLen -= existing.Len
+	delete(i.docs, id)
+}
+
+// mutate memuat ulang index terbaru lalu menulis perubahan ke journal (atau snapshot
+// baru saat compaction) sebelum diterapkan di memory, semuanya dalam satu file lock
+func (i *LocalIndex) mutate(record logRecord) error {
+	unlock, err := lockFile(i.lockPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.reload(); err != nil {
+		return err
+	}
+
+	// snapshot belum ada atau journal sudah sebesar isi index: tulis ulang snapshot
+	if i.snapshotMod.IsZero() || i.logEntries+record.size() > max(len(i.docs), minCompactEntries) {
+		i.apply(record)
+		if err := i.compact(); err != nil {
+			// memory sudah berubah tapi disk belum, kosongkan supaya dimuat ulang penuh berikutnya
+			i.reset()
+			return err
+		}
+		return nil
+	}
+
+	if err := i.appendLog(record); err != nil {
+		return err
+	}
+	i.apply(record)
+
+	return nil
+}
+
+// apply harus dipanggil dengan i.mu terkunci
+func (i *LocalIndex) apply(record logRecord) {
+	for _, id := range record.Deleted {
+		i.removeDoc(id)
+	}
+	for _, doc := range record.Docs {
+		i.removeDoc(doc.ID)
+		i.addDoc(newIndexedDoc(doc))
+	}
+}
+
+func uniqueTerms(tokens []string) []string {
+	seen := make(map[string]struct{}, len(tokens))
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		terms = append(terms, token)
+	}
+	return terms
+}
+
+func sortedFacets(facets map[int64]*FacetCount) []FacetCount {
+	result := make([]FacetCount, 0, len(facets))
+	for _, facet := range facets {
+		result = append(result, *facet)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Count != result[b].Count {
+			return result[a].Count > result[b].Count
+		}
+		return result[a].ID < result[b].ID
+	})
+	return result
+}
+
+// snippet mengambil potongan teks di sekitar kata pertama yang cocok lalu di-highlight
+func snippet(text string, terms map[string]struct{}) string {
+	runes := []rune(text)
+	if len(runes) <= snippetLength {
+		return highlight(text, terms)
+	}
+
+	// pencarian dilakukan per rune karena huruf kecil bisa beda panjang byte dengan aslinya
+	lower := make([]rune, len(runes))
+	for idx, r := range runes {
+		lower[idx] = unicode.ToLower(r)
+	}
+
+	first := -1
+	for term := range terms {
+		if idx := indexRunes(lower, []rune(term)); idx >= 0 && (first == -1 || idx < first) {
+			first = idx
+		}
+	}
+
+	start := 0
+	if first > 0 {
+		start = first - snippetLength/4
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+		start = end - snippetLength
+	}
+
+	// potong di batas kata supaya tidak ada kata terpotong
+	for start > 0 && start < end && isWordRune(runes[start-1]) && isWordRune(runes[start]) {
+		start++
+	}
+	for end < len(runes) && end > start && isWordRune(runes[end-1]) && isWordRune(runes[end]) {
+		end--
+	}
+
+	result := highlight(string(runes[start:end]), terms)
+	if start > 0 {
+		result = "…" + result
+	}
+	if end < len(runes) {
+		result += "…"
+	}
+	return result
+}
+
+// indexRunes sama seperti strings.Index tapi posisi yang dikembalikan adalah index rune
+func indexRunes(haystack []rune, needle []rune) int {
+	if len(needle) == 0 {
+		return -1
+	}
+
+	for idx := 0; idx+len(needle) <= len(haystack); idx++ {
+		if slices.Equal(haystack[idx:idx+len(needle)], needle) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// highlight meng-escape teks dan membungkus kata yang cocok dengan <mark>
+func highlight(text string, terms map[string]struct{}) string {
+	var b strings.Builder
+	var word []rune
+
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		escaped := html.EscapeString(string(word))
+		if _, ok := terms[strings.ToLower(string(word))]; ok {
+			b.WriteString("<mark>" + escaped + "</mark>")
+		} else {
+			b.WriteString(escaped)
+		}
+		word = word[:0]
+	}
+
+	for _, r := range text {
+		if isWordRune(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	flush()
+
+	return b.String()
+}
@@ -0,0 +1,203 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnippetMultibyteLowercase(t *testing.T) {
+	// "Ⱥ" 2 byte tapi huruf kecilnya "ⱥ" 3 byte, offset byte dari teks lowercase tidak valid di teks asli
+	text := strings.Repeat("Ⱥ", 300) + " golang"
+	terms := map[string]struct{}{"golang": {}}
+
+	got := snippet(text, terms)
+	if !strings.Contains(got, "<mark>golang</mark>") {
+		t.Fatalf("snippet %q does not highlight the match", got)
+	}
+}
+
+func TestSnippetCentersOnMatch(t *testing.T) {
+	text := strings.Repeat("Ⱥbc ", 100) + "golang " + strings.Repeat("xyz ", 100)
+	terms := map[string]struct{}{"golang": {}}
+
+	got := snippet(text, terms)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Fatalf("snippet %q should be trimmed on both sides", got)
+	}
+	if !strings.Contains(got, "<mark>golang</mark>") {
+		t.Fatalf("snippet %q does not highlight the match", got)
+	}
+}
+
+func newTestIndex(t *testing.T, path string) *LocalIndex {
+	t.Helper()
+	index, err := NewLocalIndex(path)
+	if err != nil {
+		t.Fatalf("NewLocalIndex: %v", err)
+	}
+	return index
+}
+
+func searchIDs(t *testing.T, index *LocalIndex, text string) []int64 {
+	t.Helper()
+	result, err := index.Search(Query{Text: text})
+	if err != nil {
+		t.Fatalf("Search(%q): %v", text, err)
+	}
+	ids := make([]int64, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.PostID)
+	}
+	return ids
+}
+
+func TestLocalIndexSharedBetweenProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.gob")
+	writer := newTestIndex(t, path)
+	reader := newTestIndex(t, path)
+
+	docs := make([]Document, 0, 50)
+	for id := int64(1); id <= 50; id++ {
+		docs = append(docs, Document{ID: id, Title: fmt.Sprintf("post %d", id), Content: "golang search"})
+	}
+	if err := writer.IndexMany(docs); err != nil {
+		t.Fatalf("IndexMany: %v", err)
+	}
+	if got := reader.Count(); got != 50 {
+		t.Fatalf("reader count = %d, want 50", got)
+	}
+
+	// perubahan kecil hanya menambah journal, snapshot tidak ditulis ulang
+	snapshotInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat snapshot: %v", err)
+	}
+	if err := writer.Index(Document{ID: 51, Title: "rust ownership"}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := writer.Delete(1); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(snapshotInfo.ModTime()) {
+		t.Fatal("snapshot was rewritten for a single mutation")
+	}
+
+	if ids := searchIDs(t, reader, "rust"); len(ids) != 1 || ids[0] != 51 {
+		t.Fatalf("reader rust hits = %v, want [51]", ids)
+	}
+	if got := reader.Count(); got != 50 {
+		t.Fatalf("reader count after delete = %d, want 50", got)
+	}
+
+	// penulis kedua harus melanjutkan journal yang sama
+	if err := reader.Index(Document{ID: 1, Title: "rust again"}); err != nil {
+		t.Fatalf("Index from reader: %v", err)
+	}
+	if ids := searchIDs(t, writer, "rust"); len(ids) != 2 {
+		t.Fatalf("writer rust hits = %v, want 2 hits", ids)
+	}
+
+	reopened := newTestIndex(t, path)
+	if got := reopened.Count(); got != 51 {
+		t.Fatalf("reopened count = %d, want 51", got)
+	}
+}
+
+func TestLocalIndexCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.gob")
+	writer := newTestIndex(t, path)
+	reader := newTestIndex(t, path)
+
+	for id := int64(1); id <= minCompactEntries+10; id++ {
+		if err := writer.Index(Document{ID: id % 20, Title: fmt.Sprintf("rev %d golang", id)}); err != nil {
+			t.Fatalf("Index: %v", err)
+		}
+		if id == 5 {
+			// reader sempat membaca sebagian journal sebelum compaction
+			if got := reader.Count(); got != 5 {
+				t.Fatalf("reader count = %d, want 5", got)
+			}
+		}
+	}
+
+	if info, err := os.Stat(path + ".log"); err == nil && info.Size() > 1<<20 {
+		t.Fatalf("journal grew to %d bytes without compaction", info.Size())
+	}
+	if writer.generation < 2 {
+		t.Fatalf("generation = %d, want compaction to have happened", writer.generation)
+	}
+
+	if got := reader.Count(); got != 20 {
+		t.Fatalf("reader count = %d, want 20", got)
+	}
+	if ids := searchIDs(t, reader, fmt.Sprintf("rev %d", minCompactEntries+10)); len(ids) == 0 || ids[0] != (minCompactEntries+10)%20 {
+		t.Fatalf("reader does not see latest revision, hits = %v", ids)
+	}
+}
+
+func TestLocalIndexSkipsTornFrame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.gob")
+	writer := newTestIndex(t, path)
+
+	if err := writer.IndexMany([]Document{{ID: 1, Title: "golang"}}); err != nil {
+		t.Fatalf("IndexMany: %v", err)
+	}
+	if err := writer.Index(Document{ID: 2, Title: "golang"}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+
+	// simulasi penulis crash di tengah frame
+	file, err := os.OpenFile(path+".log", os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	file.Write([]byte{0, 0, 1, 0, 42})
+	file.Close()
+
+	reader := newTestIndex(t, path)
+	if got := reader.Count(); got != 2 {
+		t.Fatalf("reader count = %d, want 2", got)
+	}
+
+	if err := reader.Index(Document{ID: 3, Title: "golang"}); err != nil {
+		t.Fatalf("Index after torn frame: %v", err)
+	}
+	if got := newTestIndex(t, path).Count(); got != 3 {
+		t.Fatalf("reopened count = %d, want 3", got)
+	}
+}
+
+func TestLocalIndexVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.gob")
+	writer := newTestIndex(t, path)
+	reader := newTestIndex(t, path)
+
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	docs := []Document{
+		{ID: 1, Title: "golang", UpdatedAt: updatedAt},
+		{ID: 2, Title: "golang", UpdatedAt: updatedAt},
+		{ID: 3, Title: "golang", UpdatedAt: updatedAt},
+	}
+	if err := writer.IndexMany(docs); err != nil {
+		t.Fatalf("IndexMany: %v", err)
+	}
+	if err := writer.Index(Document{ID: 2, Title: "golang", UpdatedAt: updatedAt.Add(time.Minute)}); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := writer.DeleteMany([]int64{1, 3}); err != nil {
+		t.Fatalf("DeleteMany: %v", err)
+	}
+
+	// versi harus tetap sama setelah lewat journal
+	versions, err := reader.Versions()
+	if err != nil {
+		t.Fatalf("Versions: %v", err)
+	}
+	if len(versions) != 1 || !versions[2].Equal(updatedAt.Add(time.Minute)) {
+		t.Fatalf("versions = %v, want only post 2 updated a minute later", versions)
+	}
+}
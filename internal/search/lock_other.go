@@ -0,0 +1,18 @@
+//go:build !unix
+
+package search
+
+import "sync"
+
+var localLock sync.RWMutex
+
+// lockFile di platform non-unix hanya mengunci di dalam process
+func lockFile(path string) (func(), error) {
+	localLock.Lock()
+	return localLock.Unlock, nil
+}
+
+func rlockFile(path string) (func(), error) {
+	localLock.RLock()
+	return localLock.RUnlock, nil
+}
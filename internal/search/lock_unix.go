@@ -0,0 +1,35 @@
+//go:build unix
+
+package search
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile mengambil exclusive lock lintas process (misal child Prefork)
+func lockFile(path string) (func(), error) {
+	return flockFile(path, syscall.LOCK_EX)
+}
+
+// rlockFile mengambil shared lock, dipakai saat membaca supaya tidak bertabrakan dengan compaction
+func rlockFile(path string) (func(), error) {
+	return flockFile(path, syscall.LOCK_SH)
+}
+
+func flockFile(path string, how int) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), how); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,291 @@
+package search
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// journal di-compact minimal setelah sekian entry supaya index kecil tidak terus menulis snapshot
+	minCompactEntries = 1000
+
+	logHeaderSize = 8
+	frameLenSize  = 4
+)
+
+// snapshot adalah isi index lengkap, generation naik setiap compaction
+type snapshot struct {
+	Generation int64
+	Docs       []Document
+}
+
+// logRecord satu perubahan di journal, disimpan sebagai frame [panjang uint32][gob]
+type logRecord struct {
+	Docs    []Document
+	Deleted []int64
+}
+
+func (r logRecord) size() int {
+	return len(r.Docs) + len(r.Deleted)
+}
+
+func (i *LocalIndex) logPath() string {
+	return i.path + ".log"
+}
+
+func (i *LocalIndex) lockPath() string {
+	return i.path + ".lock"
+}
+
+// reloadIfChanged mengecek ukuran/waktu file tanpa lock, baru membaca perubahan kalau ada
+func (i *LocalIndex) reloadIfChanged() error {
+	var snapshotMod time.Time
+	info, err := os.Stat(i.path)
+	if err == nil {
+		snapshotMod = info.ModTime()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	var logSize int64
+	info, err = os.Stat(i.logPath())
+	if err == nil {
+		logSize = info.Size()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	i.mu.RLock()
+	unchanged := snapshotMod.Equal(i.snapshotMod) && logSize == i.logOffset
+	i.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	unlock, err := rlockFile(i.lockPath())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.reload()
+}
+
+// reload memuat snapshot kalau sudah diganti lalu menerapkan entry journal yang belum dibaca.
+// Harus dipanggil dengan file lock dan i.mu terkunci.
+func (i *LocalIndex) reload() error {
+	info, err := os.Stat(i.path)
+	if errors.Is(err, os.ErrNotExist) {
+		if !i.snapshotMod.IsZero() {
+			i.reset()
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !info.ModTime().Equal(i.snapshotMod) {
+		if err := i.loadSnapshot(); err != nil {
+			return err
+		}
+		i.snapshotMod = info.ModTime()
+	}
+
+	return i.readLog()
+}
+
+func (i *LocalIndex) reset() {
+	i.docs = make(map[int64]*indexedDoc)
+	i.postings = make(map[string]map[int64]struct{})
+	i.totalLen = 0
+	i.generation = 0
+	i.snapshotMod = time.Time{}
+	i.logOffset = 0
+	i.logEntries = 0
+}
+
+func (i *LocalIndex) loadSnapshot() error {
+	file, err := os.Open(i.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(file).Decode(&snap); err != nil {
+		// file format lama hanya berisi []Document tanpa generation
+		if _, seekErr := file.Seek(0, io.SeekStart); seekErr != nil {
+			return err
+		}
+		if legacyErr := gob.NewDecoder(file).Decode(&snap.Docs); legacyErr != nil {
+			return err
+		}
+	}
+
+	i.reset()
+	i.docs = make(map[int64]*indexedDoc, len(snap.Docs))
+	i.generation = snap.Generation
+	for _, doc := range snap.Docs {
+		i.addDoc(newIndexedDoc(doc))
+	}
+
+	return nil
+}
+
+// readLog menerapkan frame journal mulai dari logOffset. Journal dengan generation berbeda
+// adalah sisa sebelum compaction (isinya sudah ada di snapshot) dan diabaikan.
+func (i *LocalIndex) readLog() error {
+	file, err := os.Open(i.logPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var header [logHeaderSize]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		return err
+	}
+	if int64(binary.BigEndian.Uint64(header[:])) != i.generation {
+		return nil
+	}
+
+	if i.logOffset < logHeaderSize {
+		i.logOffset = logHeaderSize
+	}
+	if _, err := file.Seek(i.logOffset, io.SeekStart); err != nil {
+		return err
+	}
+
+	pending, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	// frame terakhir yang belum lengkap (penulis crash) dilewati dan ditimpa penulisan berikutnya
+	for len(pending) >= frameLenSize {
+		frameLen := int(binary.BigEndian.Uint32(pending))
+		if len(pending) < frameLenSize+frameLen {
+			break
+		}
+
+		var record logRecord
+		if err := gob.NewDecoder(bytes.NewReader(pending[frameLenSize : frameLenSize+frameLen])).Decode(&record); err != nil {
+			return err
+		}
+		i.apply(record)
+
+		i.logOffset += int64(frameLenSize + frameLen)
+		i.logEntries += record.size()
+		pending = pending[frameLenSize+frameLen:]
+	}
+
+	return nil
+}
+
+// appendLog menulis satu frame di akhir journal, harus dipanggil dengan exclusive lock dan i.mu terkunci
+func (i *LocalIndex) appendLog(record logRecord) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(record); err != nil {
+		return err
+	}
+
+	frame := make([]byte, frameLenSize, frameLenSize+payload.Len())
+	binary.BigEndian.PutUint32(frame, uint32(payload.Len()))
+	frame = append(frame, payload.Bytes()...)
+
+	file, err := os.OpenFile(i.logPath(), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// journal belum ada / dari generation lama dimulai ulang dengan header generation sekarang
+	if i.logOffset < logHeaderSize {
+		var header [logHeaderSize]byte
+		binary.BigEndian.PutUint64(header[:], uint64(i.generation))
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(header[:], 0); err != nil {
+			return err
+		}
+		i.logOffset = logHeaderSize
+	}
+
+	if err := file.Truncate(i.logOffset); err != nil {
+		return err
+	}
+	if _, err := file.WriteAt(frame, i.logOffset); err != nil {
+		return err
+	}
+
+	i.logOffset += int64(len(frame))
+	i.logEntries += record.size()
+
+	return nil
+}
+
+// compact menulis snapshot baru dengan generation berikutnya lalu mengosongkan journal,
+// harus dipanggil dengan exclusive lock dan i.mu terkunci
+func (i *LocalIndex) compact() error {
+	snap := snapshot{Generation: i.generation + 1, Docs: make([]Document, 0, len(i.docs))}
+	for _, doc := range i.docs {
+		snap.Docs = append(snap.Docs, doc.Doc)
+	}
+
+	if err := writeFileAtomic(i.path, func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(snap)
+	}); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(i.path)
+	if err != nil {
+		return err
+	}
+
+	i.generation = snap.Generation
+	i.snapshotMod = info.ModTime()
+	i.logOffset = 0
+	i.logEntries = 0
+
+	// journal lama tertinggal kalau gagal dihapus, tapi diabaikan karena generation-nya berbeda
+	if err := os.Remove(i.logPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
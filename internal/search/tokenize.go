@@ -0,0 +1,56 @@
+package search
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+var stopWords = map[string]struct{}{
+	"the": {}, "and": {}, "for": {}, "are": {}, "but": {}, "not": {}, "you": {}, "with": {},
+	"this": {}, "that": {}, "from": {}, "was": {}, "have": {}, "has": {}, "its": {}, "of": {},
+	"to": {}, "in": {}, "on": {}, "is": {}, "it": {}, "an": {}, "as": {}, "at": {}, "by": {}, "or": {},
+	"yang": {}, "dan": {}, "di": {}, "ke": {}, "dari": {}, "ini": {}, "itu": {}, "untuk": {},
+	"dengan": {}, "pada": {}, "adalah": {}, "dalam": {}, "tidak": {}, "akan": {}, "juga": {},
+}
+
+// plainText membuang tag HTML supaya konten editor bisa diindex dan dibuat snippet
+func plainText(content string) string {
+	text := htmlTagPattern.ReplaceAllString(content, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 {
+			continue
+		}
+		if _, stop := stopWords[word]; stop {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+
+	return tokens
+}
+
+func termFrequencies(text string) (map[string]int, int) {
+	tokens := tokenize(text)
+	freq := make(map[string]int, len(tokens))
+	for _, token := range tokens {
+		freq[token]++
+	}
+	return freq, len(tokens)
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// DeletedUserName nama yang tampil di komentar dan post milik akun yang sudah dihapus
+const DeletedUserName = "deleted user"
+
+type AccountService interface {
+	RequestExport(user utils.Claims) (*dto.DataExportResponse, error)
+	FindExportFile(exportId int64, user utils.Claims) (filePath string, filename string, err error)
+	BuildExport(export models.DataExport) error
+	CleanupExpiredExports(now time.Time, limit int) error
+	RequestDeletion(req dto.DeleteAccountRequest, user utils.Claims, meta dto.SessionMeta) (*dto.AccountDeletionResponse, error)
+	CancelDeletion(user utils.Claims, meta dto.SessionMeta) error
+	DeleteAccount(user models.User) error
+}
+
+type AccountServiceImpl struct {
+	UserRepository         repository.UserRepository
+	UserIdentityRepository repository.UserIdentityRepository
+	AccountRepository      repository.AccountRepository
+	DataExportRepository   repository.DataExportRepository
+	SecurityLogRepository  repository.SecurityLogRepository
+	SearchService          SearchService
+	Storage                StorageService
+}
+
+func NewAccountService(
+	userRepository repository.UserRepository,
+	userIdentityRepository repository.UserIdentityRepository,
+	accountRepository repository.AccountRepository,
+	dataExportRepository repository.DataExportRepository,
+	securityLogRepository repository.SecurityLogRepository,
+	searchService SearchService,
+	storage StorageService,
+) AccountService {
+	return &AccountServiceImpl{
+		UserRepository:         userRepository,
+		UserIdentityRepository: userIdentityRepository,
+		AccountRepository:      accountRepository,
+		DataExportRepository:   dataExportRepository,
+		SecurityLogRepository:  securityLogRepository,
+		SearchService:          searchService,
+		Storage:                storage,
+	}
+}
+
+// RequestExport memakai export terakhir kalau masih diproses atau masih bisa diunduh, selain itu membuat job baru
+func (s *AccountServiceImpl) RequestExport(user utils.Claims) (*dto.DataExportResponse, error) {
+	userId := int64(user.UserId)
+
+	latest, err := s.DataExportRepository.FindLatestByUserId(userId)
+	if err != nil && !isNotFoundErr(err) {
+		return nil, err
+	}
+
+	if latest != nil {
+		switch enum.DataExportStatus(latest.Status) {
+		case enum.DataExportPending, enum.DataExportProcessing:
+			return toDataExportResponse(latest), nil
+		case enum.DataExportReady:
+			if latest.ExpiresAt != nil && latest.ExpiresAt.After(time.Now()) {
+				return toDataExportResponse(latest), nil
+			}
+		}
+	}
+
+	export := models.DataExport{
+		UserID: userId,
+		Status: string(enum.DataExportPending),
+	}
+	if err := s.DataExportRepository.Create(&export); err != nil {
+		return nil, err
+	}
+
+	return toDataExportResponse(&export), nil
+}
+
+func (s *AccountServiceImpl) FindExportFile(exportId int64, user utils.Claims) (string, string, error) {
+	latest, err := s.DataExportRepository.FindLatestByUserId(int64(user.UserId))
+	if err != nil {
+		return "", "", err
+	}
+
+	// hanya export terakhir yang boleh diunduh, export lama dianggap sudah diganti
+	if latest.ID != exportId {
+		return "", "", exception.NewNotFoundErr("data export not found")
+	}
+	if enum.DataExportStatus(latest.Status) != enum.DataExportReady || latest.FilePath == nil {
+		return "", "", exception.NewBadRequestErr("data export is not ready yet")
+	}
+	if latest.ExpiresAt != nil && latest.ExpiresAt.Before(time.Now()) {
+		return "", "", exception.NewNotFoundErr("data export has expired, request a new one")
+	}
+
+	if _, err := os.Stat(*latest.FilePath); err != nil {
+		return "", "", exception.NewNotFoundErr("data export file not found, request a new one")
+	}
+
+	filename := fmt.Sprintf("export-%d-%s.zip", latest.UserID, latest.CreatedAt.Format("20060102"))
+	return *latest.FilePath, filename, nil
+}
+
+// BuildExport dipanggil worker setelah job di-claim, membuat zip berisi file JSON dan asset upload user
+func (s *AccountServiceImpl) BuildExport(export models.DataExport) error {
+	filePath, fileSize, err := s.writeExport(export)
+	if err != nil {
+		if markErr := s.DataExportRepository.MarkFailed(export.ID, truncate(err.Error(), 255)); markErr != nil {
+			utils.Logger.Errorf("failed to mark data export %d as failed %v", export.ID, markErr)
+		}
+		return err
+	}
+
+	expiresAt := time.Now().Add(config.AppConfig.Account.GetExportTTL())
+	if err := s.DataExportRepository.MarkReady(export.ID, filePath, fileSize, expiresAt); err != nil {
+		_ = os.Remove(filePath)
+		return err
+	}
+
+	return nil
+}
+
+func (s *AccountServiceImpl) writeExport(export models.DataExport) (string, int64, error) {
+	user, err := s.UserRepository.FindById(int(export.UserID))
+	if err != nil {
+		return "", 0, err
+	}
+
+	exportDir := config.AppConfig.Account.GetExportDir()
+	if err := os.MkdirAll(exportDir, 0700); err != nil {
+		return "", 0, err
+	}
+
+	// nama file acak supaya tidak bisa ditebak walau folder export ikut terbuka
+	filePath := filepath.Join(exportDir, fmt.Sprintf("%d-%d-%s.zip", export.UserID, export.ID, utils.GenerateRandomString(16)))
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", 0, err
+	}
+
+	archive := zip.NewWriter(file)
+	writeErr := s.writeExportEntries(archive, user)
+	closeErr := archive.Close()
+	fileErr := file.Close()
+
+	if err := errors.Join(writeErr, closeErr, fileErr); err != nil {
+		_ = os.Remove(filePath)
+		return "", 0, err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return filePath, info.Size(), nil
+}
+
+func (s *AccountServiceImpl) writeExportEntries(archive *zip.Writer, user *models.User) error {
+	identities, err := s.UserIdentityRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return err
+	}
+
+	profile := dto.ExportProfile{
+		ID:              user.ID,
+		Name:            user.Name,
+		Username:        user.Username,
+		Email:           user.Email,
+		Bio:             user.Bio,
+		ProfileImageURI: user.ProfileImageURI,
+		SocialLinks:     user.SocialLinks,
+		Role:            user.Role,
+		Status:          user.Status,
+		AuthProvider:    user.AuthProvider,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		IsSubscribed:    user.IsSubscribed,
+		SubscriptionEnd: user.SubscriptionEnd,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+		Identities:      identities,
+	}
+	if err := writeZipJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	posts, err := s.AccountRepository.FindPostsByAuthor(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "posts.json", posts); err != nil {
+		return err
+	}
+
+	postIds := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		postIds = append(postIds, post.ID)
+	}
+	assets, err := s.AccountRepository.FindPostAssets(postIds)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "post_assets.json", assets); err != nil {
+		return err
+	}
+
+	comments, err := s.AccountRepository.FindCommentsByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "comments.json", comments); err != nil {
+		return err
+	}
+
+	likes, err := s.AccountRepository.FindLikesByUser(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "likes.json", likes); err != nil {
+		return err
+	}
+
+	follows, err := s.AccountRepository.FindFollows(user.ID)
+	if err != nil {
+		return err
+	}
+	exportFollows := dto.ExportFollows{
+		Followers: []models.Follower{},
+		Following: []models.Follower{},
+	}
+	for _, follow := range follows {
+		if int64(follow.FollowerID) == user.ID {
+			exportFollows.Following = append(exportFollows.Following, follow)
+		} else {
+			exportFollows.Followers = append(exportFollows.Followers, follow)
+		}
+	}
+	if err := writeZipJSON(archive, "follows.json", exportFollows); err != nil {
+		return err
+	}
+
+	readingLists, err := s.AccountRepository.FindReadingLists(user.ID)
+	if err != nil {
+		return err
+	}
+	savedPosts, err := s.AccountRepository.FindSavedPosts(user.ID)
+	if err != nil {
+		return err
+	}
+	exportLists := make([]dto.ExportReadingList, 0, len(readingLists))
+	for _, readingList := range readingLists {
+		item := dto.ExportReadingList{ReadingList: readingList, SavedPosts: []models.SavedPost{}}
+		for _, savedPost := range savedPosts {
+			if savedPost.ReadingListID == readingList.ID {
+				item.SavedPosts = append(item.SavedPosts, savedPost)
+			}
+		}
+		exportLists = append(exportLists, item)
+	}
+	if err := writeZipJSON(archive, "reading_lists.json", exportLists); err != nil {
+		return err
+	}
+
+	subscriptions, err := s.AccountRepository.FindSubscriptions(user.ID)
+	if err != nil {
+		return err
+	}
+	if err := writeZipJSON(archive, "subscriptions.json", subscriptions); err != nil {
+		return err
+	}
+
+	// asset upload user ikut disalin, file yang hilang/di luar storage dilewati
+	if user.ProfileImageURI != nil {
+		s.copyAssetToZip(archive, *user.ProfileImageURI, "assets/avatar")
+	}
+	for _, post := range posts {
+		if post.MainImageURI != nil {
+			s.copyAssetToZip(archive, *post.MainImageURI, "assets/posts/"+strconv.FormatInt(post.ID, 10))
+		}
+	}
+	for _, asset := range assets {
+		s.copyAssetToZip(archive, asset.AssetURI, "assets/posts/"+strconv.FormatInt(asset.PostID, 10))
+	}
+
+	return nil
+}
+
+func (s *AccountServiceImpl) copyAssetToZip(archive *zip.Writer, uri string, dir string) {
+	src, err := s.Storage.OpenFile(uri)
+	if err != nil {
+		if !errors.Is(err, ErrNotLocalFile) {
+			utils.Logger.Warnf("failed to open asset %s for export %v", uri, err)
+		}
+		return
+	}
+	defer src.Close()
+
+	dst, err := archive.Create(dir + "/" + path.Base(uri))
+	if err != nil {
+		utils.Logger.Warnf("failed to add asset %s to export %v", uri, err)
+		return
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		utils.Logger.Warnf("failed to copy asset %s to export %v", uri, err)
+	}
+}
+
+func writeZipJSON(archive *zip.Writer, name string, data interface{}) error {
+	dst, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(dst)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+func (s *AccountServiceImpl) CleanupExpiredExports(now time.Time, limit int) error {
+	exports, err := s.DataExportRepository.FindExpired(now, limit)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		removeExportFile(export)
+		if err := s.DataExportRepository.Delete(export.ID); err != nil {
+			utils.Logger.Errorf("failed to delete expired data export %d %v", export.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func removeExportFile(export models.DataExport) {
+	if export.FilePath == nil {
+		return
+	}
+	if err := os.Remove(*export.FilePath); err != nil && !os.IsNotExist(err) {
+		utils.Logger.Warnf("failed to remove data export file %s %v", *export.FilePath, err)
+	}
+}
+
+// RequestDeletion menjadwalkan penghapusan akun, selama masa tenggang user masih bisa login dan membatalkan
+func (s *AccountServiceImpl) RequestDeletion(req dto.DeleteAccountRequest, user utils.Claims, meta dto.SessionMeta) (*dto.AccountDeletionResponse, error) {
+	userModel, err := s.UserRepository.FindById(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if userModel.Password != "" {
+		if req.Password == "" {
+			return nil, exception.NewBadRequestErr("password is required")
+		}
+		if err := utils.ComparePassword(req.Password, userModel.Password); err != nil {
+			return nil, exception.NewBadRequestErr("password is invalid")
+		}
+	}
+
+	if userModel.DeletionScheduledAt != nil {
+		return &dto.AccountDeletionResponse{DeletionScheduledAt: *userModel.DeletionScheduledAt}, nil
+	}
+
+	scheduledAt := time.Now().Add(config.AppConfig.Account.GetDeletionGracePeriod())
+	userModel.DeletionScheduledAt = &scheduledAt
+	if err := s.UserRepository.UpdateProfile(userModel, "deletion_scheduled_at"); err != nil {
+		return nil, err
+	}
+
+	s.writeSecurityLog(userModel.ID, enum.SecurityEventAccountDeletionRequested, meta, "scheduled at "+scheduledAt.Format(time.RFC3339))
+
+	return &dto.AccountDeletionResponse{DeletionScheduledAt: scheduledAt}, nil
+}
+
+func (s *AccountServiceImpl) CancelDeletion(user utils.Claims, meta dto.SessionMeta) error {
+	userModel, err := s.UserRepository.FindById(user.UserId)
+	if err != nil {
+		return err
+	}
+
+	if userModel.DeletionScheduledAt == nil {
+		return exception.NewBadRequestErr("account is not scheduled for deletion")
+	}
+
+	userModel.DeletionScheduledAt = nil
+	if err := s.UserRepository.UpdateProfile(userModel, "deletion_scheduled_at"); err != nil {
+		return err
+	}
+
+	s.writeSecurityLog(userModel.ID, enum.SecurityEventAccountDeletionCancelled, meta, "")
+
+	return nil
+}
+
+// DeleteAccount dipanggil worker setelah masa tenggang lewat
+func (s *AccountServiceImpl) DeleteAccount(user models.User) error {
+	accountConfig := config.AppConfig.Account
+	opts := dto.AccountDeletionOptions{
+		Purge:       accountConfig.GetDeletionMode() == config.AccountDeletionPurge,
+		DeletePosts: accountConfig.GetPostAction() == config.AccountPostDelete,
+		ReassignTo:  accountConfig.ReassignUserID,
+		DeletedName: DeletedUserName,
+	}
+
+	// data yang perlu dibersihkan di luar database diambil sebelum barisnya hilang
+	posts, err := s.AccountRepository.FindPostsByAuthor(user.ID)
+	if err != nil {
+		return err
+	}
+
+	postIds := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		postIds = append(postIds, post.ID)
+	}
+
+	var assets []models.PostAsset
+	if opts.DeletePosts {
+		assets, err = s.AccountRepository.FindPostAssets(postIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	exports, err := s.DataExportRepository.FindAllByUserId(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.AccountRepository.DeleteAccount(user.ID, opts); err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		removeExportFile(export)
+	}
+
+	if user.ProfileImageURI != nil {
+		s.deleteLocalFile(*user.ProfileImageURI)
+	}
+
+	for _, post := range posts {
+		if !opts.DeletePosts {
+			// nama author di index pencarian ikut berubah
+			s.SearchService.SyncPost(post.ID)
+			continue
+		}
+
+		s.SearchService.RemovePost(post.ID)
+		if post.MainImageURI != nil {
+			s.deleteLocalFile(*post.MainImageURI)
+		}
+	}
+	for _, asset := range assets {
+		s.deleteLocalFile(asset.AssetURI)
+	}
+
+	s.writeSecurityLog(user.ID, enum.SecurityEventAccountDeleted, dto.SessionMeta{}, fmt.Sprintf("mode %s, posts %s", accountConfig.GetDeletionMode(), accountConfig.GetPostAction()))
+
+	return nil
+}
+
+// deleteLocalFile hanya menghapus file milik storage ini, URL luar dilewati
+func (s *AccountServiceImpl) deleteLocalFile(uri string) {
+	err := s.Storage.DeleteFile(uri)
+	if errors.Is(err, ErrNotLocalFile) || errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		utils.Logger.Warnf("failed to delete file %s %v", uri, err)
+	}
+}
+
+func (s *AccountServiceImpl) writeSecurityLog(userId int64, event enum.SecurityEvent, meta dto.SessionMeta, detail string) {
+	if err := s.SecurityLogRepository.Create(&models.SecurityLog{
+		UserID:    &userId,
+		Event:     string(event),
+		IPAddress: meta.IPAddress,
+		UserAgent: truncate(meta.UserAgent, 255),
+		Detail:    detail,
+	}); err != nil {
+		utils.Logger.Errorf("failed to write security log %v", err)
+	}
+}
+
+func toDataExportResponse(export *models.DataExport) *dto.DataExportResponse {
+	response := &dto.DataExportResponse{
+		ID:           export.ID,
+		Status:       export.Status,
+		FileSize:     export.FileSize,
+		ErrorMessage: export.ErrorMessage,
+		CompletedAt:  export.CompletedAt,
+		ExpiresAt:    export.ExpiresAt,
+		CreatedAt:    export.CreatedAt,
+	}
+
+	if enum.DataExportStatus(export.Status) == enum.DataExportReady {
+		response.DownloadURL = fmt.Sprintf("/api/users/me/export/%d/download", export.ID)
+	}
+
+	return response
+}
@@ -1,37 +1,60 @@
 package services
 
 import (
-	"context"
-	"encoding/json"
 	"errors"
-	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/MrBista/blog-api/internal/config"
 	"github.com/MrBista/blog-api/internal/dto"
 	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/utils"
-	"golang.org/x/oauth2"
 	"gorm.io/gorm"
 )
 
+const (
+	passwordResetTokenPurpose = "password_reset"
+	passwordResetTokenTTL     = 30 * time.Minute
+)
+
 type AuthService interface {
-	LoginUser(reqLogin dto.LoginRequest) (dto.LoginResponse, error)
+	LoginUser(reqLogin dto.LoginRequest, meta dto.SessionMeta) (dto.LoginResponse, error)
 	RegisterUser(reqRegister dto.RegisterRequest) error
-	ConfirmOtp() error
+	ConfirmOtp(req dto.VerifyOtpRequest) error
+	ResendOtp(req dto.ResendOtpRequest) error
+	ForgotPassword(req dto.ForgotPasswordRequest) error
+	ResetPassword(req dto.ResetPasswordRequest) error
+	ChangePassword(req dto.ChangePasswordRequest, user utils.Claims) error
+
+	VerifyTwoFactorLogin(req dto.TwoFactorVerifyRequest, meta dto.SessionMeta) (dto.LoginResponse, error)
+	SetupPendingTwoFactor(req dto.TwoFactorPendingRequest, meta dto.SessionMeta) (*dto.TwoFactorSetupResponse, error)
+	EnablePendingTwoFactor(req dto.TwoFactorPendingEnableRequest, meta dto.SessionMeta) (*dto.TwoFactorEnableResponse, error)
 
-	GetGoogleAuthURL(state string) string
-	HandleGoogleCallback(code string) (dto.LoginResponse, error)
+	CompleteLogin(user *models.User, meta dto.SessionMeta) (dto.LoginResponse, error)
 }
 
 type AuthServiceImpl struct {
-	UserRepo repository.UserRepository
+	UserRepo         repository.UserRepository
+	SessionService   SessionService
+	OtpService       OtpService
+	TwoFactorService TwoFactorService
+	LoginGuard       LoginGuardService
+	Mailer           Mailer
 }
 
-func NewAutService(userRepo repository.UserRepository) AuthService {
+func NewAutService(userRepo repository.UserRepository, sessionService SessionService, otpService OtpService, twoFactorService TwoFactorService, loginGuard LoginGuardService, mailer Mailer) AuthService {
 	return &AuthServiceImpl{
-		UserRepo: userRepo,
+		UserRepo:         userRepo,
+		SessionService:   sessionService,
+		OtpService:       otpService,
+		TwoFactorService: twoFactorService,
+		LoginGuard:       loginGuard,
+		Mailer:           mailer,
 	}
 }
 
@@ -53,13 +76,15 @@ func (s *AuthServiceImpl) FindByEmailOrUsername(email, username string) (*models
 	return user, nil
 }
 
-func (s *AuthServiceImpl) LoginUser(reqLogin dto.LoginRequest) (dto.LoginResponse, error) {
+func (s *AuthServiceImpl) LoginUser(reqLogin dto.LoginRequest, meta dto.SessionMeta) (dto.LoginResponse, error) {
 	/*
 		1. cari user ada nggak
-		2. kalau user ga ada maka throw username/password not valid
-		3. kalau ada maka cek passwordnya match apa nggak
-		4. kalau ga match maka throw username/password not valid
-		5. generate jwt by userid dan buat expired 7 hari tuk refresh token dan 1 hari tuk access token
+		2. cek counter login gagal akun & IP, tolak kalau masih backoff/lockout
+		3. kalau user ga ada maka throw username/password not valid
+		4. kalau ada maka cek passwordnya match apa nggak
+		5. kalau ga match maka catat kegagalan lalu throw username/password not valid
+		5. kalau user pakai 2FA (atau wajib 2FA) kembalikan token mfa_pending dulu
+		6. buat session baru, access token memuat id session dan refresh token disimpan ter-hash
 		6.
 
 	*/
@@ -67,204 +92,360 @@ func (s *AuthServiceImpl) LoginUser(reqLogin dto.LoginRequest) (dto.LoginRespons
 	var responseLogin dto.LoginResponse
 
 	user, err := s.UserRepo.FindByIdentifier(reqLogin.Identifier)
+	if err != nil && !isNotFoundErr(err) {
+		return responseLogin, err
+	}
 
-	if err != nil {
+	if err := s.LoginGuard.Check(user, reqLogin.Identifier, meta); err != nil {
 		return responseLogin, err
 	}
 
-	if user.AuthProvider == "google" {
-		return responseLogin, exception.NewBadRequestErr("please login with Google")
+	if user == nil {
+		s.LoginGuard.RegisterFailure(nil, reqLogin.Identifier, meta, "unknown identifier")
+		return responseLogin, exception.NewUnAuthorizationErr("invalid username/email or password")
+	}
+
+	if user.Password == "" {
+		// akun dari login provider (Google, GitHub, dll) yang belum pernah set password
+		return responseLogin, exception.NewBadRequestErr("this account has no password, please login with your linked provider")
 	}
 
 	if err := utils.ComparePassword(reqLogin.Password, user.Password); err != nil {
-		return responseLogin, err
+		s.LoginGuard.RegisterFailure(user, reqLogin.Identifier, meta, "wrong password")
+		return responseLogin, exception.NewUnAuthorizationErr("invalid username/email or password")
+	}
+
+	s.LoginGuard.RegisterSuccess(user)
+
+	if isUnverified(user) {
+		return responseLogin, exception.NewForbiddenErr("email is not verified, please verify your email first")
+	}
+
+	if enum.UserStatus(user.Status) != enum.UserStatusActive {
+		return responseLogin, exception.NewForbiddenErr("user is not active")
+	}
+
+	return s.CompleteLogin(user, meta)
+
+}
+
+func (s *AuthServiceImpl) RegisterUser(reqRegister dto.RegisterRequest) error {
+	/*
+		1. cari user by identifier ada atau tidak
+		2. kalau ada maka throw
+		3. kalau ga ada maka lanjut tuk register
+		4. password di hash jangan lupa
+		5. user dibuat inactive dan OTP dikirim ke email, user aktif setelah verifikasi
+	*/
+	if isReservedUsername(reqRegister.Username) {
+		return exception.NewBadRequestErr("username is not available")
 	}
 
-	jwtService := utils.GetJwtService()
+	_, err := s.FindByEmailOrUsername(reqRegister.Email, reqRegister.Username)
 
-	token, err := jwtService.CreateAccessToken(int(user.ID), user.Role)
+	if err == nil {
+		return exception.NewBadRequestErr("Username/Password is invalid")
+	}
 
+	passwordHash, err := utils.HashPassword(reqRegister.Password)
 	if err != nil {
-		return responseLogin, err
+		return err
+	}
+
+	modelUser := models.User{
+		Name:     displayName(reqRegister.Name, reqRegister.Username),
+		Username: reqRegister.Username,
+		Email:    reqRegister.Email,
+		Password: passwordHash,
+		Status:   int(enum.UserStatusInactive), // aktif setelah email diverifikasi
+		Role:     int(enum.RoleReader),
 	}
 
-	responseLogin.AccessToken = token
-	responseLogin.TypeToken = "access_token"
+	err = s.UserRepo.CreateUser(&modelUser)
 
-	return responseLogin, nil
+	if err != nil {
+		return err
+	}
 
+	return s.sendVerificationOtp(&modelUser)
 }
 
-// Get Google OAuth URL
-func (s *AuthServiceImpl) GetGoogleAuthURL(state string) string {
-	if state == "" {
-		state = utils.GenerateRandomString(32)
+func (s *AuthServiceImpl) ConfirmOtp(req dto.VerifyOtpRequest) error {
+	user, err := s.UserRepo.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return exception.NewBadRequestErr("invalid or expired code")
+		}
+		return exception.NewGormDBErr(err)
 	}
 
-	return utils.GoogleOAuthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	if user.EmailVerifiedAt != nil {
+		return exception.NewBusnissLogicErr("email is already verified")
+	}
+
+	if err := s.OtpService.Verify(user.ID, enum.OtpPurposeEmailVerification, req.Code); err != nil {
+		return err
+	}
+
+	return s.UserRepo.MarkEmailVerified(user.ID)
 }
 
-// Handle Google Callback - IMPROVED
-func (s *AuthServiceImpl) HandleGoogleCallback(code string) (dto.LoginResponse, error) {
-	var responseLogin dto.LoginResponse
+// ResendOtp tidak memberi tahu apakah email terdaftar, supaya endpoint ini tidak bisa dipakai untuk cek akun
+func (s *AuthServiceImpl) ResendOtp(req dto.ResendOtpRequest) error {
+	user, err := s.UserRepo.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return exception.NewGormDBErr(err)
+	}
+
+	if !isUnverified(user) {
+		return nil
+	}
+
+	return s.sendVerificationOtp(user)
+}
+
+func (s *AuthServiceImpl) sendVerificationOtp(user *models.User) error {
+	code, err := s.OtpService.Issue(user.ID, enum.OtpPurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+
+	sendMailAsync(s.Mailer, user.Email, "Verify your email",
+		"Hi "+user.Name+",\n\nYour verification code is "+code+
+			".\n\nThe code is valid for "+config.AppConfig.Otp.GetTTL().String()+". Ignore this email if you did not create an account.")
+
+	return nil
+}
 
-	// 1. Exchange authorization code dengan access token
-	token, err := utils.GoogleOAuthConfig.Exchange(context.Background(), code)
+// ForgotPassword mengirim link reset, response selalu sama supaya tidak bisa dipakai untuk cek email terdaftar
+func (s *AuthServiceImpl) ForgotPassword(req dto.ForgotPasswordRequest) error {
+	user, err := s.UserRepo.FindByEmail(req.Email)
 	if err != nil {
-		return responseLogin, errors.New("failed to exchange token")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return exception.NewGormDBErr(err)
 	}
 
-	// 2. Ambil user info dari Google
-	googleUser, err := s.getGoogleUserInfo(token)
+	if enum.UserStatus(user.Status) == enum.UserStatusBanned {
+		return nil
+	}
+
+	token := utils.CreateSignedToken(passwordResetTokenPurpose, passwordResetSubject(user), passwordResetTokenTTL)
+	link := config.AppConfig.AppMain.GetPasswordResetUrl() + "?token=" + url.QueryEscape(token)
+
+	sendMailAsync(s.Mailer, user.Email, "Reset your password",
+		"Hi "+user.Name+",\n\nOpen this link to set a new password:\n"+link+
+			"\n\nThe link is valid for "+passwordResetTokenTTL.String()+" and can only be used once. Ignore this email if you did not request a password reset.")
+
+	return nil
+}
+
+func (s *AuthServiceImpl) ResetPassword(req dto.ResetPasswordRequest) error {
+	subject, err := utils.VerifySignedToken(passwordResetTokenPurpose, req.Token)
 	if err != nil {
-		return responseLogin, err
+		return exception.NewBadRequestErr("invalid or expired reset token")
 	}
 
-	// 3. Cari atau buat user di database
-	user, err := s.findOrCreateGoogleUser(googleUser)
+	userIdPart, _, _ := strings.Cut(subject, ":")
+	userId, err := strconv.Atoi(userIdPart)
 	if err != nil {
-		return responseLogin, err
+		return exception.NewBadRequestErr("invalid or expired reset token")
 	}
 
-	// 4. Generate JWT token
-	jwtService := utils.GetJwtService()
-	accessToken, err := jwtService.CreateAccessToken(int(user.ID), user.Role)
+	user, err := s.UserRepo.FindById(userId)
 	if err != nil {
-		return responseLogin, exception.NewBadRequestErr("failed to generate token")
+		return exception.NewBadRequestErr("invalid or expired reset token")
+	}
+
+	// subject memuat sidik jari password lama, jadi token otomatis tidak berlaku setelah password diganti
+	if subject != passwordResetSubject(user) {
+		return exception.NewBadRequestErr("invalid or expired reset token")
 	}
 
-	responseLogin.AccessToken = accessToken
-	responseLogin.TypeToken = "access_token"
+	if err := s.updatePassword(user, req.NewPassword); err != nil {
+		return err
+	}
 
-	return responseLogin, nil
+	// pemilik email sudah terbukti, lockout akun tidak perlu ditunggu
+	s.LoginGuard.RegisterSuccess(user)
+
+	// link ke login provider ditolak selama email belum terverifikasi, reset password membuka jalannya
+	if user.EmailVerifiedAt == nil {
+		if err := s.UserRepo.MarkEmailVerified(user.ID); err != nil {
+			return err
+		}
+	}
+
+	// reset lewat email dianggap akun mungkin bocor, semua session dicabut
+	return s.SessionService.RevokeAllSessions(user.ID, SessionRevokedPassword)
 }
-func (s *AuthServiceImpl) getGoogleUserInfo(token *oauth2.Token) (*dto.GoogleUserInfo, error) {
-	client := utils.GoogleOAuthConfig.Client(context.Background(), token)
 
-	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+func (s *AuthServiceImpl) ChangePassword(req dto.ChangePasswordRequest, claims utils.Claims) error {
+	user, err := s.UserRepo.FindById(claims.UserId)
 	if err != nil {
-		return nil, errors.New("failed to get user info from google")
+		return err
+	}
+
+	// akun login provider tanpa password boleh langsung set password pertama
+	if user.Password != "" {
+		if req.CurrentPassword == "" {
+			return exception.NewBadRequestErr("current password is required")
+		}
+		if err := utils.ComparePassword(req.CurrentPassword, user.Password); err != nil {
+			return exception.NewBadRequestErr("current password is invalid")
+		}
+	}
+
+	if err := s.updatePassword(user, req.NewPassword); err != nil {
+		return err
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
+	return s.SessionService.RevokeOtherSessions(claims, SessionRevokedPassword)
+}
+
+func (s *AuthServiceImpl) updatePassword(user *models.User, newPassword string) error {
+	passwordHash, err := utils.HashPassword(newPassword)
 	if err != nil {
-		return nil, errors.New("failed to read user info")
+		return err
 	}
 
-	var googleUser dto.GoogleUserInfo
-	if err := json.Unmarshal(data, &googleUser); err != nil {
-		return nil, errors.New("failed to parse user info")
+	updated, err := s.UserRepo.UpdatePassword(user.ID, user.Password, passwordHash)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return exception.NewBusnissLogicErr("password has been changed by another request, please try again")
 	}
 
-	return &googleUser, nil
+	return nil
 }
 
-func (s *AuthServiceImpl) findOrCreateGoogleUser(googleUser *dto.GoogleUserInfo) (*models.User, error) {
-	/*
-	   flow:
-	   1. Cek by username (Google ID) → kalau ada, berarti user Google yang sama
-	   2. Kalau tidak ada, cek by email → mungkin user sudah register via form biasa
-	   3. Kalau email ada tapi auth_provider = 'local' → link account
-	   4. Kalau benar-benar baru → create user baru
-	*/
+func passwordResetSubject(user *models.User) string {
+	return strconv.FormatInt(user.ID, 10) + ":" + utils.HashToken(user.Password)[:16]
+}
 
-	// 1. Cek apakah Google ID (di column username) sudah ada
-	userByUsername, err := s.UserRepo.FindByUsername(googleUser.ID)
-	if err == nil {
-		userByUsername.Name = googleUser.Name
-		userByUsername.ProfileImageURI = &googleUser.Picture
-		userByUsername.Email = googleUser.Email // update email juga kalau berubah
-		userByUsername.Status = 1
+// CompleteLogin langkah terakhir login: langsung buat session, atau minta kode 2FA/enrolment dulu
+func (s *AuthServiceImpl) CompleteLogin(user *models.User, meta dto.SessionMeta) (dto.LoginResponse, error) {
+	var responseLogin dto.LoginResponse
 
-		if updateErr := s.UserRepo.Update(userByUsername); updateErr != nil {
-			return nil, errors.New("failed to update user")
-		}
+	enabled, err := s.TwoFactorService.IsEnabled(user.ID)
+	if err != nil {
+		return responseLogin, err
+	}
 
-		return userByUsername, nil
+	if !enabled && !s.TwoFactorService.IsRequired(user.Role) {
+		return s.SessionService.CreateSession(user, meta)
 	}
-	if err != gorm.ErrRecordNotFound {
-		return nil, exception.NewGormDBErr(err)
+
+	mfaToken, err := utils.GetJwtService().CreateMfaPendingToken(int(user.ID), user.Role)
+	if err != nil {
+		return responseLogin, err
 	}
 
-	userByEmail, err := s.UserRepo.FindByEmail(googleUser.Email)
-	if err == nil {
-		if userByEmail.AuthProvider == "google" {
-			// Aneh, harusnya ketemu di step 1. Mungkin data corrupt
-			return nil, exception.NewBadRequestErr("inconsistent data: email exists but google id doesn't match")
-		}
+	responseLogin.MfaRequired = true
+	responseLogin.MfaSetupRequired = !enabled
+	responseLogin.MfaToken = mfaToken
 
-		userByEmail.Username = googleUser.ID
-		userByEmail.AuthProvider = "google"
-		userByEmail.Name = googleUser.Name
-		userByEmail.ProfileImageURI = &googleUser.Picture
-		userByUsername.Status = 1
+	return responseLogin, nil
+}
 
-		if updateErr := s.UserRepo.Update(userByEmail); updateErr != nil {
-			return nil, exception.NewBadRequestErr("failed to link google account")
-		}
+func (s *AuthServiceImpl) VerifyTwoFactorLogin(req dto.TwoFactorVerifyRequest, meta dto.SessionMeta) (dto.LoginResponse, error) {
+	var responseLogin dto.LoginResponse
+
+	user, mfaClaims, err := s.findMfaPendingUser(req.MfaToken)
+	if err != nil {
+		return responseLogin, err
+	}
 
-		return userByEmail, nil
+	if err := s.LoginGuard.CheckTwoFactor(user, mfaClaims.ID, meta); err != nil {
+		return responseLogin, err
 	}
 
-	if err != gorm.ErrRecordNotFound {
-		return nil, exception.NewGormDBErr(err)
+	if err := s.TwoFactorService.Verify(user.ID, req.Code, req.RecoveryCode); err != nil {
+		s.registerTwoFactorFailure(user, mfaClaims, meta, err)
+		return responseLogin, err
 	}
 
-	newUser := &models.User{
-		Email:           googleUser.Email,
-		Username:        googleUser.ID, // Google ID disimpan di username
-		Name:            googleUser.Name,
-		ProfileImageURI: &googleUser.Picture,
-		AuthProvider:    "google",
-		Role:            int(enum.RoleReader),
-		Status:          1,
-		Password:        "", // no password untuk Google OAuth
+	s.LoginGuard.RegisterTwoFactorSuccess(user)
+
+	return s.SessionService.CreateSession(user, meta)
+}
+
+// SetupPendingTwoFactor enrolment saat login untuk user yang wajib 2FA tapi belum punya
+func (s *AuthServiceImpl) SetupPendingTwoFactor(req dto.TwoFactorPendingRequest, meta dto.SessionMeta) (*dto.TwoFactorSetupResponse, error) {
+	user, mfaClaims, err := s.findMfaPendingUser(req.MfaToken)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.UserRepo.Create(newUser); err != nil {
-		return nil, errors.New("failed to create user")
+	// token yang sudah dikunci karena kode salah tidak boleh dipakai membuat secret baru
+	if err := s.LoginGuard.CheckTwoFactor(user, mfaClaims.ID, meta); err != nil {
+		return nil, err
 	}
 
-	return newUser, nil
+	return s.TwoFactorService.Setup(user.ID)
 }
 
-func (s *AuthServiceImpl) RegisterUser(reqRegister dto.RegisterRequest) error {
-	/*
-		1. cari user by identifier ada atau tidak
-		2. kalau ada maka throw
-		3. kalau ga ada maka lanjut tuk register
-		4. password di hash jangan lupa
-	*/
-	_, err := s.FindByEmailOrUsername(reqRegister.Email, reqRegister.Username)
+func (s *AuthServiceImpl) EnablePendingTwoFactor(req dto.TwoFactorPendingEnableRequest, meta dto.SessionMeta) (*dto.TwoFactorEnableResponse, error) {
+	user, mfaClaims, err := s.findMfaPendingUser(req.MfaToken)
+	if err != nil {
+		return nil, err
+	}
 
-	if err == nil {
-		return exception.NewBadRequestErr("Username/Password is invalid")
+	if err := s.LoginGuard.CheckTwoFactor(user, mfaClaims.ID, meta); err != nil {
+		return nil, err
 	}
 
-	passwordHash, err := utils.HashPassword(reqRegister.Password)
+	recoveryCodes, err := s.TwoFactorService.Enable(user.ID, req.Code)
 	if err != nil {
-		return err
+		s.registerTwoFactorFailure(user, mfaClaims, meta, err)
+		return nil, err
 	}
 
-	modelUser := models.User{
-		Name:     reqRegister.Email,
-		Username: reqRegister.Username,
-		Email:    reqRegister.Email,
-		Password: passwordHash,
-		Status:   1, // 1 == aktif,
-		Role:     int(enum.RoleReader),
+	s.LoginGuard.RegisterTwoFactorSuccess(user)
+
+	responseLogin, err := s.SessionService.CreateSession(user, meta)
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.UserRepo.CreateUser(&modelUser)
+	return &dto.TwoFactorEnableResponse{
+		RecoveryCodes: recoveryCodes,
+		Login:         &responseLogin,
+	}, nil
+}
+
+func (s *AuthServiceImpl) findMfaPendingUser(mfaToken string) (*models.User, *utils.Claims, error) {
+	claims, err := utils.GetJwtService().VerifyToken(mfaToken)
+	if err != nil || claims.TokenType != utils.MfaPendingTokenType || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, nil, exception.NewUnAuthorizationErr("invalid or expired mfa token")
+	}
 
+	user, err := s.UserRepo.FindById(claims.UserId)
 	if err != nil {
-		return err
+		return nil, nil, exception.NewUnAuthorizationErr("invalid or expired mfa token")
+	}
+	if enum.UserStatus(user.Status) != enum.UserStatusActive {
+		return nil, nil, exception.NewForbiddenErr("user is not active")
 	}
 
-	return nil
+	return user, claims, nil
+}
+
+// registerTwoFactorFailure hanya kode yang salah/sudah dipakai yang dihitung, error lain (db, belum setup) tidak
+func (s *AuthServiceImpl) registerTwoFactorFailure(user *models.User, mfaClaims *utils.Claims, meta dto.SessionMeta, err error) {
+	if !isUnauthorizedErr(err) {
+		return
+	}
+
+	s.LoginGuard.RegisterTwoFactorFailure(user, mfaClaims.ID, mfaClaims.ExpiresAt.Time, meta, err.Error())
 }
 
-func (s *AuthServiceImpl) ConfirmOtp() error {
-	panic("not implemented") // TODO: Implement
+// isUnverified user register biasa yang belum memasukkan OTP, user lama sebelum ada verifikasi tetap dianggap terverifikasi
+func isUnverified(user *models.User) bool {
+	return user.EmailVerifiedAt == nil && enum.UserStatus(user.Status) == enum.UserStatusInactive
 }
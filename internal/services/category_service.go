@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
 	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
 	"gorm.io/gorm"
 )
 
@@ -17,18 +19,29 @@ type CategoryService interface {
 	FindById(id int) (*dto.CategoryResponse, error)
 	CreateCategory(body dto.CategoryRequst) error
 	UpdateCategory(id int, body dto.CategoryRequst) error
-	DeleteById(id int) error
+	DeleteById(id int, mode enum.CategoryDeleteMode) error
+	FindTree() ([]*dto.CategoryTreeResponse, error)
+	MoveCategory(id int, body dto.MoveCategoryRequest) error
+	FollowCategory(id int, user utils.Claims) error
+	UnfollowCategory(id int, user utils.Claims) error
+	FindFollowedCategories(user utils.Claims) ([]dto.FollowedCategoryResponse, error)
 }
 
 type CategoryServiceImpl struct {
-	DB                 *gorm.DB
-	CategoryRepository repository.CategoryRepository
+	DB                         *gorm.DB
+	CategoryRepository         repository.CategoryRepository
+	CategoryFollowerRepository repository.CategoryFollowerRepository
+	SearchService              SearchService
+	FeedService                FeedService
 }
 
-func NewCategoryService(categoryRepository repository.CategoryRepository, DB *gorm.DB) CategoryService {
+func NewCategoryService(categoryRepository repository.CategoryRepository, categoryFollowerRepository repository.CategoryFollowerRepository, searchService SearchService, feedService FeedService, DB *gorm.DB) CategoryService {
 	return &CategoryServiceImpl{
-		DB:                 DB,
-		CategoryRepository: categoryRepository,
+		DB:                         DB,
+		CategoryRepository:         categoryRepository,
+		CategoryFollowerRepository: categoryFollowerRepository,
+		SearchService:              searchService,
+		FeedService:                feedService,
 	}
 }
 
@@ -66,10 +79,15 @@ func (s *CategoryServiceImpl) FindById(id int) (*dto.CategoryResponse, error) {
 	}
 
 	categoryDto := dto.CategoryResponse{
-		Id:   int(category.ID),
-		Name: category.Name,
-		Slug: category.Slug,
-		Desc: category.Slug,
+		Id:                 int(category.ID),
+		Name:               category.Name,
+		Slug:               category.Slug,
+		FollowerCount:      category.FollowerCount,
+		PublishedPostCount: category.PublishedPostCount,
+	}
+
+	if category.Description != nil {
+		categoryDto.Desc = *category.Description
 	}
 
 	if category.ParentID != nil {
@@ -100,6 +118,9 @@ func (s *CategoryServiceImpl) CreateCategory(body dto.CategoryRequst) error {
 		Slug:        slugCategory,
 	}
 	if body.ParentId != 0 {
+		if _, err := s.CategoryRepository.FindById(body.ParentId); err != nil {
+			return exception.NewBadRequestErr("parent category not found")
+		}
 		var parentId int64 = int64(body.ParentId)
 		category.ParentID = &parentId
 	}
@@ -128,15 +149,16 @@ func (s *CategoryServiceImpl) UpdateCategory(id int, body dto.CategoryRequst) er
 
 	slugCategory := fmt.Sprintf("%s_%d", slugBase, time.Now().UnixMilli())
 
-	var parentID *int
-	if body.ParentId != 0 {
-		parentID = &body.ParentId
+	// parent diubah lewat SetParent supaya dicek tidak membentuk siklus
+	if body.ParentId != findCategoryById.ParentId {
+		if err := s.MoveCategory(id, dto.MoveCategoryRequest{ParentId: toParentId(body.ParentId)}); err != nil {
+			return err
+		}
 	}
 
 	categoryUpdate := map[string]interface{}{
 		"Name":        body.Name,
 		"Description": body.Desc,
-		"ParentID":    parentID,
 		"Slug":        slugCategory,
 	}
 
@@ -148,17 +170,148 @@ func (s *CategoryServiceImpl) UpdateCategory(id int, body dto.CategoryRequst) er
 	return nil
 }
 
-func (s *CategoryServiceImpl) DeleteById(id int) error {
-	_, err := s.FindById(id)
+func (s *CategoryServiceImpl) DeleteById(id int, mode enum.CategoryDeleteMode) error {
+	if mode == "" {
+		mode = enum.CategoryDeleteReparent
+	}
+	if !enum.IsValidCategoryDeleteMode(mode) {
+		return exception.NewBadRequestErr("mode must be reparent or cascade")
+	}
+
+	postIds, err := s.CategoryRepository.DeleteWithMode(int64(id), mode)
+	if err != nil {
+		return err
+	}
+
+	// kategori post berubah, dokumen pencarian ikut diperbarui
+	for _, postId := range postIds {
+		s.SearchService.SyncPost(postId)
+	}
+
+	return nil
+}
 
+func (s *CategoryServiceImpl) FindTree() ([]*dto.CategoryTreeResponse, error) {
+	categories, err := s.CategoryRepository.FindAllForTree()
 	if err != nil {
+		return nil, err
+	}
+
+	return buildCategoryTree(categories), nil
+}
+
+func (s *CategoryServiceImpl) MoveCategory(id int, body dto.MoveCategoryRequest) error {
+	var parentId *int64
+	if body.ParentId != nil && *body.ParentId != 0 {
+		parentId = body.ParentId
+	}
+
+	return s.CategoryRepository.SetParent(int64(id), parentId)
+}
+
+func (s *CategoryServiceImpl) FollowCategory(id int, user utils.Claims) error {
+	if _, err := s.CategoryRepository.FindById(id); err != nil {
 		return exception.NewNotFoundErr("category not found")
 	}
 
-	err = s.CategoryRepository.DeleteById(id)
+	created, err := s.CategoryFollowerRepository.Follow(int64(user.UserId), int64(id))
+	if err != nil {
+		return err
+	}
+	if !created {
+		return exception.NewBadRequestErr("Already following this category")
+	}
+
+	// inbox feed hanya pelengkap, follow tetap berhasil walau backfill gagal
+	if err := s.FeedService.OnFollowCategory(int64(user.UserId), int64(id)); err != nil {
+		utils.Logger.WithError(err).Warn("failed to backfill feed for user ", user.UserId)
+	}
 
+	return nil
+}
+
+func (s *CategoryServiceImpl) UnfollowCategory(id int, user utils.Claims) error {
+	deleted, err := s.CategoryFollowerRepository.Unfollow(int64(user.UserId), int64(id))
 	if err != nil {
 		return err
 	}
+	if !deleted {
+		return exception.NewNotFoundErr("Not following this category")
+	}
+
+	if err := s.FeedService.OnUnfollowCategory(int64(user.UserId), int64(id)); err != nil {
+		utils.Logger.WithError(err).Warn("failed to clean feed for user ", user.UserId)
+	}
+
 	return nil
 }
+
+func (s *CategoryServiceImpl) FindFollowedCategories(user utils.Claims) ([]dto.FollowedCategoryResponse, error) {
+	return s.CategoryFollowerRepository.FindAllByUserId(int64(user.UserId))
+}
+
+// buildCategoryTree kategori dengan parent yang tidak ada dianggap root, node yang terjebak siklus lama ditempel di root
+func buildCategoryTree(categories []models.Category) []*dto.CategoryTreeResponse {
+	nodes := make(map[int64]*dto.CategoryTreeResponse, len(categories))
+	for _, category := range categories {
+		node := &dto.CategoryTreeResponse{
+			Id:                 int(category.ID),
+			Name:               category.Name,
+			Slug:               category.Slug,
+			FollowerCount:      category.FollowerCount,
+			PublishedPostCount: category.PublishedPostCount,
+			Children:           make([]*dto.CategoryTreeResponse, 0),
+		}
+		if category.Description != nil {
+			node.Desc = *category.Description
+		}
+		if category.ParentID != nil {
+			node.ParentId = int(*category.ParentID)
+		}
+		nodes[category.ID] = node
+	}
+
+	roots := make([]*dto.CategoryTreeResponse, 0)
+	children := make(map[int64][]*dto.CategoryTreeResponse, len(categories))
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil || nodes[*category.ParentID] == nil {
+			roots = append(roots, node)
+			continue
+		}
+		children[*category.ParentID] = append(children[*category.ParentID], node)
+	}
+
+	visited := make(map[int]bool, len(categories))
+	var attach func(node *dto.CategoryTreeResponse)
+	attach = func(node *dto.CategoryTreeResponse) {
+		visited[node.Id] = true
+		for _, child := range children[int64(node.Id)] {
+			if visited[child.Id] {
+				continue
+			}
+			node.Children = append(node.Children, child)
+			attach(child)
+		}
+	}
+
+	for _, root := range roots {
+		attach(root)
+	}
+	for _, category := range categories {
+		if node := nodes[category.ID]; !visited[node.Id] {
+			roots = append(roots, node)
+			attach(node)
+		}
+	}
+
+	return roots
+}
+
+func toParentId(parentId int) *int64 {
+	if parentId == 0 {
+		return nil
+	}
+	id := int64(parentId)
+	return &id
+}
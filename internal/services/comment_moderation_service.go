@@ -0,0 +1,99 @@
+package services
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type CommentModerationAction string
+
+const (
+	CommentModerationApprove CommentModerationAction = "approve"
+	CommentModerationReject  CommentModerationAction = "reject"
+)
+
+type CommentModerationService interface {
+	FindQueue(filter dto.CommentModerationFilterRequest) (*dto.PaginationResult, error)
+	Moderate(commentId int64, action CommentModerationAction, user utils.Claims) error
+	BulkModerate(body dto.CommentModerationBulkRequest, user utils.Claims) (*dto.CommentModerationBulkResponse, error)
+}
+
+type CommentModerationServiceImpl struct {
+	CommentRepository repository.CommentRepository
+}
+
+func NewCommentModerationService(commentRepository repository.CommentRepository) CommentModerationService {
+	return &CommentModerationServiceImpl{
+		CommentRepository: commentRepository,
+	}
+}
+
+func (s *CommentModerationServiceImpl) FindQueue(filter dto.CommentModerationFilterRequest) (*dto.PaginationResult, error) {
+	status := enum.CommentStatus(filter.Status)
+	if status != enum.CommentStatusInactive && status != enum.CommentStatusRejected {
+		return nil, exception.NewBadRequestErr("status must be 0 (held) or 3 (rejected)")
+	}
+
+	return s.CommentRepository.FindModerationQueue(filter)
+}
+
+func (s *CommentModerationServiceImpl) Moderate(commentId int64, action CommentModerationAction, user utils.Claims) error {
+	updated, err := s.moderate([]int64{commentId}, action, user)
+	if err != nil {
+		return err
+	}
+
+	if updated == 0 {
+		return exception.NewNotFoundErr("comment not found in moderation queue")
+	}
+
+	return nil
+}
+
+func (s *CommentModerationServiceImpl) BulkModerate(body dto.CommentModerationBulkRequest, user utils.Claims) (*dto.CommentModerationBulkResponse, error) {
+	ids := make([]int64, 0, len(body.IDs))
+	seen := make(map[int64]struct{}, len(body.IDs))
+	for _, id := range body.IDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+
+	updated, err := s.moderate(ids, CommentModerationAction(body.Action), user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.CommentModerationBulkResponse{
+		Processed: updated,
+		Skipped:   int64(len(ids)) - updated,
+	}, nil
+}
+
+// moderate hanya memproses komentar yang masih ditahan, komentar lain dihitung skipped
+func (s *CommentModerationServiceImpl) moderate(ids []int64, action CommentModerationAction, user utils.Claims) (int64, error) {
+	var toStatus enum.CommentStatus
+	switch action {
+	case CommentModerationApprove:
+		toStatus = enum.CommentStatusActive
+	case CommentModerationReject:
+		toStatus = enum.CommentStatusRejected
+	default:
+		return 0, exception.NewBadRequestErr("unknown moderation action")
+	}
+
+	data := map[string]interface{}{
+		"status":       toStatus,
+		"moderated_by": user.UserId,
+		"moderated_at": time.Now(),
+	}
+
+	return s.CommentRepository.UpdateStatusByIds(ids, enum.CommentStatusInactive, data)
+}
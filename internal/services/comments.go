@@ -1,29 +1,69 @@
 package services
 
 import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/policy"
 	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/spam"
 	"github.com/MrBista/blog-api/internal/utils"
 	"gorm.io/gorm"
 )
 
+const (
+	defaultCommentTreeDepth  = 3
+	guestCommentTokenPurpose = "guest_comment_confirm"
+	guestCommentTokenTTL     = 48 * time.Hour
+)
+
 type CommentService interface {
 	FindAllCommentByPostId(filter dto.CommentFilterRequest, userDetail utils.Claims) (*dto.PaginationResult, error)
 	CreateComment(commentBody dto.CommentRequest, userDetail utils.Claims) (*models.Comment, error)
+	FindCommentTree(filter dto.CommentTreeFilterRequest, userDetail utils.Claims) (*dto.PaginationResult, error)
+	FindReplies(filter dto.CommentRepliesFilterRequest, userDetail utils.Claims) (*dto.CommentRepliesResponse, error)
+	UpdateComment(postId int64, commentId int64, body dto.UpdateCommentRequest, userDetail utils.Claims) (*models.Comment, error)
+	DeleteComment(postId int64, commentId int64, userDetail utils.Claims) error
+	CreateGuestComment(body dto.GuestCommentRequest) (*models.Comment, error)
+	ConfirmGuestComment(token string) (*models.Comment, error)
 }
 
 type CommentServiceImpl struct {
 	DB                *gorm.DB
 	CommentRepository repository.CommentRepository
+	// UserBlockRepository dipakai untuk menolak komentar dari user yang diblokir penulis post
+	UserBlockRepository repository.UserBlockRepository
+	Config              *config.CommentConfig
+	SpamChecker         spam.SpamChecker
+	Mailer              Mailer
+	BaseUrl             string
 }
 
-func NewCommentService(commentRepository repository.CommentRepository, db *gorm.DB) CommentService {
+func NewCommentService(
+	commentRepository repository.CommentRepository,
+	userBlockRepository repository.UserBlockRepository,
+	db *gorm.DB,
+	commentConfig *config.CommentConfig,
+	spamChecker spam.SpamChecker,
+	mailer Mailer,
+	baseUrl string,
+) CommentService {
 
 	return &CommentServiceImpl{
-		DB:                db,
-		CommentRepository: commentRepository,
+		DB:                  db,
+		CommentRepository:   commentRepository,
+		UserBlockRepository: userBlockRepository,
+		Config:              commentConfig,
+		SpamChecker:         spamChecker,
+		Mailer:              mailer,
+		BaseUrl:             strings.TrimRight(baseUrl, "/"),
 	}
 }
 
@@ -32,6 +72,8 @@ func (s *CommentServiceImpl) FindAllCommentByPostId(filter dto.CommentFilterRequ
 		return nil, err
 	}
 
+	filter.ViewerID = userDetail.UserId
+
 	datas, err := s.CommentRepository.FindAllCommentByPostId(filter)
 
 	if err != nil {
@@ -55,13 +97,33 @@ func (s *CommentServiceImpl) CreateComment(commentBody dto.CommentRequest, userD
 
 	// cari dulu ada ga post yang mau di comment
 	// kalau ga ada maka throw
-	if _, err := s.FindDetailPostByPostId(commentBody.PostId); err != nil {
+	post, err := s.FindDetailPostByPostId(commentBody.PostId)
+	if err != nil {
 		return nil, err
 	}
 
 	convertUserId := int64(userDetail.UserId)
 	convertParentId := int64(commentBody.ParentId)
 
+	if err := s.ensureNotBlocked(post.AuthorID, convertUserId); err != nil {
+		return nil, err
+	}
+
+	if convertParentId != 0 {
+		parent, err := s.CommentRepository.FindById(int64(commentBody.PostId), convertParentId)
+		if err != nil {
+			return nil, err
+		}
+		if enum.CommentStatus(parent.Status) != enum.CommentStatusActive {
+			return nil, exception.NewBusnissLogicErr("cannot reply to deleted comment")
+		}
+		if parent.UserID != nil {
+			if err := s.ensureNotBlocked(*parent.UserID, convertUserId); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	var comment models.Comment
 	comment.Content = commentBody.Content
 	comment.PostID = int64(commentBody.PostId)
@@ -70,6 +132,10 @@ func (s *CommentServiceImpl) CreateComment(commentBody dto.CommentRequest, userD
 	}
 	comment.UserID = &convertUserId
 
+	if err := s.applyModeration(&comment, userDetail); err != nil {
+		return nil, err
+	}
+
 	if err := s.CommentRepository.Create(&comment); err != nil {
 		return nil, err
 	}
@@ -77,3 +143,430 @@ func (s *CommentServiceImpl) CreateComment(commentBody dto.CommentRequest, userD
 	return &comment, nil
 
 }
+
+// ensureNotBlocked menolak komentar kalau ownerId (penulis post/komentar induk) memblokir user
+func (s *CommentServiceImpl) ensureNotBlocked(ownerId int64, userId int64) error {
+	if ownerId == userId {
+		return nil
+	}
+
+	blocked, err := s.UserBlockRepository.Exists(ownerId, userId, enum.UserBlockTypeBlock)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return exception.NewForbiddenErr("you cannot comment here")
+	}
+
+	return nil
+}
+
+// applyModeration menilai spam lalu menentukan komentar langsung tampil atau ditahan di antrian moderasi
+func (s *CommentServiceImpl) applyModeration(comment *models.Comment, userDetail utils.Claims) error {
+	if policy.Can(userDetail, enum.PermissionCommentModerate) {
+		comment.Status = int8(enum.CommentStatusActive)
+		return nil
+	}
+
+	if err := s.scoreComment(comment); err != nil {
+		return err
+	}
+
+	status, err := s.moderationStatus(comment)
+	if err != nil {
+		return err
+	}
+	comment.Status = int8(status)
+
+	return nil
+}
+
+func (s *CommentServiceImpl) scoreComment(comment *models.Comment) error {
+	result, err := s.SpamChecker.Check(spam.Input{
+		UserID:    comment.UserID,
+		Email:     comment.Email,
+		PostID:    comment.PostID,
+		Content:   comment.Content,
+		CommentID: comment.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	comment.SpamScore = result.Score
+	if len(result.Reasons) > 0 {
+		// kolom varchar(500) dihitung per karakter, potong per rune supaya UTF-8 tidak rusak
+		reasons := strings.Join(result.Reasons, "; ")
+		if runes := []rune(reasons); len(runes) > 500 {
+			reasons = string(runes[:500])
+		}
+		comment.SpamReasons = &reasons
+	}
+
+	return nil
+}
+
+// moderationStatus memakai skor spam yang sudah tersimpan, jadi bisa dipanggil ulang saat komentar tamu dikonfirmasi
+func (s *CommentServiceImpl) moderationStatus(comment *models.Comment) (enum.CommentStatus, error) {
+	if comment.SpamScore >= s.Config.GetSpamThreshold() {
+		return enum.CommentStatusInactive, nil
+	}
+
+	switch enum.ModerationMode(s.Config.GetModerationMode()) {
+	case enum.ModerationAll:
+		return enum.CommentStatusInactive, nil
+	case enum.ModerationFirstTime:
+		approved, err := s.CommentRepository.CountApprovedByAuthor(comment.UserID, comment.Email)
+		if err != nil {
+			return 0, err
+		}
+		if approved == 0 {
+			return enum.CommentStatusInactive, nil
+		}
+	}
+
+	return enum.CommentStatusActive, nil
+}
+
+func (s *CommentServiceImpl) CreateGuestComment(body dto.GuestCommentRequest) (*models.Comment, error) {
+	post, err := s.FindDetailPostByPostId(body.PostId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enum.PostStatus(post.Status) != enum.PostStatusPublished {
+		return nil, exception.NewBusnissLogicErr("post is not open for comments")
+	}
+
+	allowGuest := s.Config.AllowGuest
+	if post.AllowGuestComments != nil {
+		allowGuest = *post.AllowGuestComments
+	}
+	if !allowGuest {
+		return nil, exception.NewForbiddenErr("guest comments are not allowed on this post")
+	}
+
+	recent, err := s.CommentRepository.CountRecentGuestByIP(body.IPAddress, time.Now().Add(-s.Config.GetGuestRateWindow()))
+	if err != nil {
+		return nil, err
+	}
+	if recent >= int64(s.Config.GetGuestRateLimit()) {
+		return nil, exception.NewBusnissLogicErr("too many comments, please try again later")
+	}
+
+	parentId := int64(body.ParentId)
+	if parentId != 0 {
+		parent, err := s.CommentRepository.FindById(post.ID, parentId)
+		if err != nil {
+			return nil, err
+		}
+		if enum.CommentStatus(parent.Status) != enum.CommentStatusActive {
+			return nil, exception.NewBusnissLogicErr("cannot reply to deleted comment")
+		}
+	}
+
+	name := strings.TrimSpace(body.Name)
+	email := strings.ToLower(strings.TrimSpace(body.Email))
+	ip := body.IPAddress
+
+	comment := models.Comment{
+		PostID:    post.ID,
+		Name:      &name,
+		Email:     &email,
+		Content:   body.Content,
+		IPAddress: &ip,
+	}
+	if parentId != 0 {
+		comment.ParentID = &parentId
+	}
+
+	if err := s.scoreComment(&comment); err != nil {
+		return nil, err
+	}
+
+	// komentar tamu baru tampil (atau masuk antrian moderasi) setelah email dikonfirmasi
+	comment.Status = int8(enum.CommentStatusUnconfirmed)
+
+	if err := s.CommentRepository.Create(&comment); err != nil {
+		return nil, err
+	}
+
+	token := utils.CreateSignedToken(guestCommentTokenPurpose, strconv.FormatInt(comment.ID, 10), guestCommentTokenTTL)
+	link := s.BaseUrl + "/api/comments/confirm?token=" + url.QueryEscape(token)
+
+	sendMailAsync(s.Mailer, email, "Confirm your comment",
+		"Hi "+name+",\n\nPlease confirm your comment on \""+post.Title+"\" by opening this link:\n"+link+
+			"\n\nThe link is valid for "+guestCommentTokenTTL.String()+". Ignore this email if you did not write the comment.")
+
+	return &comment, nil
+}
+
+func (s *CommentServiceImpl) ConfirmGuestComment(token string) (*models.Comment, error) {
+	subject, err := utils.VerifySignedToken(guestCommentTokenPurpose, token)
+	if err != nil {
+		return nil, exception.NewBadRequestErr("invalid or expired confirmation link")
+	}
+
+	commentId, err := strconv.ParseInt(subject, 10, 64)
+	if err != nil {
+		return nil, exception.NewBadRequestErr("invalid or expired confirmation link")
+	}
+
+	comment, err := s.CommentRepository.GetCommentById(commentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enum.CommentStatus(comment.Status) != enum.CommentStatusUnconfirmed {
+		return nil, exception.NewBusnissLogicErr("comment already confirmed")
+	}
+
+	status, err := s.moderationStatus(comment)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.CommentRepository.UpdateStatusByIds([]int64{comment.ID}, enum.CommentStatusUnconfirmed, map[string]interface{}{
+		"status": status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, exception.NewBusnissLogicErr("comment already confirmed")
+	}
+
+	comment.Status = int8(status)
+
+	return comment, nil
+}
+
+func (s *CommentServiceImpl) FindCommentTree(filter dto.CommentTreeFilterRequest, userDetail utils.Claims) (*dto.PaginationResult, error) {
+	if _, err := s.FindDetailPostByPostId(filter.PostId); err != nil {
+		return nil, err
+	}
+
+	filter.ViewerID = userDetail.UserId
+	filter.Depth = s.normalizeDepth(filter.Depth)
+	filter.ReplyLimit = s.normalizeReplyLimit(filter.ReplyLimit)
+
+	comments, total, err := s.CommentRepository.FindTopLevelComments(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := toCommentNodes(comments)
+
+	if err := s.loadReplies(nodes, filter.Depth, filter.ReplyLimit, filter.ViewerID); err != nil {
+		return nil, err
+	}
+
+	return dto.NewPaginationResult(nodes, total, filter.Page, filter.PageSize, "comments"), nil
+}
+
+func (s *CommentServiceImpl) FindReplies(filter dto.CommentRepliesFilterRequest, userDetail utils.Claims) (*dto.CommentRepliesResponse, error) {
+	if _, err := s.CommentRepository.FindById(int64(filter.PostId), filter.ParentId); err != nil {
+		return nil, err
+	}
+
+	filter.ViewerID = userDetail.UserId
+	filter.Depth = s.normalizeDepth(filter.Depth)
+	filter.ReplyLimit = s.normalizeReplyLimit(filter.ReplyLimit)
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 10
+	}
+
+	// ambil satu lebih banyak untuk tahu masih ada halaman berikutnya atau tidak
+	replies, err := s.CommentRepository.FindRepliesAfter(filter.ParentId, filter.Cursor, filter.Limit+1, filter.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.CommentRepliesResponse{}
+	if len(replies) > filter.Limit {
+		replies = replies[:filter.Limit]
+		nextCursor := replies[len(replies)-1].ID
+		response.NextCursor = &nextCursor
+	}
+
+	response.Replies = toCommentNodes(replies)
+
+	if err := s.loadReplies(response.Replies, filter.Depth, filter.ReplyLimit, filter.ViewerID); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// loadReplies mengisi balasan secara bertingkat, depth adalah jumlah level termasuk level nodes saat ini
+func (s *CommentServiceImpl) loadReplies(nodes []*dto.CommentNodeResponse, depth int, replyLimit int, viewerId int) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	parentIds := make([]int64, 0, len(nodes))
+	for _, node := range nodes {
+		parentIds = append(parentIds, node.ID)
+	}
+
+	counts, err := s.CommentRepository.CountRepliesByParentIds(parentIds, viewerId)
+	if err != nil {
+		return err
+	}
+
+	withReplies := make([]int64, 0, len(nodes))
+	for _, node := range nodes {
+		node.ReplyCount = counts[node.ID]
+		if node.ReplyCount > 0 {
+			withReplies = append(withReplies, node.ID)
+		}
+	}
+
+	if depth <= 1 {
+		// batas kedalaman, client memuat balasan lewat cursor mulai dari 0
+		for _, node := range nodes {
+			if node.ReplyCount > 0 {
+				var cursor int64
+				node.NextCursor = &cursor
+			}
+		}
+		return nil
+	}
+
+	replies, err := s.CommentRepository.FindRepliesByParentIds(withReplies, replyLimit, viewerId)
+	if err != nil {
+		return err
+	}
+
+	children := toCommentNodes(replies)
+	byParent := make(map[int64][]*dto.CommentNodeResponse)
+	for _, child := range children {
+		if child.ParentID != nil {
+			byParent[*child.ParentID] = append(byParent[*child.ParentID], child)
+		}
+	}
+
+	for _, node := range nodes {
+		node.Replies = byParent[node.ID]
+		if node.Replies == nil {
+			node.Replies = []*dto.CommentNodeResponse{}
+		}
+		if int64(len(node.Replies)) < node.ReplyCount {
+			var cursor int64
+			if len(node.Replies) > 0 {
+				cursor = node.Replies[len(node.Replies)-1].ID
+			}
+			node.NextCursor = &cursor
+		}
+	}
+
+	return s.loadReplies(children, depth-1, replyLimit, viewerId)
+}
+
+func (s *CommentServiceImpl) normalizeDepth(depth int) int {
+	maxDepth := s.Config.GetMaxDepth()
+	if depth < 1 {
+		depth = defaultCommentTreeDepth
+	}
+	if depth > maxDepth {
+		depth = maxDepth
+	}
+	return depth
+}
+
+func (s *CommentServiceImpl) normalizeReplyLimit(limit int) int {
+	if limit < 1 {
+		return s.Config.GetReplyLimit()
+	}
+	if limit > 50 {
+		return 50
+	}
+	return limit
+}
+
+func (s *CommentServiceImpl) UpdateComment(postId int64, commentId int64, body dto.UpdateCommentRequest, userDetail utils.Claims) (*models.Comment, error) {
+	comment, err := s.CommentRepository.FindById(postId, commentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID == nil || *comment.UserID != int64(userDetail.UserId) {
+		return nil, exception.NewForbiddenErr("comment is not yours")
+	}
+
+	status := enum.CommentStatus(comment.Status)
+	if status == enum.CommentStatusDeleted || status == enum.CommentStatusRejected {
+		return nil, exception.NewBusnissLogicErr("deleted or rejected comment cannot be edited")
+	}
+
+	// isi baru dinilai ulang supaya komentar yang sudah disetujui tidak bisa diubah jadi spam,
+	// komentar yang sedang ditahan tetap ditahan walaupun isi barunya lolos
+	previousStatus := status
+	comment.Content = body.Content
+	comment.SpamReasons = nil
+	if err := s.applyModeration(comment, userDetail); err != nil {
+		return nil, err
+	}
+	if previousStatus == enum.CommentStatusInactive {
+		comment.Status = int8(enum.CommentStatusInactive)
+	}
+
+	now := time.Now()
+	dataToUpdate := map[string]interface{}{
+		"content":      body.Content,
+		"edited_at":    now,
+		"status":       comment.Status,
+		"spam_score":   comment.SpamScore,
+		"spam_reasons": comment.SpamReasons,
+	}
+
+	if err := s.CommentRepository.Update(comment.ID, dataToUpdate); err != nil {
+		return nil, err
+	}
+
+	comment.EditedAt = &now
+
+	return comment, nil
+}
+
+// DeleteComment hanya menandai status deleted (tombstone), baris tetap ada supaya balasan tidak yatim
+func (s *CommentServiceImpl) DeleteComment(postId int64, commentId int64, userDetail utils.Claims) error {
+	comment, err := s.CommentRepository.FindById(postId, commentId)
+	if err != nil {
+		return err
+	}
+
+	if enum.CommentStatus(comment.Status) == enum.CommentStatusDeleted {
+		return exception.NewNotFoundErr("comment not found")
+	}
+
+	var ownerId int64
+	if comment.UserID != nil {
+		ownerId = *comment.UserID
+	}
+	if !policy.CanActOn(userDetail, enum.PermissionCommentDeleteAny, ownerId) {
+		post, err := s.FindDetailPostByPostId(int(postId))
+		if err != nil {
+			return err
+		}
+		if post.AuthorID != int64(userDetail.UserId) {
+			return exception.NewForbiddenErr("comment is not yours")
+		}
+	}
+
+	return s.CommentRepository.Update(comment.ID, map[string]interface{}{
+		"status": enum.CommentStatusDeleted,
+	})
+}
+
+func toCommentNodes(comments []dto.CommentWithUserResponse) []*dto.CommentNodeResponse {
+	nodes := make([]*dto.CommentNodeResponse, 0, len(comments))
+	for _, comment := range comments {
+		comment.MaskDeleted()
+		nodes = append(nodes, &dto.CommentNodeResponse{
+			CommentWithUserResponse: comment,
+			Replies:                 []*dto.CommentNodeResponse{},
+		})
+	}
+	return nodes
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// jumlah post lama author yang langsung masuk inbox saat di-follow (strategy write)
+const feedBackfillLimit = 50
+
+type FeedService interface {
+	FindMyFeed(filter dto.FeedFilterRequest, user utils.Claims) (*dto.FeedResponse, error)
+	// hook On* hanya mengubah inbox di strategy write, strategy read langsung membaca followers/category_followers
+	OnFollow(userId int64, authorId int64) error
+	OnUnfollow(userId int64, authorId int64) error
+	OnFollowCategory(userId int64, categoryId int64) error
+	OnUnfollowCategory(userId int64, categoryId int64) error
+	FanoutPending() (int, error)
+	CleanupInbox() (int64, error)
+}
+
+type FeedServiceImpl struct {
+	FeedRepository repository.FeedRepository
+	Config         *config.FeedConfig
+}
+
+func NewFeedService(feedRepository repository.FeedRepository, cfg *config.FeedConfig) FeedService {
+	return &FeedServiceImpl{
+		FeedRepository: feedRepository,
+		Config:         cfg,
+	}
+}
+
+func (s *FeedServiceImpl) FindMyFeed(filter dto.FeedFilterRequest, user utils.Claims) (*dto.FeedResponse, error) {
+	filter.ViewerID = user.UserId
+	if filter.Limit < 1 {
+		filter.Limit = s.Config.GetPageSize()
+	}
+	if filter.Limit > s.Config.GetMaxPageSize() {
+		filter.Limit = s.Config.GetMaxPageSize()
+	}
+
+	if filter.Cursor != "" {
+		publishedAt, id, err := decodeFeedCursor(filter.Cursor)
+		if err != nil {
+			return nil, exception.NewBadRequestErr("invalid cursor")
+		}
+		filter.AfterPublishedAt = &publishedAt
+		filter.AfterID = id
+	}
+
+	// ambil satu lebih banyak untuk tahu masih ada halaman berikutnya atau tidak
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	var (
+		posts []dto.PostResponse
+		err   error
+	)
+	if s.Config.GetStrategy() == config.FeedStrategyWrite {
+		posts, err = s.FeedRepository.FindFromInbox(filter)
+	} else {
+		posts, err = s.FeedRepository.FindFromFollows(filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.FeedResponse{}
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		if last.PublishedAt != nil {
+			nextCursor := encodeFeedCursor(*last.PublishedAt, int64(last.ID))
+			response.NextCursor = &nextCursor
+		}
+	}
+	response.Posts = posts
+
+	return &response, nil
+}
+
+func (s *FeedServiceImpl) OnFollow(userId int64, authorId int64) error {
+	if s.Config.GetStrategy() != config.FeedStrategyWrite {
+		return nil
+	}
+
+	since := time.Now().Add(-s.Config.GetRetention())
+	return s.FeedRepository.BackfillAuthor(userId, authorId, since, feedBackfillLimit)
+}
+
+func (s *FeedServiceImpl) OnUnfollow(userId int64, authorId int64) error {
+	if s.Config.GetStrategy() != config.FeedStrategyWrite {
+		return nil
+	}
+
+	return s.FeedRepository.RemoveAuthor(userId, authorId)
+}
+
+func (s *FeedServiceImpl) OnFollowCategory(userId int64, categoryId int64) error {
+	if s.Config.GetStrategy() != config.FeedStrategyWrite {
+		return nil
+	}
+
+	since := time.Now().Add(-s.Config.GetRetention())
+	return s.FeedRepository.BackfillCategory(userId, categoryId, since, feedBackfillLimit)
+}
+
+func (s *FeedServiceImpl) OnUnfollowCategory(userId int64, categoryId int64) error {
+	if s.Config.GetStrategy() != config.FeedStrategyWrite {
+		return nil
+	}
+
+	return s.FeedRepository.RemoveCategory(userId, categoryId)
+}
+
+// FanoutPending menyebar post yang baru terbit ke inbox follower, post yang lebih tua dari retention dilewati
+func (s *FeedServiceImpl) FanoutPending() (int, error) {
+	now := time.Now()
+
+	posts, err := s.FeedRepository.FindPostsToFanout(now.Add(-s.Config.GetRetention()), s.Config.GetFanoutBatch())
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, post := range posts {
+		if err := s.FeedRepository.Fanout(post, now); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+func (s *FeedServiceImpl) CleanupInbox() (int64, error) {
+	return s.FeedRepository.DeleteOlderThan(time.Now().Add(-s.Config.GetRetention()))
+}
+
+// encodeFeedCursor cursor opaque berisi posisi (published_at, id) post terakhir di halaman
+func encodeFeedCursor(publishedAt time.Time, id int64) string {
+	raw := fmt.Sprintf("%d_%d", publishedAt.UnixNano(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(cursor string) (time.Time, int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	parts := strings.SplitN(string(raw), "_", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, fmt.Errorf("malformed feed cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return time.Unix(0, nanos), id, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type JwtKeyService interface {
+	FindKeys() ([]dto.JwtKeyResponse, error)
+	Rotate(admin utils.Claims, meta dto.SessionMeta) (*dto.JwtKeyResponse, error)
+	GetJWKS() utils.JSONWebKeySet
+}
+
+type JwtKeyServiceImpl struct {
+	JwtService            *utils.JwtService
+	SecurityLogRepository repository.SecurityLogRepository
+}
+
+func NewJwtKeyService(jwtService *utils.JwtService, securityLogRepository repository.SecurityLogRepository) JwtKeyService {
+	return &JwtKeyServiceImpl{
+		JwtService:            jwtService,
+		SecurityLogRepository: securityLogRepository,
+	}
+}
+
+func (s *JwtKeyServiceImpl) FindKeys() ([]dto.JwtKeyResponse, error) {
+	if s.JwtService.Keys == nil {
+		return nil, exception.NewBusnissLogicErr("tokens are signed with jwt.secret_key, set jwt.algorithm to RS256 or EdDSA to use signing keys")
+	}
+
+	keys := s.JwtService.Keys.Keys()
+	responses := make([]dto.JwtKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, s.toResponse(key))
+	}
+
+	return responses, nil
+}
+
+// Rotate membuat key baru untuk token berikutnya, token lama tetap valid sampai kadaluarsa
+func (s *JwtKeyServiceImpl) Rotate(admin utils.Claims, meta dto.SessionMeta) (*dto.JwtKeyResponse, error) {
+	if s.JwtService.Keys == nil {
+		return nil, exception.NewBusnissLogicErr("tokens are signed with jwt.secret_key, set jwt.algorithm to RS256 or EdDSA to rotate keys")
+	}
+
+	key, err := s.JwtService.Keys.Rotate(s.JwtService.KeyRetention())
+	if err != nil {
+		if err == utils.ErrJwtRotationDisabled {
+			return nil, exception.NewBusnissLogicErr(err.Error())
+		}
+		utils.Logger.Errorf("failed to rotate jwt key %v", err)
+		return nil, err
+	}
+
+	adminId := int64(admin.UserId)
+	if err := s.SecurityLogRepository.Create(&models.SecurityLog{
+		UserID:    &adminId,
+		Event:     string(enum.SecurityEventJwtKeyRotated),
+		IPAddress: meta.IPAddress,
+		UserAgent: truncate(meta.UserAgent, 255),
+		Detail:    "new signing key " + key.Kid,
+	}); err != nil {
+		utils.Logger.Errorf("failed to write security log %v", err)
+	}
+
+	response := s.toResponse(key)
+	return &response, nil
+}
+
+func (s *JwtKeyServiceImpl) GetJWKS() utils.JSONWebKeySet {
+	if s.JwtService.Keys == nil {
+		// token HS256 tidak bisa diverifikasi tanpa secret, jadi tidak ada public key
+		return utils.JSONWebKeySet{Keys: []utils.JSONWebKey{}}
+	}
+
+	return s.JwtService.Keys.JWKS()
+}
+
+func (s *JwtKeyServiceImpl) toResponse(key *utils.JwtKey) dto.JwtKeyResponse {
+	source := "config"
+	if key.FromDir {
+		source = "key_dir"
+	}
+
+	return dto.JwtKeyResponse{
+		Kid:       key.Kid,
+		Algorithm: key.Algorithm,
+		Source:    source,
+		CanSign:   key.PrivateKey != nil,
+		Active:    s.JwtService.Keys.IsActive(key),
+		CreatedAt: key.CreatedAt,
+	}
+}
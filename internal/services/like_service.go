@@ -0,0 +1,149 @@
+package services
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type LikeService interface {
+	LikePost(slug string, user utils.Claims) (*dto.LikeStatusResponse, error)
+	UnlikePost(slug string, user utils.Claims) (*dto.LikeStatusResponse, error)
+	LikeComment(postId int64, commentId int64, user utils.Claims) (*dto.LikeStatusResponse, error)
+	UnlikeComment(postId int64, commentId int64, user utils.Claims) (*dto.LikeStatusResponse, error)
+	FindMyLikes(filter dto.LikeFilterRequest, user utils.Claims) (*dto.PaginationResult, error)
+}
+
+type LikeServiceImpl struct {
+	LikeRepository    repository.LikeRepository
+	PostRepository    repository.PostRepository
+	CommentRepository repository.CommentRepository
+}
+
+func NewLikeService(likeRepository repository.LikeRepository, postRepository repository.PostRepository, commentRepository repository.CommentRepository) LikeService {
+	return &LikeServiceImpl{
+		LikeRepository:    likeRepository,
+		PostRepository:    postRepository,
+		CommentRepository: commentRepository,
+	}
+}
+
+func (s *LikeServiceImpl) findLikeablePost(slug string) (*models.Post, error) {
+	post, err := s.PostRepository.GetDetailPost(slug)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("post not found")
+	}
+
+	if enum.PostStatus(post.Status) != enum.PostStatusPublished {
+		return nil, exception.NewBusnissLogicErr("only published post can be liked")
+	}
+
+	return post, nil
+}
+
+func (s *LikeServiceImpl) findLikeableComment(postId int64, commentId int64) (*models.Comment, error) {
+	post, err := s.PostRepository.GetPostById(postId)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("post not found")
+	}
+
+	if enum.PostStatus(post.Status) != enum.PostStatusPublished {
+		return nil, exception.NewBusnissLogicErr("only comment on published post can be liked")
+	}
+
+	comment, err := s.CommentRepository.FindById(postId, commentId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enum.CommentStatus(comment.Status) != enum.CommentStatusActive {
+		return nil, exception.NewBusnissLogicErr("only active comment can be liked")
+	}
+
+	return comment, nil
+}
+
+func (s *LikeServiceImpl) LikePost(slug string, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	post, err := s.findLikeablePost(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.like(enum.LikeTargetPost, post.ID, user)
+}
+
+func (s *LikeServiceImpl) UnlikePost(slug string, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	post, err := s.PostRepository.GetDetailPost(slug)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("post not found")
+	}
+
+	return s.unlike(enum.LikeTargetPost, post.ID, user)
+}
+
+func (s *LikeServiceImpl) LikeComment(postId int64, commentId int64, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	comment, err := s.findLikeableComment(postId, commentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.like(enum.LikeTargetComment, comment.ID, user)
+}
+
+func (s *LikeServiceImpl) UnlikeComment(postId int64, commentId int64, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	comment, err := s.CommentRepository.FindById(postId, commentId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.unlike(enum.LikeTargetComment, comment.ID, user)
+}
+
+func (s *LikeServiceImpl) like(targetType enum.LikeTarget, targetId int64, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	like := models.Like{
+		UserID:     int64(user.UserId),
+		TargetType: int8(targetType),
+		TargetID:   targetId,
+	}
+
+	if err := s.LikeRepository.Create(like); err != nil {
+		return nil, err
+	}
+
+	return s.likeStatus(targetType, targetId, true)
+}
+
+func (s *LikeServiceImpl) unlike(targetType enum.LikeTarget, targetId int64, user utils.Claims) (*dto.LikeStatusResponse, error) {
+	if err := s.LikeRepository.Delete(int64(user.UserId), targetType, targetId); err != nil {
+		return nil, err
+	}
+
+	return s.likeStatus(targetType, targetId, false)
+}
+
+func (s *LikeServiceImpl) likeStatus(targetType enum.LikeTarget, targetId int64, liked bool) (*dto.LikeStatusResponse, error) {
+	total, err := s.LikeRepository.CountByTarget(targetType, targetId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LikeStatusResponse{
+		TargetType: int(targetType),
+		TargetID:   targetId,
+		Liked:      liked,
+		LikeCount:  total,
+	}, nil
+}
+
+func (s *LikeServiceImpl) FindMyLikes(filter dto.LikeFilterRequest, user utils.Claims) (*dto.PaginationResult, error) {
+	if filter.TargetType != 0 && !enum.IsValidLikeTarget(enum.LikeTarget(filter.TargetType)) {
+		return nil, exception.NewBadRequestErr("invalid like type")
+	}
+
+	filter.UserID = user.UserId
+
+	return s.LikeRepository.FindAllByUser(filter)
+}
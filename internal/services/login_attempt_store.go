@@ -0,0 +1,170 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+)
+
+// memorySweepInterval jarak minimal antar pembersihan counter yang sudah kadaluarsa di memory
+const memorySweepInterval = time.Minute
+
+// LoginFailurePolicy aturan counter login gagal untuk satu jenis key
+type LoginFailurePolicy struct {
+	Window    time.Duration
+	LockAfter int
+	LockFor   time.Duration
+}
+
+// LoginAttemptStore penyimpanan counter login gagal, implementasinya dipilih lewat config login_guard.store
+type LoginAttemptStore interface {
+	// Get mengembalikan nil kalau key belum punya kegagalan
+	Get(key string) (*models.LoginAttempt, error)
+	RegisterFailure(key string, policy LoginFailurePolicy) (*models.LoginAttempt, error)
+	Reset(key string) (bool, error)
+	FindLocked() ([]models.LoginAttempt, error)
+}
+
+var (
+	memoryLoginAttemptStore     *MemoryLoginAttemptStore
+	memoryLoginAttemptStoreOnce sync.Once
+)
+
+// NewLoginAttemptStore store memory dipakai bersama oleh semua handler di process yang sama,
+// tapi tidak dibagi antar child prefork sehingga validateConfig menolaknya selama prefork aktif
+func NewLoginAttemptStore(guardConfig *config.LoginGuardConfig, loginAttemptRepository repository.LoginAttemptRepository) LoginAttemptStore {
+	if guardConfig.GetStore() == config.LoginGuardStoreDatabase {
+		return &DatabaseLoginAttemptStore{
+			LoginAttemptRepository: loginAttemptRepository,
+		}
+	}
+
+	memoryLoginAttemptStoreOnce.Do(func() {
+		memoryLoginAttemptStore = NewMemoryLoginAttemptStore(guardConfig.GetFailureWindow())
+	})
+
+	return memoryLoginAttemptStore
+}
+
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*models.LoginAttempt
+	retention time.Duration
+	lastSweep time.Time
+}
+
+func NewMemoryLoginAttemptStore(retention time.Duration) *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		attempts:  map[string]*models.LoginAttempt{},
+		retention: retention,
+	}
+}
+
+func (s *MemoryLoginAttemptStore) Get(key string) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *MemoryLoginAttemptStore) RegisterFailure(key string, policy LoginFailurePolicy) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	attempt, ok := s.attempts[key]
+	if !ok || now.Sub(attempt.LastFailureAt) > policy.Window {
+		attempt = &models.LoginAttempt{Key: key}
+		s.attempts[key] = attempt
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	attempt.UpdatedAt = now
+	if attempt.Failures >= policy.LockAfter {
+		lockedUntil := now.Add(policy.LockFor)
+		attempt.LockedUntil = &lockedUntil
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.attempts[key]
+	delete(s.attempts, key)
+
+	return ok, nil
+}
+
+func (s *MemoryLoginAttemptStore) FindLocked() ([]models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	locked := make([]models.LoginAttempt, 0)
+	for _, attempt := range s.attempts {
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			locked = append(locked, *attempt)
+		}
+	}
+
+	return locked, nil
+}
+
+// sweep membuang counter yang sudah lewat masa simpan dan tidak sedang dikunci, dipanggil dengan lock
+func (s *MemoryLoginAttemptStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < memorySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, attempt := range s.attempts {
+		locked := attempt.LockedUntil != nil && attempt.LockedUntil.After(now)
+		if !locked && now.Sub(attempt.LastFailureAt) > s.retention {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+type DatabaseLoginAttemptStore struct {
+	LoginAttemptRepository repository.LoginAttemptRepository
+}
+
+func (s *DatabaseLoginAttemptStore) Get(key string) (*models.LoginAttempt, error) {
+	attempt, err := s.LoginAttemptRepository.FindByKey(key)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (s *DatabaseLoginAttemptStore) RegisterFailure(key string, policy LoginFailurePolicy) (*models.LoginAttempt, error) {
+	return s.LoginAttemptRepository.RegisterFailure(key, time.Now(), policy.Window, policy.LockAfter, policy.LockFor)
+}
+
+func (s *DatabaseLoginAttemptStore) Reset(key string) (bool, error) {
+	return s.LoginAttemptRepository.Delete(key)
+}
+
+func (s *DatabaseLoginAttemptStore) FindLocked() ([]models.LoginAttempt, error) {
+	return s.LoginAttemptRepository.FindLocked(time.Now())
+}
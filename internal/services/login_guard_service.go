@@ -0,0 +1,311 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// maxBackoffShift batas pangkat backoff supaya perkalian durasi tidak overflow
+const maxBackoffShift = 20
+
+// LoginGuardService membatasi percobaan login gagal per akun dan per IP dengan backoff bertingkat lalu lockout sementara
+type LoginGuardService interface {
+	Check(user *models.User, identifier string, meta dto.SessionMeta) error
+	RegisterFailure(user *models.User, identifier string, meta dto.SessionMeta, reason string)
+	RegisterSuccess(user *models.User)
+	// CheckTwoFactor dipanggil sebelum kode 2FA dicek, mfaTokenId (jti) kosong untuk user yang sudah login
+	CheckTwoFactor(user *models.User, mfaTokenId string, meta dto.SessionMeta) error
+	// RegisterTwoFactorFailure token mfa_pending dikunci sampai tokenExpiresAt setelah terlalu banyak kode salah
+	RegisterTwoFactorFailure(user *models.User, mfaTokenId string, tokenExpiresAt time.Time, meta dto.SessionMeta, reason string)
+	RegisterTwoFactorSuccess(user *models.User)
+	FindLockouts() ([]dto.LoginLockoutResponse, error)
+	ClearLockout(key string, admin utils.Claims) error
+	FindSecurityLogs(filter dto.SecurityLogFilterRequest) (*dto.PaginationResult, error)
+}
+
+type LoginGuardServiceImpl struct {
+	Store                 LoginAttemptStore
+	SecurityLogRepository repository.SecurityLogRepository
+	Config                *config.LoginGuardConfig
+}
+
+func NewLoginGuardService(store LoginAttemptStore, securityLogRepository repository.SecurityLogRepository, guardConfig *config.LoginGuardConfig) LoginGuardService {
+	return &LoginGuardServiceImpl{
+		Store:                 store,
+		SecurityLogRepository: securityLogRepository,
+		Config:                guardConfig,
+	}
+}
+
+// Check dipanggil sebelum password dicek, jadi percobaan selama backoff/lockout tidak pernah sampai ke bcrypt
+func (s *LoginGuardServiceImpl) Check(user *models.User, identifier string, meta dto.SessionMeta) error {
+	now := time.Now()
+
+	for _, key := range s.keys(user, identifier, meta) {
+		attempt, err := s.Store.Get(key)
+		if err != nil {
+			return err
+		}
+
+		blockedUntil := s.blockedUntil(attempt, now)
+		if !blockedUntil.After(now) {
+			continue
+		}
+
+		s.writeLog(enum.SecurityEventLoginBlocked, user, identifier, meta, "blocked by "+key)
+
+		retryAfter := int(math.Ceil(blockedUntil.Sub(now).Seconds()))
+		return exception.NewTooManyRequestsErr("too many failed login attempts, please try again in "+strconv.Itoa(retryAfter)+" seconds", retryAfter)
+	}
+
+	return nil
+}
+
+func (s *LoginGuardServiceImpl) RegisterFailure(user *models.User, identifier string, meta dto.SessionMeta, reason string) {
+	s.writeLog(enum.SecurityEventLoginFailed, user, identifier, meta, reason)
+
+	for _, key := range s.keys(user, identifier, meta) {
+		policy := s.policy(key)
+
+		attempt, err := s.Store.RegisterFailure(key, policy)
+		if err != nil {
+			utils.Logger.Errorf("failed to register login failure of %s %v", key, err)
+			continue
+		}
+
+		// lockout baru terjadi tepat saat counter mencapai batas
+		if attempt.Failures == policy.LockAfter {
+			s.writeLog(enum.SecurityEventLoginLocked, user, identifier, meta, key+" locked until "+attempt.LockedUntil.Format(time.RFC3339))
+		}
+	}
+}
+
+// RegisterSuccess hanya me-reset counter akun; counter IP dibiarkan supaya login ke akun sendiri
+// tidak bisa dipakai untuk me-reset percobaan ke akun lain
+func (s *LoginGuardServiceImpl) RegisterSuccess(user *models.User) {
+	if _, err := s.Store.Reset(accountAttemptKey(user, "")); err != nil {
+		utils.Logger.Errorf("failed to reset login failures of user %d %v", user.ID, err)
+	}
+}
+
+func (s *LoginGuardServiceImpl) CheckTwoFactor(user *models.User, mfaTokenId string, meta dto.SessionMeta) error {
+	now := time.Now()
+
+	for _, key := range twoFactorAttemptKeys(user, mfaTokenId) {
+		attempt, err := s.Store.Get(key)
+		if err != nil {
+			return err
+		}
+
+		blockedUntil := s.blockedUntil(attempt, now)
+		if !blockedUntil.After(now) {
+			continue
+		}
+
+		s.writeLog(enum.SecurityEventTwoFactorBlocked, user, "", meta, "blocked by "+key)
+
+		// token mfa_pending yang sudah dikunci tidak akan terbuka lagi sebelum kadaluarsa
+		if strings.HasPrefix(key, string(enum.LoginAttemptScopeMfaToken)+":") {
+			return exception.NewUnAuthorizationErr("too many invalid codes, please login again")
+		}
+
+		retryAfter := int(math.Ceil(blockedUntil.Sub(now).Seconds()))
+		return exception.NewTooManyRequestsErr("too many invalid two factor codes, please try again in "+strconv.Itoa(retryAfter)+" seconds", retryAfter)
+	}
+
+	return nil
+}
+
+func (s *LoginGuardServiceImpl) RegisterTwoFactorFailure(user *models.User, mfaTokenId string, tokenExpiresAt time.Time, meta dto.SessionMeta, reason string) {
+	s.writeLog(enum.SecurityEventTwoFactorFailed, user, "", meta, reason)
+
+	for _, key := range twoFactorAttemptKeys(user, mfaTokenId) {
+		policy := s.policy(key)
+		if strings.HasPrefix(key, string(enum.LoginAttemptScopeMfaToken)+":") {
+			lifetime := time.Until(tokenExpiresAt)
+			policy = LoginFailurePolicy{
+				Window:    lifetime,
+				LockAfter: s.Config.GetMaxMfaTokenFailures(),
+				LockFor:   lifetime,
+			}
+		}
+
+		attempt, err := s.Store.RegisterFailure(key, policy)
+		if err != nil {
+			utils.Logger.Errorf("failed to register two factor failure of %s %v", key, err)
+			continue
+		}
+
+		if attempt.Failures == policy.LockAfter {
+			s.writeLog(enum.SecurityEventTwoFactorLocked, user, "", meta, key+" locked until "+attempt.LockedUntil.Format(time.RFC3339))
+		}
+	}
+}
+
+func (s *LoginGuardServiceImpl) RegisterTwoFactorSuccess(user *models.User) {
+	if _, err := s.Store.Reset(twoFactorAttemptKeys(user, "")[0]); err != nil {
+		utils.Logger.Errorf("failed to reset two factor failures of user %d %v", user.ID, err)
+	}
+}
+
+func (s *LoginGuardServiceImpl) FindLockouts() ([]dto.LoginLockoutResponse, error) {
+	attempts, err := s.Store.FindLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	lockouts := make([]dto.LoginLockoutResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		lockout := dto.LoginLockoutResponse{
+			Key:           attempt.Key,
+			Failures:      attempt.Failures,
+			LastFailureAt: attempt.LastFailureAt,
+			LockedUntil:   attempt.LockedUntil,
+		}
+
+		scope, value, _ := strings.Cut(attempt.Key, ":")
+		lockout.Scope = scope
+		switch enum.LoginAttemptScope(scope) {
+		case enum.LoginAttemptScopeIP:
+			lockout.IPAddress = value
+		case enum.LoginAttemptScopeTwoFactor:
+			if userId, err := strconv.ParseInt(value, 10, 64); err == nil {
+				lockout.UserID = &userId
+			}
+		case enum.LoginAttemptScopeAccount:
+			if userId, err := strconv.ParseInt(value, 10, 64); err == nil {
+				lockout.UserID = &userId
+			} else {
+				// identifier yang tidak terdaftar
+				lockout.Identifier = strings.TrimPrefix(value, "?")
+			}
+		}
+
+		lockouts = append(lockouts, lockout)
+	}
+
+	return lockouts, nil
+}
+
+func (s *LoginGuardServiceImpl) ClearLockout(key string, admin utils.Claims) error {
+	deleted, err := s.Store.Reset(key)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return exception.NewNotFoundErr("lockout not found")
+	}
+
+	s.writeLog(enum.SecurityEventLockoutCleared, nil, "", dto.SessionMeta{}, key+" cleared by user "+strconv.Itoa(admin.UserId))
+
+	return nil
+}
+
+func (s *LoginGuardServiceImpl) FindSecurityLogs(filter dto.SecurityLogFilterRequest) (*dto.PaginationResult, error) {
+	filter.SetDefaults()
+
+	return s.SecurityLogRepository.FindAllWithPagination(filter)
+}
+
+func (s *LoginGuardServiceImpl) keys(user *models.User, identifier string, meta dto.SessionMeta) []string {
+	keys := []string{accountAttemptKey(user, identifier)}
+	if meta.IPAddress != "" {
+		keys = append(keys, string(enum.LoginAttemptScopeIP)+":"+meta.IPAddress)
+	}
+	return keys
+}
+
+func (s *LoginGuardServiceImpl) policy(key string) LoginFailurePolicy {
+	lockAfter := s.Config.GetMaxAccountFailures()
+	if strings.HasPrefix(key, string(enum.LoginAttemptScopeIP)+":") {
+		lockAfter = s.Config.GetMaxIPFailures()
+	}
+	if strings.HasPrefix(key, string(enum.LoginAttemptScopeTwoFactor)+":") {
+		lockAfter = s.Config.GetMaxTwoFactorFailures()
+	}
+
+	return LoginFailurePolicy{
+		Window:    s.Config.GetFailureWindow(),
+		LockAfter: lockAfter,
+		LockFor:   s.Config.GetLockoutDuration(),
+	}
+}
+
+// blockedUntil waktu paling cepat boleh mencoba lagi: akhir lockout, atau kegagalan terakhir + backoff
+// (base delay dikali dua untuk setiap kegagalan, maksimal max delay)
+func (s *LoginGuardServiceImpl) blockedUntil(attempt *models.LoginAttempt, now time.Time) time.Time {
+	if attempt == nil || attempt.Failures == 0 {
+		return time.Time{}
+	}
+
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+		return *attempt.LockedUntil
+	}
+
+	if now.Sub(attempt.LastFailureAt) > s.Config.GetFailureWindow() {
+		return time.Time{}
+	}
+
+	shift := attempt.Failures - 1
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+
+	delay := s.Config.GetBaseDelay() * time.Duration(1<<shift)
+	if delay > s.Config.GetMaxDelay() {
+		delay = s.Config.GetMaxDelay()
+	}
+
+	return attempt.LastFailureAt.Add(delay)
+}
+
+func (s *LoginGuardServiceImpl) writeLog(event enum.SecurityEvent, user *models.User, identifier string, meta dto.SessionMeta, detail string) {
+	log := &models.SecurityLog{
+		Event:      string(event),
+		Identifier: truncate(identifier, 150),
+		IPAddress:  meta.IPAddress,
+		UserAgent:  truncate(meta.UserAgent, 255),
+		Detail:     truncate(detail, 255),
+	}
+	if user != nil {
+		log.UserID = &user.ID
+	}
+
+	utils.Logger.WithField("event", log.Event).
+		WithField("identifier", log.Identifier).
+		WithField("ip", log.IPAddress).
+		Warn("security event: ", log.Detail)
+
+	if err := s.SecurityLogRepository.Create(log); err != nil {
+		utils.Logger.Errorf("failed to write security log %v", err)
+	}
+}
+
+// accountAttemptKey memakai id user kalau akun ditemukan, supaya login via email dan username berbagi counter.
+// Identifier yang tidak terdaftar diberi awalan "?" supaya tidak bentrok dengan id user.
+func accountAttemptKey(user *models.User, identifier string) string {
+	if user != nil {
+		return string(enum.LoginAttemptScopeAccount) + ":" + strconv.FormatInt(user.ID, 10)
+	}
+
+	return string(enum.LoginAttemptScopeAccount) + ":?" + truncate(strings.ToLower(strings.TrimSpace(identifier)), 150)
+}
+
+// twoFactorAttemptKeys counter per user selalu ada di index 0, counter per token hanya untuk login yang menunggu 2FA
+func twoFactorAttemptKeys(user *models.User, mfaTokenId string) []string {
+	keys := []string{string(enum.LoginAttemptScopeTwoFactor) + ":" + strconv.FormatInt(user.ID, 10)}
+	if mfaTokenId != "" {
+		keys = append(keys, string(enum.LoginAttemptScopeMfaToken)+":"+mfaTokenId)
+	}
+	return keys
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// Mailer adalah pengirim email, implementasinya dipilih lewat config mail.driver
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// NewMailer memakai SMTP (bisa diarahkan ke MailHog di local) kalau driver smtp dan host sudah dikonfigurasi,
+// selain itu email hanya ditulis ke log untuk development
+func NewMailer(mailConfig *config.MailConfig) Mailer {
+	if mailConfig.GetDriver() != config.MailDriverSMTP || mailConfig.Host == "" {
+		return &LogMailer{}
+	}
+
+	return &SMTPMailer{
+		Config: mailConfig,
+	}
+}
+
+type SMTPMailer struct {
+	Config *config.MailConfig
+}
+
+func (s *SMTPMailer) Send(to string, subject string, body string) error {
+	addr := fmt.Sprintf("%s:%s", s.Config.Host, s.Config.GetPort())
+
+	var auth smtp.Auth
+	if s.Config.Username != "" {
+		auth = smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)
+	}
+
+	message := strings.Join([]string{
+		"From: " + s.Config.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(addr, auth, s.Config.From, []string{to}, []byte(message))
+}
+
+type LogMailer struct{}
+
+func (s *LogMailer) Send(to string, subject string, body string) error {
+	utils.Logger.WithField("to", to).WithField("subject", subject).Info("mail not configured, email body: ", body)
+	return nil
+}
+
+// sendMailAsync mengirim email di background supaya request tidak menunggu SMTP
+func sendMailAsync(mailer Mailer, to string, subject string, body string) {
+	go func() {
+		if err := mailer.Send(to, subject, body); err != nil {
+			utils.Logger.Errorf("failed to send email to %s %v", to, err)
+		}
+	}()
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/oauth"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	oauthStateTokenPurpose = "oauth_state:"
+	oauthStateLength       = 32
+	maxUsernameLength      = 30
+
+	// user lama dari login Google menyimpan Google ID di kolom username
+	legacyGoogleProvider = "google"
+)
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_.]+`)
+
+type OAuthService interface {
+	ListProviders() []oauth.ProviderInfo
+	BeginAuth(providerName string, linkUserId int64) (string, string, error)
+	HandleLoginCallback(providerName string, req dto.OAuthCallbackRequest, stateCookie string, meta dto.SessionMeta) (dto.LoginResponse, error)
+	HandleLinkCallback(providerName string, req dto.OAuthCallbackRequest, stateCookie string, user utils.Claims) (*models.UserIdentity, error)
+	FindMyIdentities(user utils.Claims) ([]models.UserIdentity, error)
+	UnlinkIdentity(identityId int64, user utils.Claims) error
+}
+
+type OAuthServiceImpl struct {
+	Providers              *oauth.Registry
+	UserRepository         repository.UserRepository
+	UserIdentityRepository repository.UserIdentityRepository
+	AuthService            AuthService
+	Config                 *config.OAuthConfig
+}
+
+func NewOAuthService(providers *oauth.Registry, userRepository repository.UserRepository, userIdentityRepository repository.UserIdentityRepository, authService AuthService, oauthConfig *config.OAuthConfig) OAuthService {
+	return &OAuthServiceImpl{
+		Providers:              providers,
+		UserRepository:         userRepository,
+		UserIdentityRepository: userIdentityRepository,
+		AuthService:            authService,
+		Config:                 oauthConfig,
+	}
+}
+
+func (s *OAuthServiceImpl) ListProviders() []oauth.ProviderInfo {
+	return s.Providers.List()
+}
+
+// BeginAuth mengembalikan URL login provider dan nilai cookie state yang ditandatangani.
+// linkUserId diisi kalau flow ini untuk menautkan provider ke user yang sedang login.
+func (s *OAuthServiceImpl) BeginAuth(providerName string, linkUserId int64) (string, string, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := utils.GenerateRandomString(oauthStateLength)
+	stateCookie := utils.CreateSignedToken(oauthStateTokenPurpose+providerName, state+":"+strconv.FormatInt(linkUserId, 10), s.Config.GetStateTTL())
+
+	url, err := provider.AuthCodeURL(state)
+	if err != nil {
+		utils.Logger.Errorf("failed to build auth url of %s %v", providerName, err)
+		return "", "", exception.NewBadRequestErr("login provider is not available")
+	}
+
+	return url, stateCookie, nil
+}
+
+func (s *OAuthServiceImpl) HandleLoginCallback(providerName string, req dto.OAuthCallbackRequest, stateCookie string, meta dto.SessionMeta) (dto.LoginResponse, error) {
+	var responseLogin dto.LoginResponse
+
+	identity, err := s.exchange(providerName, req, stateCookie, 0)
+	if err != nil {
+		return responseLogin, err
+	}
+
+	user, err := s.findOrCreateUser(providerName, identity)
+	if err != nil {
+		return responseLogin, err
+	}
+
+	if enum.UserStatus(user.Status) != enum.UserStatusActive {
+		return responseLogin, exception.NewForbiddenErr("user is not active")
+	}
+
+	return s.AuthService.CompleteLogin(user, meta)
+}
+
+func (s *OAuthServiceImpl) HandleLinkCallback(providerName string, req dto.OAuthCallbackRequest, stateCookie string, user utils.Claims) (*models.UserIdentity, error) {
+	identity, err := s.exchange(providerName, req, stateCookie, int64(user.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.UserIdentityRepository.FindByProviderSubject(providerName, identity.Subject)
+	if err == nil {
+		if existing.UserID != int64(user.UserId) {
+			return nil, exception.NewBusnissLogicErr("this " + providerName + " account is already linked to another user")
+		}
+		return existing, nil
+	}
+	if !isNotFoundErr(err) {
+		return nil, err
+	}
+
+	userIdentity := models.UserIdentity{
+		UserID:   int64(user.UserId),
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    optionalString(identity.Email),
+	}
+	if err := s.UserIdentityRepository.Create(&userIdentity); err != nil {
+		return nil, err
+	}
+
+	return &userIdentity, nil
+}
+
+func (s *OAuthServiceImpl) FindMyIdentities(user utils.Claims) ([]models.UserIdentity, error) {
+	return s.UserIdentityRepository.FindAllByUserId(int64(user.UserId))
+}
+
+// UnlinkIdentity ditolak kalau itu satu-satunya cara login user
+func (s *OAuthServiceImpl) UnlinkIdentity(identityId int64, claims utils.Claims) error {
+	user, err := s.UserRepository.FindById(claims.UserId)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		total, err := s.UserIdentityRepository.CountByUserId(user.ID)
+		if err != nil {
+			return err
+		}
+		if total <= 1 {
+			return exception.NewBusnissLogicErr("set a password or link another provider before removing this one")
+		}
+	}
+
+	deleted, err := s.UserIdentityRepository.DeleteByUser(identityId, user.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return exception.NewNotFoundErr("identity not found")
+	}
+
+	return nil
+}
+
+func (s *OAuthServiceImpl) getProvider(providerName string) (oauth.Provider, error) {
+	provider, err := s.Providers.Get(providerName)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("login provider " + providerName + " is not configured")
+	}
+	return provider, nil
+}
+
+// exchange memverifikasi state terhadap cookie lalu menukar code dengan data user dari provider
+func (s *OAuthServiceImpl) exchange(providerName string, req dto.OAuthCallbackRequest, stateCookie string, linkUserId int64) (*oauth.Identity, error) {
+	provider, err := s.getProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := utils.VerifySignedToken(oauthStateTokenPurpose+providerName, stateCookie)
+	if err != nil {
+		return nil, exception.NewBadRequestErr("invalid or expired login state, please try again")
+	}
+
+	expected := req.State + ":" + strconv.FormatInt(linkUserId, 10)
+	if subtle.ConstantTimeCompare([]byte(subject), []byte(expected)) != 1 {
+		return nil, exception.NewBadRequestErr("invalid or expired login state, please try again")
+	}
+
+	identity, err := provider.Exchange(context.Background(), req.Code)
+	if err != nil {
+		utils.Logger.Errorf("failed to login with %s %v", providerName, err)
+		return nil, exception.NewBadRequestErr("failed to login with " + provider.DisplayName())
+	}
+
+	return identity, nil
+}
+
+func (s *OAuthServiceImpl) findOrCreateUser(providerName string, identity *oauth.Identity) (*models.User, error) {
+	/*
+	   flow:
+	   1. Cek user_identities by provider + subject → kalau ada, berarti akun provider yang sama
+	   2. User lama Google yang Google ID-nya masih di username → tautkan ke user_identities
+	   3. Kalau email sudah terverifikasi di provider dan ada user dengan email itu → link account
+	   4. Kalau benar-benar baru → create user baru dengan username asli
+	*/
+
+	userIdentity, err := s.UserIdentityRepository.FindByProviderSubject(providerName, identity.Subject)
+	if err == nil {
+		if identity.Email != "" && (userIdentity.Email == nil || *userIdentity.Email != identity.Email) {
+			if err := s.UserIdentityRepository.UpdateEmail(userIdentity.ID, &identity.Email); err != nil {
+				return nil, err
+			}
+		}
+		return s.UserRepository.FindById(int(userIdentity.UserID))
+	}
+	if !isNotFoundErr(err) {
+		return nil, err
+	}
+
+	if providerName == legacyGoogleProvider {
+		legacyUser, err := s.UserRepository.FindByUsername(identity.Subject)
+		if err == nil && legacyUser.AuthProvider == legacyGoogleProvider {
+			return s.linkIdentity(legacyUser, providerName, identity)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, exception.NewGormDBErr(err)
+		}
+	}
+
+	if identity.Email == "" {
+		return nil, exception.NewBadRequestErr("login provider did not return an email address")
+	}
+
+	userByEmail, err := s.UserRepository.FindByEmail(identity.Email)
+	if err == nil {
+		if !identity.EmailVerified {
+			return nil, exception.NewBusnissLogicErr("email is already registered, please login and link this provider from your account")
+		}
+		// akun lokal yang emailnya belum terverifikasi bisa saja didaftarkan orang lain dengan email korban,
+		// kalau di-link password milik pendaftar tetap berlaku di akun yang sekarang dipakai pemilik email
+		if userByEmail.EmailVerifiedAt == nil && userByEmail.Password != "" {
+			return nil, exception.NewBusnissLogicErr("email is already registered but not verified, please verify it or reset the password before signing in with this provider")
+		}
+		return s.linkIdentity(userByEmail, providerName, identity)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	username, err := s.uniqueUsername(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	newUser := &models.User{
+		Email:        identity.Email,
+		Username:     username,
+		Name:         identity.Name,
+		AuthProvider: providerName,
+		Role:         int(enum.RoleReader),
+		Status:       int(enum.UserStatusActive),
+		Password:     "", // no password untuk login provider
+	}
+	if newUser.Name == "" {
+		newUser.Name = username
+	}
+	if identity.Picture != "" {
+		newUser.ProfileImageURI = &identity.Picture
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		newUser.EmailVerifiedAt = &now
+	}
+
+	if err := s.UserIdentityRepository.CreateUserWithIdentity(newUser, &models.UserIdentity{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    optionalString(identity.Email),
+	}); err != nil {
+		return nil, err
+	}
+
+	return newUser, nil
+}
+
+func (s *OAuthServiceImpl) linkIdentity(user *models.User, providerName string, identity *oauth.Identity) (*models.User, error) {
+	if enum.UserStatus(user.Status) == enum.UserStatusBanned {
+		return nil, exception.NewForbiddenErr("user is banned")
+	}
+
+	if err := s.UserIdentityRepository.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    optionalString(identity.Email),
+	}); err != nil {
+		return nil, err
+	}
+
+	// user yang belum verifikasi OTP dianggap terverifikasi kalau provider sudah memverifikasi emailnya
+	if user.EmailVerifiedAt == nil && identity.EmailVerified && strings.EqualFold(user.Email, identity.Email) {
+		if err := s.UserRepository.MarkEmailVerified(user.ID); err != nil {
+			return nil, err
+		}
+		return s.UserRepository.FindById(int(user.ID))
+	}
+
+	return user, nil
+}
+
+// uniqueUsername membuat username dari username/email provider, ditambah angka acak kalau sudah dipakai
+func (s *OAuthServiceImpl) uniqueUsername(identity *oauth.Identity) (string, error) {
+	base := identity.Username
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = strings.Trim(usernameInvalidChars.ReplaceAllString(strings.ToLower(base), ""), "._")
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > maxUsernameLength-6 {
+		base = base[:maxUsernameLength-6]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		_, err := s.UserRepository.FindByUsername(candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", exception.NewGormDBErr(err)
+		}
+
+		suffix, err := utils.GenerateNumericCode(5)
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + suffix
+	}
+
+	return "", exception.NewBusnissLogicErr("failed to generate username, please try again")
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
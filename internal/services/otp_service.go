@@ -0,0 +1,104 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type OtpService interface {
+	Issue(userId int64, purpose enum.OtpPurpose) (string, error)
+	Verify(userId int64, purpose enum.OtpPurpose, code string) error
+}
+
+type OtpServiceImpl struct {
+	UserOtpRepository repository.UserOtpRepository
+	Config            *config.OtpConfig
+}
+
+func NewOtpService(userOtpRepository repository.UserOtpRepository, otpConfig *config.OtpConfig) OtpService {
+	return &OtpServiceImpl{
+		UserOtpRepository: userOtpRepository,
+		Config:            otpConfig,
+	}
+}
+
+// Issue membuat kode baru (kode lama otomatis tidak berlaku) dan mengembalikan kode asli untuk dikirim ke user
+func (s *OtpServiceImpl) Issue(userId int64, purpose enum.OtpPurpose) (string, error) {
+	latest, err := s.UserOtpRepository.FindLatest(userId, string(purpose))
+	if err == nil {
+		wait := time.Until(latest.CreatedAt.Add(s.Config.GetResendCooldown()))
+		if wait > 0 {
+			return "", exception.NewBusnissLogicErr(fmt.Sprintf("please wait %d seconds before requesting a new code", int(wait.Seconds())+1))
+		}
+	} else if !isNotFoundErr(err) {
+		return "", err
+	}
+
+	code, err := utils.GenerateNumericCode(s.Config.GetLength())
+	if err != nil {
+		return "", err
+	}
+
+	codeHash, err := utils.HashPassword(code)
+	if err != nil {
+		return "", err
+	}
+
+	otp := models.UserOtp{
+		UserID:    userId,
+		Purpose:   string(purpose),
+		CodeHash:  codeHash,
+		ExpiresAt: time.Now().Add(s.Config.GetTTL()),
+	}
+
+	if err := s.UserOtpRepository.Create(&otp); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Verify mencocokkan kode terbaru yang masih berlaku. Jatah percobaan dipesan lebih dulu lewat update bersyarat,
+// jadi request paralel tidak bisa mencoba lebih dari maxAttempts kode
+func (s *OtpServiceImpl) Verify(userId int64, purpose enum.OtpPurpose, code string) error {
+	otp, err := s.UserOtpRepository.FindLatestActive(userId, string(purpose))
+	if err != nil {
+		if isNotFoundErr(err) {
+			return exception.NewBadRequestErr("invalid or expired code")
+		}
+		return err
+	}
+
+	maxAttempts := s.Config.GetMaxAttempts()
+	reserved, err := s.UserOtpRepository.IncrementAttempts(otp.ID, maxAttempts)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return exception.NewBusnissLogicErr("too many attempts, please request a new code")
+	}
+
+	if err := utils.ComparePassword(code, otp.CodeHash); err != nil {
+		if otp.Attempts+1 >= maxAttempts {
+			return exception.NewBusnissLogicErr("too many attempts, please request a new code")
+		}
+		return exception.NewBadRequestErr("invalid or expired code")
+	}
+
+	consumed, err := s.UserOtpRepository.Consume(otp.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return exception.NewBadRequestErr("invalid or expired code")
+	}
+
+	return nil
+}
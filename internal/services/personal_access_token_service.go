@@ -0,0 +1,107 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+const (
+	personalAccessTokenLength       = 40
+	personalAccessTokenPrefixLength = 8
+)
+
+type PersonalAccessTokenService interface {
+	Create(req dto.CreatePersonalAccessTokenRequest, user utils.Claims) (*dto.PersonalAccessTokenCreatedResponse, error)
+	FindMine(user utils.Claims) ([]dto.PersonalAccessTokenResponse, error)
+	Revoke(id int64, user utils.Claims) error
+}
+
+type PersonalAccessTokenServiceImpl struct {
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepository
+}
+
+func NewPersonalAccessTokenService(personalAccessTokenRepository repository.PersonalAccessTokenRepository) PersonalAccessTokenService {
+	return &PersonalAccessTokenServiceImpl{
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
+	}
+}
+
+func (s *PersonalAccessTokenServiceImpl) Create(req dto.CreatePersonalAccessTokenRequest, user utils.Claims) (*dto.PersonalAccessTokenCreatedResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]struct{}, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !enum.IsValidTokenScope(enum.TokenScope(scope)) {
+			return nil, exception.NewBadRequestErr("invalid scope " + scope)
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+
+	plain := utils.PersonalAccessTokenPrefix + utils.GenerateRandomString(personalAccessTokenLength)
+
+	token := models.PersonalAccessToken{
+		UserID:      int64(user.UserId),
+		Name:        strings.TrimSpace(req.Name),
+		TokenHash:   utils.HashToken(plain),
+		TokenPrefix: plain[:len(utils.PersonalAccessTokenPrefix)+personalAccessTokenPrefixLength],
+		Scopes:      strings.Join(scopes, ","),
+		ExpiresAt:   time.Now().AddDate(0, 0, req.ExpiresInDays),
+	}
+
+	if err := s.PersonalAccessTokenRepository.Create(&token); err != nil {
+		return nil, err
+	}
+
+	return &dto.PersonalAccessTokenCreatedResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(token),
+		Token:                       plain,
+	}, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) FindMine(user utils.Claims) ([]dto.PersonalAccessTokenResponse, error) {
+	tokens, err := s.PersonalAccessTokenRepository.FindAllByUserId(int64(user.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.PersonalAccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, toPersonalAccessTokenResponse(token))
+	}
+
+	return response, nil
+}
+
+func (s *PersonalAccessTokenServiceImpl) Revoke(id int64, user utils.Claims) error {
+	revoked, err := s.PersonalAccessTokenRepository.RevokeByUser(id, int64(user.UserId))
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return exception.NewNotFoundErr("token not found")
+	}
+
+	return nil
+}
+
+func toPersonalAccessTokenResponse(token models.PersonalAccessToken) dto.PersonalAccessTokenResponse {
+	return dto.PersonalAccessTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      strings.Split(token.Scopes, ","),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/policy"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type PostRevisionService interface {
+	FindAllRevision(slug string, user utils.Claims) ([]dto.PostRevisionResponse, error)
+	FindDetailRevision(slug string, revisionId int64, user utils.Claims) (*dto.PostRevisionResponse, error)
+	DiffRevision(slug string, fromId, toId int64, user utils.Claims) (*dto.PostRevisionDiffResponse, error)
+	RestoreRevision(slug string, revisionId int64, user utils.Claims) (*dto.PostRevisionResponse, error)
+}
+
+type PostRevisionServiceImpl struct {
+	PostRepository         repository.PostRepository
+	PostRevisionRepository repository.PostRevisionRepository
+	SearchService          SearchService
+}
+
+func NewPostRevisionService(postRepository repository.PostRepository, postRevisionRepository repository.PostRevisionRepository, searchService SearchService) PostRevisionService {
+	return &PostRevisionServiceImpl{
+		PostRepository:         postRepository,
+		PostRevisionRepository: postRevisionRepository,
+		SearchService:          searchService,
+	}
+}
+
+func (s *PostRevisionServiceImpl) findOwnedPost(slug string, user utils.Claims) (*models.Post, error) {
+	post, err := s.PostRepository.GetDetailPost(slug)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("post not found")
+	}
+
+	// editor dengan post.edit.any boleh melihat dan memulihkan revisi post yang boleh ia edit
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostEditAny, post.AuthorID, "posts is not yours"); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+func (s *PostRevisionServiceImpl) FindAllRevision(slug string, user utils.Claims) ([]dto.PostRevisionResponse, error) {
+	post, err := s.findOwnedPost(slug, user)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.PostRevisionRepository.FindAllByPostId(post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (s *PostRevisionServiceImpl) FindDetailRevision(slug string, revisionId int64, user utils.Claims) (*dto.PostRevisionResponse, error) {
+	post, err := s.findOwnedPost(slug, user)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.PostRevisionRepository.FindById(post.ID, revisionId)
+	if err != nil {
+		return nil, err
+	}
+
+	response := mapRevisionToResponse(*revision)
+
+	return &response, nil
+}
+
+func (s *PostRevisionServiceImpl) DiffRevision(slug string, fromId, toId int64, user utils.Claims) (*dto.PostRevisionDiffResponse, error) {
+	post, err := s.findOwnedPost(slug, user)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := s.PostRevisionRepository.FindById(post.ID, fromId)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.PostRevisionRepository.FindById(post.ID, toId)
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := utils.DiffLines(from.Content, to.Content)
+	if err != nil {
+		if errors.Is(err, utils.ErrDiffTooLarge) {
+			return nil, exception.NewBusnissLogicErr("revisions are too different to compare")
+		}
+		return nil, err
+	}
+
+	diff := dto.PostRevisionDiffResponse{
+		From:         mapRevisionToResponse(*from),
+		To:           mapRevisionToResponse(*to),
+		TitleChanged: from.Title != to.Title,
+		Lines:        lines,
+	}
+
+	// konten lengkap sudah ada di lines, tidak perlu dikirim dua kali
+	diff.From.Content = ""
+	diff.To.Content = ""
+
+	for _, line := range lines {
+		switch line.Type {
+		case utils.DiffInsert:
+			diff.Additions++
+		case utils.DiffDelete:
+			diff.Deletions++
+		}
+	}
+
+	return &diff, nil
+}
+
+func (s *PostRevisionServiceImpl) RestoreRevision(slug string, revisionId int64, user utils.Claims) (*dto.PostRevisionResponse, error) {
+	post, err := s.findOwnedPost(slug, user)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.PostRevisionRepository.FindById(post.ID, revisionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision.Title == post.Title && revision.Content == post.Content {
+		return nil, exception.NewBusnissLogicErr("post already has the content of this revision")
+	}
+
+	update := repository.PostUpdate{
+		Data: map[string]interface{}{
+			"title":   revision.Title,
+			"content": revision.Content,
+		},
+		Revision: &models.PostRevision{
+			PostID:         post.ID,
+			Title:          revision.Title,
+			Content:        revision.Content,
+			EditorID:       int64(user.UserId),
+			RestoredFromID: &revision.ID,
+		},
+	}
+
+	// sama seperti UpdatePost, konten yang sudah di-approve harus di-review ulang
+	if enum.PostStatus(post.Status) == enum.PostStatusReview && post.ApprovedBy != nil {
+		update.Data["approved_by"] = nil
+	}
+
+	// nomor revisi dialokasikan di transaksi yang sama dengan update post, dengan row lock post
+	if err := s.PostRepository.ApplyPostUpdate(post.ID, enum.PostStatus(post.Status), update); err != nil {
+		return nil, err
+	}
+
+	s.SearchService.SyncPost(post.ID)
+
+	response := mapRevisionToResponse(*update.Revision)
+
+	return &response, nil
+}
+
+func mapRevisionToResponse(revision models.PostRevision) dto.PostRevisionResponse {
+	response := dto.PostRevisionResponse{
+		ID:             revision.ID,
+		PostID:         revision.PostID,
+		RevisionNumber: revision.RevisionNumber,
+		Title:          revision.Title,
+		Content:        revision.Content,
+		EditorID:       revision.EditorID,
+		RestoredFromID: revision.RestoredFromID,
+		CreatedAt:      revision.CreatedAt,
+	}
+
+	if revision.Editor != nil {
+		response.EditorName = revision.Editor.Name
+	}
+
+	return response
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/policy"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type PostAction string
+
+const (
+	PostActionSubmit  PostAction = "submit"
+	PostActionApprove PostAction = "approve"
+	PostActionReject  PostAction = "reject"
+	PostActionArchive PostAction = "archive"
+)
+
+type postTransition struct {
+	From           []enum.PostStatus
+	To             enum.PostStatus
+	AllowAuthor    bool
+	Permission     enum.Permission
+	RequireComment bool
+}
+
+// postTransitions adalah state machine status post, transisi di luar tabel ini ditolak
+var postTransitions = map[PostAction]postTransition{
+	PostActionSubmit: {
+		From:        []enum.PostStatus{enum.PostStatusInactive, enum.PostStatusDraft},
+		To:          enum.PostStatusReview,
+		AllowAuthor: true,
+	},
+	PostActionApprove: {
+		From:       []enum.PostStatus{enum.PostStatusReview},
+		To:         enum.PostStatusPublished,
+		Permission: enum.PermissionPostPublish,
+	},
+	PostActionReject: {
+		From:           []enum.PostStatus{enum.PostStatusReview},
+		To:             enum.PostStatusDraft,
+		Permission:     enum.PermissionPostPublish,
+		RequireComment: true,
+	},
+	PostActionArchive: {
+		From:        []enum.PostStatus{enum.PostStatusPublished},
+		To:          enum.PostStatusArchived,
+		AllowAuthor: true,
+		Permission:  enum.PermissionPostArchiveAny,
+	},
+}
+
+// findPostAction mencari aksi yang memindahkan status from ke to
+func findPostAction(from, to enum.PostStatus) (PostAction, bool) {
+	for action, transition := range postTransitions {
+		if transition.To != to {
+			continue
+		}
+		for _, allowedFrom := range transition.From {
+			if allowedFrom == from {
+				return action, true
+			}
+		}
+	}
+	return "", false
+}
+
+type PostWorkflowService interface {
+	Transition(slug string, action PostAction, comment string, user utils.Claims) error
+	PlanStatusChange(post *models.Post, to enum.PostStatus, user utils.Claims) (*PostStatusChange, error)
+	FindReviewQueue(filter dto.PostFilterRequest) (*dto.PaginationResult, error)
+	FindMyReviewHistory(filter dto.PostStatusEventFilterRequest, user utils.Claims) (*dto.PaginationResult, error)
+	FindStatusEvents(slug string, user utils.Claims) ([]dto.PostStatusEventResponse, error)
+}
+
+type PostWorkflowServiceImpl struct {
+	PostRepository            repository.PostRepository
+	PostStatusEventRepository repository.PostStatusEventRepository
+	SearchService             SearchService
+}
+
+func NewPostWorkflowService(postRepository repository.PostRepository, postStatusEventRepository repository.PostStatusEventRepository, searchService SearchService) PostWorkflowService {
+	return &PostWorkflowServiceImpl{
+		PostRepository:            postRepository,
+		PostStatusEventRepository: postStatusEventRepository,
+		SearchService:             searchService,
+	}
+}
+
+func (s *PostWorkflowServiceImpl) Transition(slug string, action PostAction, comment string, user utils.Claims) error {
+	post, err := s.PostRepository.GetDetailPost(slug)
+	if err != nil {
+		return exception.NewNotFoundErr("post not found")
+	}
+
+	return s.applyTransition(post, action, comment, user)
+}
+
+// PostStatusChange transisi status yang sudah divalidasi tapi belum disimpan
+type PostStatusChange struct {
+	Data  map[string]interface{}
+	Event models.PostStatusEvent
+}
+
+// PlanStatusChange dipakai UpdatePost supaya field status tetap lewat state machine dan
+// bisa disimpan dalam transaksi yang sama dengan perubahan konten. Nil berarti status tidak berubah.
+func (s *PostWorkflowServiceImpl) PlanStatusChange(post *models.Post, to enum.PostStatus, user utils.Claims) (*PostStatusChange, error) {
+	from := enum.PostStatus(post.Status)
+	if from == to {
+		return nil, nil
+	}
+
+	action, ok := findPostAction(from, to)
+	if !ok {
+		return nil, exception.NewBusnissLogicErr("post status cannot change from " + from.String() + " to " + to.String())
+	}
+
+	return s.planTransition(post, action, "", user)
+}
+
+func (s *PostWorkflowServiceImpl) applyTransition(post *models.Post, action PostAction, comment string, user utils.Claims) error {
+	change, err := s.planTransition(post, action, comment, user)
+	if err != nil {
+		return err
+	}
+
+	updated, err := s.PostRepository.UpdatePostStatus(post.ID, enum.PostStatus(post.Status), change.Data)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return exception.NewBusnissLogicErr("post status has been changed by someone else, please reload")
+	}
+
+	if err := s.PostStatusEventRepository.Create(&change.Event); err != nil {
+		return err
+	}
+
+	s.SearchService.SyncPost(post.ID)
+
+	return nil
+}
+
+func (s *PostWorkflowServiceImpl) planTransition(post *models.Post, action PostAction, comment string, user utils.Claims) (*PostStatusChange, error) {
+	transition, ok := postTransitions[action]
+	if !ok {
+		return nil, exception.NewBadRequestErr("unknown post action")
+	}
+
+	from := enum.PostStatus(post.Status)
+	validFrom := false
+	for _, allowedFrom := range transition.From {
+		if allowedFrom == from {
+			validFrom = true
+			break
+		}
+	}
+	if !validFrom {
+		return nil, exception.NewBusnissLogicErr("cannot " + string(action) + " post with status " + from.String())
+	}
+
+	if !canActOnPost(transition, post, user) {
+		return nil, exception.NewForbiddenErr("You do not have permission to " + string(action) + " this post")
+	}
+
+	if transition.RequireComment && comment == "" {
+		return nil, exception.NewBusnissLogicErr("comment is required to " + string(action) + " post")
+	}
+
+	now := time.Now()
+	toStatus := transition.To
+	data := map[string]interface{}{}
+
+	switch action {
+	case PostActionSubmit, PostActionReject:
+		// approval lama tidak berlaku lagi setelah post kembali ke author
+		data["approved_by"] = nil
+	case PostActionApprove:
+		data["approved_by"] = user.UserId
+		if post.ScheduledAt != nil && post.ScheduledAt.After(now) {
+			// post terjadwal tetap di review sampai diterbitkan publisher
+			toStatus = enum.PostStatusReview
+			if comment == "" {
+				comment = "approved, scheduled for " + post.ScheduledAt.Format(time.RFC3339)
+			}
+		} else {
+			data["scheduled_at"] = nil
+			if post.PublishedAt == nil {
+				data["published_at"] = now
+			}
+		}
+	}
+
+	data["status"] = toStatus
+
+	actorId := int64(user.UserId)
+	event := models.PostStatusEvent{
+		PostID:     post.ID,
+		FromStatus: uint8(from),
+		ToStatus:   uint8(toStatus),
+		ActorID:    &actorId,
+	}
+	if comment != "" {
+		event.Comment = &comment
+	}
+
+	return &PostStatusChange{Data: data, Event: event}, nil
+}
+
+func canActOnPost(transition postTransition, post *models.Post, user utils.Claims) bool {
+	if transition.AllowAuthor && post.AuthorID == int64(user.UserId) {
+		return true
+	}
+
+	return transition.Permission != "" && policy.Can(user, transition.Permission)
+}
+
+func (s *PostWorkflowServiceImpl) FindReviewQueue(filter dto.PostFilterRequest) (*dto.PaginationResult, error) {
+	filter.Status = int(enum.PostStatusReview)
+	filter.Unapproved = 1
+	filter.IncludeAuthor = 1
+	filter.IncludeCategory = 1
+
+	return s.PostRepository.FindAllPostWithPaging(filter)
+}
+
+func (s *PostWorkflowServiceImpl) FindMyReviewHistory(filter dto.PostStatusEventFilterRequest, user utils.Claims) (*dto.PaginationResult, error) {
+	filter.ActorID = user.UserId
+
+	return s.PostStatusEventRepository.FindAllWithPaging(filter)
+}
+
+func (s *PostWorkflowServiceImpl) FindStatusEvents(slug string, user utils.Claims) ([]dto.PostStatusEventResponse, error) {
+	post, err := s.PostRepository.GetDetailPost(slug)
+	if err != nil {
+		return nil, exception.NewNotFoundErr("post not found")
+	}
+
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostEditAny, post.AuthorID, "posts is not yours"); err != nil {
+		return nil, err
+	}
+
+	return s.PostStatusEventRepository.FindAllByPostId(post.ID)
+}
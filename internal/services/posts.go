@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
 	"github.com/MrBista/blog-api/internal/exception"
 	"github.com/MrBista/blog-api/internal/mapper"
 	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/policy"
 	"github.com/MrBista/blog-api/internal/repository"
 	"github.com/MrBista/blog-api/internal/utils"
 )
@@ -40,19 +42,34 @@ type PostService interface {
 }
 
 type PostServiceImpl struct {
-	PostRepository     repository.PostRepository
-	CategoryRepository repository.CategoryRepository
-	StorageService     StorageService
+	PostRepository         repository.PostRepository
+	CategoryRepository     repository.CategoryRepository
+	TagRepository          repository.TagRepository
+	PostRevisionRepository repository.PostRevisionRepository
+	PostWorkflowService    PostWorkflowService
+	SearchService          SearchService
+	StorageService         StorageService
+	SeriesRepository       repository.SeriesRepository
 }
 
 func NewPostService(postRepostiory repository.PostRepository,
 	categoryRepository repository.CategoryRepository,
+	tagRepository repository.TagRepository,
+	postRevisionRepository repository.PostRevisionRepository,
+	postWorkflowService PostWorkflowService,
+	searchService SearchService,
 	storageService StorageService,
+	seriesRepository repository.SeriesRepository,
 ) PostService {
 	return &PostServiceImpl{
-		PostRepository:     postRepostiory,
-		CategoryRepository: categoryRepository,
-		StorageService:     storageService,
+		PostRepository:         postRepostiory,
+		CategoryRepository:     categoryRepository,
+		TagRepository:          tagRepository,
+		PostRevisionRepository: postRevisionRepository,
+		PostWorkflowService:    postWorkflowService,
+		SearchService:          searchService,
+		StorageService:         storageService,
+		SeriesRepository:       seriesRepository,
 	}
 }
 
@@ -98,12 +115,72 @@ func (p *PostServiceImpl) FindDetailPostWitInclude(slug string, filter dto.PostF
 		return nil, err
 	}
 
+	tags, err := p.TagRepository.FindByPostId(int64(post.ID))
+	if err != nil {
+		return nil, err
+	}
+	post.Tags = tags
+
+	seriesInfo, err := p.findPostSeriesInfo(int64(post.ID))
+	if err != nil {
+		return nil, err
+	}
+	post.Series = seriesInfo
+
 	// postResponse := mapper.MapPostToResponse(*post)
 
 	return post, nil
 
 }
 
+// findPostSeriesInfo posisi dan link sebelum/sesudah hanya dihitung dari bagian yang sudah terbit
+func (p *PostServiceImpl) findPostSeriesInfo(postId int64) (*dto.PostSeriesInfo, error) {
+	series, err := p.SeriesRepository.FindByPostId(postId)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	parts, err := p.SeriesRepository.FindParts(series.ID, 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &dto.PostSeriesInfo{
+		Id:    series.ID,
+		Title: series.Title,
+		Slug:  series.Slug,
+		Total: len(parts),
+	}
+
+	for i, part := range parts {
+		if part.PostId != postId {
+			continue
+		}
+
+		info.Position = i + 1
+		if i > 0 {
+			info.Previous = toSeriesPostLink(parts[i-1])
+		}
+		if i < len(parts)-1 {
+			info.Next = toSeriesPostLink(parts[i+1])
+		}
+		break
+	}
+
+	return info, nil
+}
+
+func toSeriesPostLink(part dto.SeriesPartResponse) *dto.SeriesPostLink {
+	return &dto.SeriesPostLink{
+		Id:    part.PostId,
+		Title: part.Title,
+		Slug:  part.Slug,
+	}
+}
+
 func (p *PostServiceImpl) CreatePost(reqBody *dto.CreatePostRequest, user *utils.Claims) error {
 	catId := int64(reqBody.CategoryId)
 
@@ -133,54 +210,160 @@ func (p *PostServiceImpl) CreatePost(reqBody *dto.CreatePostRequest, user *utils
 		Content:      reqBody.Content,
 		AuthorID:     int64(user.UserId),
 		MainImageURI: &reqBody.ImgUrl,
+		Status:       uint8(enum.PostStatusDraft),
+
+		AllowGuestComments: reqBody.AllowGuestComments,
 	}
-	err := p.PostRepository.CreatePost(&modelPost)
 
-	if err != nil {
+	if reqBody.PublishAt != nil {
+		if !reqBody.PublishAt.After(time.Now()) {
+			return exception.NewBadRequestErr("publishAt must be in the future")
+		}
+		// post terjadwal tetap harus di-review, publisher hanya menerbitkan yang sudah di-approve
+		modelPost.ScheduledAt = reqBody.PublishAt
+	}
+
+	var tagIds []int64
+	if len(reqBody.Tags) > 0 {
+		// tag dibuat di luar transaksi karena idempotent, kalau insert post gagal hanya tersisa tag tanpa post
+		ids, err := p.findOrCreateTagIds(reqBody.Tags)
+		if err != nil {
+			return err
+		}
+		tagIds = ids
+	}
+
+	if err := p.PostRepository.CreatePost(&modelPost, tagIds); err != nil {
 		// handle error db
 		return err
 	}
 
 	reqBody.Id = int(modelPost.ID)
 
+	p.SearchService.SyncPost(modelPost.ID)
+
 	return nil
 }
 
 func (p *PostServiceImpl) UpdatePost(reqBody *dto.UpdatePostRequest, user utils.Claims) error {
-
-	postDetail, err := p.FindDetailPost(reqBody.Slug)
+	// kondisi sebelum update dipakai untuk otorisasi, validasi status dan baseline revisi
+	postBefore, err := p.PostRepository.GetDetailPost(reqBody.Slug)
 	if err != nil {
 		return err
 	}
 
-	if postDetail.AuthorId != user.UserId {
-		return exception.NewForbiddenErr("posts is not yours")
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostEditAny, postBefore.AuthorID, "posts is not yours"); err != nil {
+		return err
 	}
 
-	dataToUpdate := make(map[string]interface{})
+	currentStatus := enum.PostStatus(postBefore.Status)
+	update := repository.PostUpdate{Data: make(map[string]interface{})}
+
+	title, content := postBefore.Title, postBefore.Content
 	if reqBody.Title != nil {
-		dataToUpdate["title"] = *reqBody.Title
+		title = *reqBody.Title
+		update.Data["title"] = title
 	}
 	if reqBody.Content != nil {
-		dataToUpdate["content"] = *reqBody.Content
+		content = *reqBody.Content
+		update.Data["content"] = content
+	}
+
+	if reqBody.AllowGuestComments != nil {
+		update.Data["allow_guest_comments"] = *reqBody.AllowGuestComments
+	}
+
+	// post setelah perubahan, dipakai state machine (misal approve melihat jadwal terbaru)
+	postAfter := *postBefore
+	if reqBody.PublishAt != nil {
+		if !reqBody.PublishAt.After(time.Now()) {
+			return exception.NewBadRequestErr("publishAt must be in the future")
+		}
+		if currentStatus != enum.PostStatusDraft && currentStatus != enum.PostStatusReview {
+			return exception.NewBusnissLogicErr("only draft or review post can be scheduled")
+		}
+		update.Data["scheduled_at"] = *reqBody.PublishAt
+		postAfter.ScheduledAt = reqBody.PublishAt
 	}
-	dataToUpdate["status"] = reqBody.Status
 
-	if err := p.PostRepository.UpdatePost(reqBody.Slug, dataToUpdate); err != nil {
+	// approval berlaku untuk konten yang di-review, publisher menerbitkan review + approved_by
+	// jadi konten yang berubah setelah approve harus di-review ulang
+	contentChanged := title != postBefore.Title || content != postBefore.Content
+	if contentChanged && currentStatus == enum.PostStatusReview && postBefore.ApprovedBy != nil {
+		update.Data["approved_by"] = nil
+		postAfter.ApprovedBy = nil
+	}
+
+	// perubahan status wajib lewat state machine dan divalidasi sebelum ada yang disimpan
+	if reqBody.Status != nil {
+		change, err := p.PostWorkflowService.PlanStatusChange(&postAfter, enum.PostStatus(*reqBody.Status), user)
+		if err != nil {
+			return err
+		}
+		if change != nil {
+			for column, value := range change.Data {
+				update.Data[column] = value
+			}
+			update.StatusEvent = &change.Event
+		}
+	}
+
+	if reqBody.Title != nil || reqBody.Content != nil {
+		update.Revision = &models.PostRevision{
+			PostID:   postBefore.ID,
+			Title:    title,
+			Content:  content,
+			EditorID: int64(user.UserId),
+		}
+		update.Baseline = &models.PostRevision{
+			PostID:   postBefore.ID,
+			Title:    postBefore.Title,
+			Content:  postBefore.Content,
+			EditorID: postBefore.AuthorID,
+		}
+	}
+
+	if reqBody.Tags != nil {
+		// tag dibuat di luar transaksi karena idempotent, kalau update gagal hanya tersisa tag tanpa post
+		tagIds, err := p.findOrCreateTagIds(*reqBody.Tags)
+		if err != nil {
+			return err
+		}
+		update.TagIDs = tagIds
+	}
+
+	if err := p.PostRepository.ApplyPostUpdate(postBefore.ID, currentStatus, update); err != nil {
 		return err
 	}
 
+	p.SearchService.SyncPost(postBefore.ID)
+
 	return nil
 }
 
+// findOrCreateTagIds mengambil id tag dari nama, tag yang belum ada akan dibuat
+func (p *PostServiceImpl) findOrCreateTagIds(names []string) ([]int64, error) {
+	tags, err := p.TagRepository.FindOrCreateByNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]int64, 0, len(tags))
+	for _, tag := range tags {
+		tagIds = append(tagIds, tag.ID)
+	}
+
+	return tagIds, nil
+}
+
 func (p *PostServiceImpl) DeletePost(slug string, user utils.Claims) error {
 	postDetail, err := p.FindDetailPost(slug)
 	if err != nil {
 		return err
 	}
 
-	if postDetail.AuthorId != user.UserId {
-		return exception.NewForbiddenErr("posts is not yours")
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostDeleteAny, int64(postDetail.AuthorId), "posts is not yours"); err != nil {
+		return err
 	}
 
 	err = p.PostRepository.DeletePost(slug)
@@ -189,6 +372,8 @@ func (p *PostServiceImpl) DeletePost(slug string, user utils.Claims) error {
 		return err
 	}
 
+	p.SearchService.RemovePost(int64(postDetail.ID))
+
 	return nil
 }
 
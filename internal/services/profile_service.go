@@ -0,0 +1,302 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	avatarSubPath      = "avatars"
+	avatarMinDimension = 64
+	avatarMaxDimension = 4096
+	avatarJpegQuality  = 90
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_.]{3,30}$`)
+
+// username yang bentrok dengan path route atau menyesatkan
+var reservedUsernames = map[string]struct{}{
+	"me":        {},
+	"admin":     {},
+	"root":      {},
+	"api":       {},
+	"system":    {},
+	"support":   {},
+	"security":  {},
+	"moderator": {},
+	"null":      {},
+	"undefined": {},
+}
+
+type ProfileService interface {
+	UpdateProfile(req dto.UpdateProfileRequest, user utils.Claims) (*dto.UserProfileResponse, error)
+	UploadAvatar(file *multipart.FileHeader, user utils.Claims) (*dto.UserProfileResponse, error)
+	ChangeUsername(req dto.ChangeUsernameRequest, user utils.Claims) (*dto.UserProfileResponse, error)
+	// FindPublicProfile mengembalikan username terbaru di redirectTo kalau username yang dicari sudah diganti
+	FindPublicProfile(username string) (profile *dto.UserProfileResponse, redirectTo string, err error)
+}
+
+type ProfileServiceImpl struct {
+	UserRepository            repository.UserRepository
+	UsernameHistoryRepository repository.UsernameHistoryRepository
+	Storage                   StorageService
+}
+
+func NewProfileService(userRepository repository.UserRepository, usernameHistoryRepository repository.UsernameHistoryRepository, storage StorageService) ProfileService {
+	return &ProfileServiceImpl{
+		UserRepository:            userRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+		Storage:                   storage,
+	}
+}
+
+func (s *ProfileServiceImpl) UpdateProfile(req dto.UpdateProfileRequest, user utils.Claims) (*dto.UserProfileResponse, error) {
+	userModel, err := s.UserRepository.FindById(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	userModel.Name = strings.TrimSpace(req.Name)
+	if userModel.Name == "" {
+		return nil, exception.NewBadRequestErr("name is required")
+	}
+	userModel.Bio = optionalString(strings.TrimSpace(req.Bio))
+	userModel.SocialLinks = toSocialLinks(req.SocialLinks)
+
+	if err := s.UserRepository.UpdateProfile(userModel, "name", "bio", "social_links"); err != nil {
+		return nil, err
+	}
+
+	return toUserProfileResponse(userModel), nil
+}
+
+func (s *ProfileServiceImpl) UploadAvatar(file *multipart.FileHeader, user utils.Claims) (*dto.UserProfileResponse, error) {
+	maxSize := config.AppConfig.Profile.GetAvatarMaxSize()
+	if file.Size > maxSize {
+		return nil, exception.NewBadRequestErr(fmt.Sprintf("avatar must not be larger than %d KB", maxSize/1024))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, exception.NewBadRequestErr("failed to read avatar")
+	}
+	defer src.Close()
+
+	// size dari header multipart bisa tidak akurat, batasi lagi saat membaca
+	data, err := io.ReadAll(io.LimitReader(src, maxSize+1))
+	if err != nil {
+		return nil, exception.NewBadRequestErr("failed to read avatar")
+	}
+	if int64(len(data)) > maxSize {
+		return nil, exception.NewBadRequestErr(fmt.Sprintf("avatar must not be larger than %d KB", maxSize/1024))
+	}
+
+	// cek dimensi dulu sebelum decode penuh supaya gambar raksasa tidak memakan memori
+	imgConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, exception.NewBadRequestErr("avatar must be a jpeg, png or gif image")
+	}
+	if imgConfig.Width > avatarMaxDimension || imgConfig.Height > avatarMaxDimension {
+		return nil, exception.NewBadRequestErr(fmt.Sprintf("avatar must not be larger than %dx%d pixels", avatarMaxDimension, avatarMaxDimension))
+	}
+	if imgConfig.Width < avatarMinDimension || imgConfig.Height < avatarMinDimension {
+		return nil, exception.NewBadRequestErr(fmt.Sprintf("avatar must be at least %dx%d pixels", avatarMinDimension, avatarMinDimension))
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, exception.NewBadRequestErr("avatar " + format + " image is corrupted")
+	}
+
+	var buf bytes.Buffer
+	avatar := utils.CropSquare(img, config.AppConfig.Profile.GetAvatarSize())
+	if err := jpeg.Encode(&buf, avatar, &jpeg.Options{Quality: avatarJpegQuality}); err != nil {
+		return nil, err
+	}
+
+	userModel, err := s.UserRepository.FindById(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	uri, err := s.Storage.SaveBytes(buf.Bytes(), avatarSubPath, fmt.Sprintf("%d.jpg", userModel.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	oldAvatar := userModel.ProfileImageURI
+	userModel.ProfileImageURI = &uri
+	if err := s.UserRepository.UpdateProfile(userModel, "profile_image_uri"); err != nil {
+		_ = s.Storage.DeleteFile(uri)
+		return nil, err
+	}
+
+	// avatar dari provider OAuth berupa URL luar, hanya file lokal yang dihapus
+	if oldAvatar != nil && strings.HasPrefix(*oldAvatar, "/public/"+avatarSubPath+"/") {
+		if err := s.Storage.DeleteFile(*oldAvatar); err != nil {
+			utils.Logger.WithError(err).Warn("failed to delete old avatar ", *oldAvatar)
+		}
+	}
+
+	return toUserProfileResponse(userModel), nil
+}
+
+func (s *ProfileServiceImpl) ChangeUsername(req dto.ChangeUsernameRequest, user utils.Claims) (*dto.UserProfileResponse, error) {
+	newUsername := strings.ToLower(strings.TrimSpace(req.Username))
+	if !usernamePattern.MatchString(newUsername) {
+		return nil, exception.NewBadRequestErr("username may only contain lowercase letters, numbers, underscore and dot (3-30 characters)")
+	}
+	if isReservedUsername(newUsername) {
+		return nil, exception.NewBadRequestErr("username is not available")
+	}
+
+	userModel, err := s.UserRepository.FindById(user.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if userModel.Username == newUsername {
+		return toUserProfileResponse(userModel), nil
+	}
+
+	if userModel.UsernameChangedAt != nil {
+		nextChange := userModel.UsernameChangedAt.Add(config.AppConfig.Profile.GetUsernameChangeInterval())
+		if wait := time.Until(nextChange); wait > 0 {
+			return nil, exception.NewTooManyRequestsErr(
+				"username can only be changed again after "+nextChange.Format(time.RFC3339),
+				int(wait.Seconds())+1,
+			)
+		}
+	}
+
+	_, err = s.UserRepository.FindByUsername(newUsername)
+	if err == nil {
+		return nil, exception.NewBadRequestErr("username is not available")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, exception.NewGormDBErr(err)
+	}
+
+	since := time.Now().Add(-config.AppConfig.Profile.GetUsernameReservation())
+	reserved, err := s.UsernameHistoryRepository.IsReserved(newUsername, userModel.ID, since)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, exception.NewBadRequestErr("username is not available")
+	}
+
+	changed, err := s.UserRepository.ChangeUsername(userModel.ID, userModel.Username, newUsername)
+	if err != nil {
+		// unique index username bisa kena kalau ada request lain yang lebih dulu
+		return nil, err
+	}
+	if !changed {
+		return nil, exception.NewBusnissLogicErr("username was changed by another request, please try again")
+	}
+
+	now := time.Now()
+	userModel.Username = newUsername
+	userModel.UsernameChangedAt = &now
+
+	return toUserProfileResponse(userModel), nil
+}
+
+func (s *ProfileServiceImpl) FindPublicProfile(username string) (*dto.UserProfileResponse, string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+
+	userModel, err := s.UserRepository.FindByUsername(username)
+	if err == nil {
+		if enum.UserStatus(userModel.Status) == enum.UserStatusDeleted {
+			return nil, "", exception.NewNotFoundErr("user not found")
+		}
+		return toUserProfileResponse(userModel), "", nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", exception.NewGormDBErr(err)
+	}
+
+	history, err := s.UsernameHistoryRepository.FindLatestByOldUsername(username)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, "", exception.NewNotFoundErr("user not found")
+		}
+		return nil, "", err
+	}
+
+	currentUser, err := s.UserRepository.FindById(int(history.UserID))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return nil, currentUser.Username, nil
+}
+
+func toSocialLinks(req *dto.SocialLinksRequest) *models.SocialLinks {
+	if req == nil {
+		return nil
+	}
+
+	links := models.SocialLinks{
+		Website:   strings.TrimSpace(req.Website),
+		Twitter:   strings.TrimSpace(req.Twitter),
+		Github:    strings.TrimSpace(req.Github),
+		Linkedin:  strings.TrimSpace(req.Linkedin),
+		Instagram: strings.TrimSpace(req.Instagram),
+		Youtube:   strings.TrimSpace(req.Youtube),
+	}
+	if links == (models.SocialLinks{}) {
+		return nil
+	}
+
+	return &links
+}
+
+func toUserProfileResponse(user *models.User) *dto.UserProfileResponse {
+	return &dto.UserProfileResponse{
+		Id:                user.ID,
+		Name:              user.Name,
+		Username:          user.Username,
+		Bio:               user.Bio,
+		ProfileImageURI:   user.ProfileImageURI,
+		SocialLinks:       user.SocialLinks,
+		CreatedAt:         user.CreatedAt,
+		UsernameChangedAt: user.UsernameChangedAt,
+	}
+}
+
+// isReservedUsername prefix deleted_ dipakai untuk akun yang dianonimkan
+func isReservedUsername(username string) bool {
+	if _, ok := reservedUsernames[strings.ToLower(username)]; ok {
+		return true
+	}
+	return strings.HasPrefix(strings.ToLower(username), "deleted_")
+}
+
+// displayName dipakai saat user dibuat tanpa nama, jangan pakai email karena nama tampil di publik
+func displayName(name string, username string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return username
+	}
+	return name
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/search"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+const reindexBatchSize = 200
+
+type SearchService interface {
+	Search(filter dto.SearchFilterRequest) (*dto.PaginationResult, error)
+	// SyncPost dan RemovePost bersifat best-effort: error hanya di-log, Reconcile memperbaiki selisihnya
+	SyncPost(postId int64)
+	RemovePost(postId int64)
+	ReindexAll() error
+	// Reconcile membandingkan post published (id + updated_at) dengan isi index lalu
+	// mengindex ulang yang hilang/basi dan menghapus yang sudah tidak published
+	Reconcile() (int, error)
+}
+
+type SearchServiceImpl struct {
+	SearchIndex    search.SearchIndex
+	PostRepository repository.PostRepository
+}
+
+func NewSearchService(searchIndex search.SearchIndex, postRepository repository.PostRepository) SearchService {
+	return &SearchServiceImpl{
+		SearchIndex:    searchIndex,
+		PostRepository: postRepository,
+	}
+}
+
+func (s *SearchServiceImpl) Search(filter dto.SearchFilterRequest) (*dto.PaginationResult, error) {
+	result, err := s.SearchIndex.Search(search.Query{
+		Text:       filter.Query,
+		CategoryID: int64(filter.CategoryID),
+		AuthorID:   int64(filter.AuthorID),
+		Offset:     filter.GetOffset(),
+		Limit:      filter.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := dto.NewPaginationResult(result.Hits, result.Total, filter.Page, filter.PageSize, "results")
+	pagination.Extra = map[string]interface{}{
+		"facets": result.Facets,
+	}
+
+	return pagination, nil
+}
+
+func (s *SearchServiceImpl) SyncPost(postId int64) {
+	post, err := s.PostRepository.FindPostForIndex(postId)
+	if err != nil {
+		utils.Logger.Errorf("failed to load post %d for search index %v", postId, err)
+		return
+	}
+
+	if enum.PostStatus(post.Status) != enum.PostStatusPublished {
+		s.RemovePost(postId)
+		return
+	}
+
+	if err := s.SearchIndex.Index(postToDocument(*post)); err != nil {
+		utils.Logger.Errorf("failed to index post %d %v", postId, err)
+	}
+}
+
+func (s *SearchServiceImpl) RemovePost(postId int64) {
+	if err := s.SearchIndex.Delete(postId); err != nil {
+		utils.Logger.Errorf("failed to remove post %d from search index %v", postId, err)
+	}
+}
+
+func (s *SearchServiceImpl) ReindexAll() error {
+	var lastId int64
+
+	for {
+		posts, err := s.PostRepository.FindPublishedPostsAfter(lastId, reindexBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+
+		// satu batch ditulis ke index sekaligus, bukan per post
+		docs := make([]search.Document, 0, len(posts))
+		for _, post := range posts {
+			docs = append(docs, postToDocument(post))
+		}
+		if err := s.SearchIndex.IndexMany(docs); err != nil {
+			return err
+		}
+		lastId = posts[len(posts)-1].ID
+	}
+}
+
+func (s *SearchServiceImpl) Reconcile() (int, error) {
+	// isi index dibaca sebelum database: post yang terbit di tengah rekonsiliasi tidak ikut terhapus
+	indexed, err := s.SearchIndex.Versions()
+	if err != nil {
+		return 0, err
+	}
+
+	var staleIds []int64
+	var lastId int64
+	for {
+		posts, err := s.PostRepository.FindPublishedPostVersionsAfter(lastId, reindexBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			updatedAt, ok := indexed[post.ID]
+			if !ok || !updatedAt.Equal(post.UpdatedAt) {
+				staleIds = append(staleIds, post.ID)
+			}
+			delete(indexed, post.ID)
+		}
+		lastId = posts[len(posts)-1].ID
+	}
+
+	// sisa id di index sudah tidak published (atau sudah dihapus)
+	removedIds := make([]int64, 0, len(indexed))
+	for id := range indexed {
+		removedIds = append(removedIds, id)
+	}
+	if err := s.SearchIndex.DeleteMany(removedIds); err != nil {
+		return 0, err
+	}
+
+	for start := 0; start < len(staleIds); start += reindexBatchSize {
+		posts, err := s.PostRepository.FindPublishedPostsByIds(staleIds[start:min(start+reindexBatchSize, len(staleIds))])
+		if err != nil {
+			return len(removedIds), err
+		}
+
+		docs := make([]search.Document, 0, len(posts))
+		for _, post := range posts {
+			docs = append(docs, postToDocument(post))
+		}
+		if err := s.SearchIndex.IndexMany(docs); err != nil {
+			return len(removedIds), err
+		}
+	}
+
+	return len(removedIds) + len(staleIds), nil
+}
+
+func postToDocument(post models.Post) search.Document {
+	doc := search.Document{
+		ID:          post.ID,
+		Title:       post.Title,
+		Slug:        post.Slug,
+		Content:     post.Content,
+		AuthorID:    post.AuthorID,
+		PublishedAt: post.PublishedAt,
+		UpdatedAt:   post.UpdatedAt,
+	}
+
+	if post.Author != nil {
+		doc.AuthorName = post.Author.Name
+	}
+	if post.Category != nil {
+		doc.CategoryID = post.Category.ID
+		doc.CategoryName = post.Category.Name
+	}
+
+	return doc
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/policy"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type SeriesService interface {
+	FindAll(filter dto.SeriesFilterRequest) (*dto.PaginationResult, error)
+	// FindBySlug viewer boleh nil untuk pembaca anonim
+	FindBySlug(slug string, viewer *utils.Claims) (*dto.SeriesDetailResponse, error)
+	Create(body dto.CreateSeriesRequest, user utils.Claims) (*dto.SeriesResponse, error)
+	Update(slug string, body dto.UpdateSeriesRequest, user utils.Claims) error
+	SetPosts(slug string, body dto.SetSeriesPostsRequest, user utils.Claims) error
+	Delete(slug string, user utils.Claims) error
+}
+
+type SeriesServiceImpl struct {
+	SeriesRepository repository.SeriesRepository
+}
+
+func NewSeriesService(seriesRepository repository.SeriesRepository) SeriesService {
+	return &SeriesServiceImpl{
+		SeriesRepository: seriesRepository,
+	}
+}
+
+func (s *SeriesServiceImpl) FindAll(filter dto.SeriesFilterRequest) (*dto.PaginationResult, error) {
+	return s.SeriesRepository.FindAll(filter)
+}
+
+func (s *SeriesServiceImpl) FindBySlug(slug string, viewer *utils.Claims) (*dto.SeriesDetailResponse, error) {
+	series, err := s.SeriesRepository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.SeriesRepository.FindSummaryById(series.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// draft di dalam series hanya terlihat oleh pemilik dan editor
+	viewerId := 0
+	publishedOnly := true
+	if viewer != nil {
+		viewerId = viewer.UserId
+		publishedOnly = !policy.CanActOn(*viewer, enum.PermissionPostEditAny, series.AuthorID)
+	}
+
+	parts, err := s.SeriesRepository.FindParts(series.ID, viewerId, publishedOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	readCount := 0
+	for _, part := range parts {
+		if part.IsRead {
+			readCount++
+		}
+	}
+
+	progress := 0
+	if len(parts) > 0 {
+		progress = readCount * 100 / len(parts)
+	}
+
+	return &dto.SeriesDetailResponse{
+		SeriesResponse: *summary,
+		Parts:          parts,
+		ReadCount:      readCount,
+		Progress:       progress,
+	}, nil
+}
+
+func (s *SeriesServiceImpl) Create(body dto.CreateSeriesRequest, user utils.Claims) (*dto.SeriesResponse, error) {
+	authorId := int64(user.UserId)
+
+	if err := s.validatePosts(0, authorId, body.PostIds); err != nil {
+		return nil, err
+	}
+
+	// judul yang hanya berisi simbol tetap menghasilkan slug yang bisa di-route
+	slugBase := utils.Slugify(body.Title)
+	if slugBase == "" {
+		slugBase = "series"
+	}
+
+	series := models.Series{
+		AuthorID:    authorId,
+		Title:       body.Title,
+		Slug:        fmt.Sprintf("%s-%d", slugBase, time.Now().UnixMilli()),
+		Description: body.Description,
+	}
+
+	if err := s.SeriesRepository.Create(&series, body.PostIds); err != nil {
+		return nil, err
+	}
+
+	return s.SeriesRepository.FindSummaryById(series.ID)
+}
+
+func (s *SeriesServiceImpl) Update(slug string, body dto.UpdateSeriesRequest, user utils.Claims) error {
+	series, err := s.findOwned(slug, user)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{}
+	if body.Title != nil {
+		data["title"] = *body.Title
+	}
+	if body.Description != nil {
+		data["description"] = *body.Description
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+
+	return s.SeriesRepository.Update(series.ID, data)
+}
+
+func (s *SeriesServiceImpl) SetPosts(slug string, body dto.SetSeriesPostsRequest, user utils.Claims) error {
+	series, err := s.findOwned(slug, user)
+	if err != nil {
+		return err
+	}
+
+	// post tetap harus milik author series walaupun yang mengubah adalah editor
+	if err := s.validatePosts(series.ID, series.AuthorID, body.PostIds); err != nil {
+		return err
+	}
+
+	return s.SeriesRepository.ReplacePosts(series.ID, body.PostIds)
+}
+
+func (s *SeriesServiceImpl) Delete(slug string, user utils.Claims) error {
+	series, err := s.SeriesRepository.FindBySlug(slug)
+	if err != nil {
+		return err
+	}
+
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostDeleteAny, series.AuthorID, "You are not allowed to delete this series"); err != nil {
+		return err
+	}
+
+	return s.SeriesRepository.Delete(series.ID)
+}
+
+func (s *SeriesServiceImpl) findOwned(slug string, user utils.Claims) (*models.Series, error) {
+	series, err := s.SeriesRepository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := policy.AuthorizeOwned(user, enum.PermissionPostEditAny, series.AuthorID, "You are not allowed to edit this series"); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// validatePosts post harus ada, milik author series, tidak dobel, dan belum masuk series lain
+func (s *SeriesServiceImpl) validatePosts(seriesId int64, authorId int64, postIds []int64) error {
+	if len(postIds) == 0 {
+		return nil
+	}
+
+	seen := make(map[int64]bool, len(postIds))
+	for _, postId := range postIds {
+		if seen[postId] {
+			return exception.NewBadRequestErr(fmt.Sprintf("post %d is listed more than once", postId))
+		}
+		seen[postId] = true
+	}
+
+	posts, err := s.SeriesRepository.FindPostsByIds(postIds)
+	if err != nil {
+		return err
+	}
+	if len(posts) != len(postIds) {
+		return exception.NewNotFoundErr("some posts were not found")
+	}
+
+	for _, post := range posts {
+		if post.AuthorID != authorId {
+			return exception.NewBadRequestErr(fmt.Sprintf("post %d does not belong to the series author", post.ID))
+		}
+	}
+
+	conflicts, err := s.SeriesRepository.FindPostsInOtherSeries(seriesId, postIds)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return exception.NewBadRequestErr(fmt.Sprintf("post %d already belongs to another series", conflicts[0]))
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+const (
+	refreshTokenSecretLength = 48
+
+	SessionRevokedLogout   = "logout"
+	SessionRevokedByUser   = "revoked by user"
+	SessionRevokedReuse    = "refresh token reuse"
+	SessionRevokedPassword = "password changed"
+	SessionRevokedBanned   = "user banned"
+)
+
+type SessionService interface {
+	CreateSession(user *models.User, meta dto.SessionMeta) (dto.LoginResponse, error)
+	Refresh(refreshToken string, meta dto.SessionMeta) (dto.LoginResponse, error)
+	Logout(user utils.Claims) error
+	FindMySessions(user utils.Claims) ([]dto.UserSessionResponse, error)
+	RevokeMySession(sessionId int64, user utils.Claims) error
+	RevokeAllSessions(userId int64, reason string) error
+	RevokeOtherSessions(user utils.Claims, reason string) error
+}
+
+type SessionServiceImpl struct {
+	UserSessionRepository repository.UserSessionRepository
+	UserRepository        repository.UserRepository
+}
+
+func NewSessionService(userSessionRepository repository.UserSessionRepository, userRepository repository.UserRepository) SessionService {
+	return &SessionServiceImpl{
+		UserSessionRepository: userSessionRepository,
+		UserRepository:        userRepository,
+	}
+}
+
+// CreateSession membuat session baru untuk satu device lalu menerbitkan access token + refresh token
+func (s *SessionServiceImpl) CreateSession(user *models.User, meta dto.SessionMeta) (dto.LoginResponse, error) {
+	var response dto.LoginResponse
+
+	secret := utils.GenerateRandomString(refreshTokenSecretLength)
+	now := time.Now()
+
+	session := models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(secret),
+		UserAgent:        truncate(meta.UserAgent, 255),
+		IPAddress:        meta.IPAddress,
+		ExpiresAt:        now.Add(utils.GetJwtService().Config.GetExpTimeRefreshToken()),
+		LastUsedAt:       now,
+	}
+
+	if err := s.UserSessionRepository.Create(&session); err != nil {
+		return response, err
+	}
+
+	return s.issueTokens(user, session.ID, secret)
+}
+
+// Refresh menukar refresh token dengan pasangan token baru (rotasi).
+// Refresh token lama yang dipakai ulang dianggap bocor, session langsung dicabut.
+func (s *SessionServiceImpl) Refresh(refreshToken string, meta dto.SessionMeta) (dto.LoginResponse, error) {
+	var response dto.LoginResponse
+
+	sessionId, secret, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return response, exception.NewUnAuthorizationErr("invalid refresh token")
+	}
+
+	session, err := s.UserSessionRepository.FindById(sessionId)
+	if err != nil {
+		return response, exception.NewUnAuthorizationErr("invalid refresh token")
+	}
+
+	hash := utils.HashToken(secret)
+
+	if session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		return response, exception.NewUnAuthorizationErr("session has expired or been revoked")
+	}
+
+	if session.RefreshTokenHash != hash {
+		if session.PreviousTokenHash != nil && *session.PreviousTokenHash == hash {
+			utils.Logger.Warnf("refresh token reuse detected on session %d of user %d", session.ID, session.UserID)
+			if err := s.UserSessionRepository.Revoke(session.ID, SessionRevokedReuse); err != nil {
+				return response, err
+			}
+		}
+		return response, exception.NewUnAuthorizationErr("invalid refresh token")
+	}
+
+	user, err := s.UserRepository.FindById(int(session.UserID))
+	if err != nil {
+		return response, exception.NewUnAuthorizationErr("invalid refresh token")
+	}
+	if enum.UserStatus(user.Status) != enum.UserStatusActive {
+		return response, exception.NewForbiddenErr("user is not active")
+	}
+
+	newSecret := utils.GenerateRandomString(refreshTokenSecretLength)
+	expiresAt := time.Now().Add(utils.GetJwtService().Config.GetExpTimeRefreshToken())
+
+	rotated, err := s.UserSessionRepository.Rotate(session.ID, hash, utils.HashToken(newSecret), expiresAt, meta.IPAddress)
+	if err != nil {
+		return response, err
+	}
+	if !rotated {
+		// refresh lain dengan token yang sama sudah menang duluan
+		return response, exception.NewUnAuthorizationErr("invalid refresh token")
+	}
+
+	return s.issueTokens(user, session.ID, newSecret)
+}
+
+func (s *SessionServiceImpl) Logout(user utils.Claims) error {
+	return s.UserSessionRepository.Revoke(user.SessionId, SessionRevokedLogout)
+}
+
+func (s *SessionServiceImpl) FindMySessions(user utils.Claims) ([]dto.UserSessionResponse, error) {
+	sessions, err := s.UserSessionRepository.FindActiveByUserId(int64(user.UserId))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == user.SessionId
+	}
+
+	return sessions, nil
+}
+
+func (s *SessionServiceImpl) RevokeMySession(sessionId int64, user utils.Claims) error {
+	revoked, err := s.UserSessionRepository.RevokeByUser(int64(user.UserId), sessionId, SessionRevokedByUser)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return exception.NewNotFoundErr("session not found")
+	}
+
+	return nil
+}
+
+func (s *SessionServiceImpl) RevokeAllSessions(userId int64, reason string) error {
+	return s.UserSessionRepository.RevokeAllByUserId(userId, reason)
+}
+
+// RevokeOtherSessions mencabut semua session user kecuali session yang sedang dipakai
+func (s *SessionServiceImpl) RevokeOtherSessions(user utils.Claims, reason string) error {
+	return s.UserSessionRepository.RevokeOthersByUserId(int64(user.UserId), user.SessionId, reason)
+}
+
+func (s *SessionServiceImpl) issueTokens(user *models.User, sessionId int64, secret string) (dto.LoginResponse, error) {
+	var response dto.LoginResponse
+
+	accessToken, err := utils.GetJwtService().CreateAccessToken(int(user.ID), user.Role, sessionId)
+	if err != nil {
+		return response, err
+	}
+
+	response.AccessToken = accessToken
+	response.RefreshToken = strconv.FormatInt(sessionId, 10) + "." + secret
+	response.TypeToken = "access_token"
+
+	return response, nil
+}
+
+// parseRefreshToken memecah format "<sessionId>.<secret>"
+func parseRefreshToken(token string) (int64, string, bool) {
+	idPart, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return 0, "", false
+	}
+
+	sessionId, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil || sessionId <= 0 {
+		return 0, "", false
+	}
+
+	return sessionId, secret, true
+}
+
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
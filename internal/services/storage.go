@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -12,9 +13,15 @@ import (
 	"time"
 )
 
+// ErrNotLocalFile uri bukan file di storage ini (misal avatar URL dari provider OAuth)
+var ErrNotLocalFile = errors.New("uri is not a local file")
+
 type StorageService interface {
 	SaveFile(file *multipart.FileHeader, subPath string) (string, error)
+	// SaveBytes untuk file hasil olahan server (misal avatar yang sudah dipotong)
+	SaveBytes(data []byte, subPath string, filename string) (string, error)
 	DeleteFile(uri string) error
+	OpenFile(uri string) (io.ReadCloser, error)
 }
 
 type LocalStorage struct {
@@ -48,11 +55,8 @@ func saveMultipartFile(fileHeader *multipart.FileHeader, dst string) error {
 }
 
 func (s *LocalStorage) SaveFile(file *multipart.FileHeader, subPath string) (string, error) {
-
-	subPath = strings.Trim(subPath, "/")
-
-	targetDir := filepath.Join(s.BasePath, filepath.FromSlash(subPath))
-	if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+	targetDir, subPath, err := s.prepareDir(subPath)
+	if err != nil {
 		return "", err
 	}
 
@@ -63,39 +67,94 @@ func (s *LocalStorage) SaveFile(file *multipart.FileHeader, subPath string) (str
 		return "", err
 	}
 
+	return s.fileURL(subPath, filename), nil
+}
+
+func (s *LocalStorage) SaveBytes(data []byte, subPath string, filename string) (string, error) {
+	targetDir, subPath, err := s.prepareDir(subPath)
+	if err != nil {
+		return "", err
+	}
+
+	filename = fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(filename))
+
+	if err := os.WriteFile(filepath.Join(targetDir, filename), data, 0644); err != nil {
+		return "", err
+	}
+
+	return s.fileURL(subPath, filename), nil
+}
+
+func (s *LocalStorage) prepareDir(subPath string) (string, string, error) {
+	subPath = strings.Trim(subPath, "/")
+
+	targetDir := filepath.Join(s.BasePath, filepath.FromSlash(subPath))
+	if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+		return "", "", err
+	}
+
+	return targetDir, subPath, nil
+}
+
+func (s *LocalStorage) fileURL(subPath string, filename string) string {
 	base := strings.TrimRight(s.BaseURL, "/")
 	parts := []string{base}
 	if subPath != "" {
 		parts = append(parts, path.Clean(subPath))
 	}
 	parts = append(parts, url.PathEscape(filename))
-	fileURL := strings.Join(parts, "/")
 
-	return fileURL, nil
+	return strings.Join(parts, "/")
 }
 
+// DeleteFile hanya menghapus file di bawah BasePath yang uri-nya diawali BaseURL,
+// uri lain (URL luar, path mentah) ditolak dengan ErrNotLocalFile
 func (s *LocalStorage) DeleteFile(uri string) error {
-	base := strings.TrimRight(s.BaseURL, "/")
+	localPath, err := s.localPath(uri)
+	if err != nil {
+		return err
+	}
 
-	if strings.HasPrefix(uri, base+"/") || uri == base {
-		rel := strings.TrimPrefix(uri, base)
-		rel = strings.TrimLeft(rel, "/")
+	return os.Remove(localPath)
+}
 
-		// decode URL encoding (%20, dll.)
-		unescapedRel, err := url.PathUnescape(rel)
-		if err != nil {
-			return fmt.Errorf("failed to decode uri: %w", err)
-		}
+func (s *LocalStorage) OpenFile(uri string) (io.ReadCloser, error) {
+	localPath, err := s.localPath(uri)
+	if err != nil {
+		return nil, err
+	}
 
-		localPath := filepath.Join(s.BasePath, filepath.FromSlash(unescapedRel))
-		return os.Remove(localPath)
+	return os.Open(localPath)
+}
+
+// localPath memetakan uri BaseURL ke path file dan memastikan hasilnya tetap di dalam BasePath
+func (s *LocalStorage) localPath(uri string) (string, error) {
+	base := strings.TrimRight(s.BaseURL, "/")
+	if !strings.HasPrefix(uri, base+"/") {
+		return "", ErrNotLocalFile
 	}
 
-	unescapedURI, err := url.PathUnescape(uri)
+	rel, err := url.PathUnescape(strings.TrimPrefix(uri, base+"/"))
 	if err != nil {
-		return fmt.Errorf("failed to decode uri: %w", err)
+		return "", fmt.Errorf("failed to decode uri: %w", err)
 	}
 
-	localPath := filepath.Join(s.BasePath, unescapedURI)
-	return os.Remove(localPath)
+	// cegah uri seperti /public/../config.yaml keluar dari BasePath
+	rel = path.Clean("/" + rel)
+	if rel == "/" {
+		return "", ErrNotLocalFile
+	}
+
+	basePath, err := filepath.Abs(s.BasePath)
+	if err != nil {
+		return "", err
+	}
+	localPath := filepath.Join(basePath, filepath.FromSlash(rel))
+
+	inside, err := filepath.Rel(basePath, localPath)
+	if err != nil || inside == "." || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", ErrNotLocalFile
+	}
+
+	return localPath, nil
 }
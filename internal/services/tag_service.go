@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/repository"
+)
+
+type TagService interface {
+	FindAllTag(filter dto.TagFilterRequest) (*dto.PaginationResult, error)
+	FindPostsByTag(slug string, filter dto.PostFilterRequest) (*dto.PaginationResult, error)
+}
+
+type TagServiceImpl struct {
+	TagRepository  repository.TagRepository
+	PostRepository repository.PostRepository
+}
+
+func NewTagService(tagRepository repository.TagRepository, postRepository repository.PostRepository) TagService {
+	return &TagServiceImpl{
+		TagRepository:  tagRepository,
+		PostRepository: postRepository,
+	}
+}
+
+func (s *TagServiceImpl) FindAllTag(filter dto.TagFilterRequest) (*dto.PaginationResult, error) {
+	datas, err := s.TagRepository.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return datas, nil
+}
+
+func (s *TagServiceImpl) FindPostsByTag(slug string, filter dto.PostFilterRequest) (*dto.PaginationResult, error) {
+	tag, err := s.TagRepository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Tag = tag.Slug
+
+	posts, err := s.PostRepository.FindAllPostWithPaging(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
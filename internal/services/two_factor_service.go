@@ -0,0 +1,363 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/qrcode"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+const (
+	reencryptBatchSize = 200
+
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	qrCodeScale        = 6
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService interface {
+	FindStatus(userId int64, role int) (*dto.TwoFactorStatusResponse, error)
+	IsEnabled(userId int64) (bool, error)
+	IsRequired(role int) bool
+	Setup(userId int64) (*dto.TwoFactorSetupResponse, error)
+	Enable(userId int64, code string) ([]string, error)
+	Disable(userId int64, role int, req dto.TwoFactorDisableRequest) error
+	RegenerateRecoveryCodes(userId int64, code string) ([]string, error)
+	Verify(userId int64, code string, recoveryCode string) error
+	// ReencryptSecrets mengenkripsi ulang secret yang masih memakai key lama setelah rotasi key
+	ReencryptSecrets() (int, error)
+}
+
+type TwoFactorServiceImpl struct {
+	UserTwoFactorRepository repository.UserTwoFactorRepository
+	UserRepository          repository.UserRepository
+	LoginGuard              LoginGuardService
+	Config                  *config.TwoFactorConfig
+}
+
+func NewTwoFactorService(userTwoFactorRepository repository.UserTwoFactorRepository, userRepository repository.UserRepository, loginGuard LoginGuardService, twoFactorConfig *config.TwoFactorConfig) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		UserTwoFactorRepository: userTwoFactorRepository,
+		UserRepository:          userRepository,
+		LoginGuard:              loginGuard,
+		Config:                  twoFactorConfig,
+	}
+}
+
+func (s *TwoFactorServiceImpl) FindStatus(userId int64, role int) (*dto.TwoFactorStatusResponse, error) {
+	response := dto.TwoFactorStatusResponse{
+		Required: s.IsRequired(role),
+	}
+
+	twoFactor, err := s.findEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return &response, nil
+	}
+
+	remaining, err := s.UserTwoFactorRepository.CountUnusedRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Enabled = true
+	response.EnabledAt = twoFactor.EnabledAt
+	response.RemainingRecoveryCodes = remaining
+
+	return &response, nil
+}
+
+func (s *TwoFactorServiceImpl) IsEnabled(userId int64) (bool, error) {
+	twoFactor, err := s.findEnabled(userId)
+	if err != nil {
+		return false, err
+	}
+
+	return twoFactor != nil, nil
+}
+
+// IsRequired policy opsional two_factor.require_admin
+func (s *TwoFactorServiceImpl) IsRequired(role int) bool {
+	return s.Config.RequireAdmin && enum.UserRole(role) == enum.RoleAdmin
+}
+
+// Setup membuat secret baru (belum aktif) beserta provisioning URI dan QR code untuk di-scan aplikasi authenticator
+func (s *TwoFactorServiceImpl) Setup(userId int64) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.UserRepository.FindById(int(userId))
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	secretEncrypted, err := utils.EncryptString(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.UserTwoFactorRepository.UpsertPending(userId, secretEncrypted); err != nil {
+		return nil, err
+	}
+
+	uri := utils.TotpProvisioningURI(s.Config.GetIssuer(), user.Email, secret)
+
+	qr, err := qrcode.Encode(uri)
+	if err != nil {
+		return nil, err
+	}
+	qrDataURI, err := qr.DataURI(qrCodeScale)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: uri,
+		QRCode:          qrDataURI,
+	}, nil
+}
+
+// Enable mengkonfirmasi enrolment dengan kode pertama dari authenticator, recovery code hanya ditampilkan sekali di sini
+func (s *TwoFactorServiceImpl) Enable(userId int64, code string) ([]string, error) {
+	twoFactor, err := s.UserTwoFactorRepository.FindByUserId(userId)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, exception.NewBusnissLogicErr("please setup two factor authentication first")
+		}
+		return nil, err
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, exception.NewBusnissLogicErr("two factor authentication is already enabled")
+	}
+
+	step, err := s.validateCode(twoFactor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashed, err := generateRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.UserTwoFactorRepository.Enable(userId, step, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, exception.NewBusnissLogicErr("two factor authentication is already enabled")
+	}
+
+	return codes, nil
+}
+
+func (s *TwoFactorServiceImpl) Disable(userId int64, role int, req dto.TwoFactorDisableRequest) error {
+	if s.IsRequired(role) {
+		return exception.NewForbiddenErr("two factor authentication is required for your role")
+	}
+
+	if err := s.guardedVerify(userId, req.Code, req.RecoveryCode); err != nil {
+		return err
+	}
+
+	return s.UserTwoFactorRepository.Delete(userId)
+}
+
+func (s *TwoFactorServiceImpl) RegenerateRecoveryCodes(userId int64, code string) ([]string, error) {
+	if err := s.guardedVerify(userId, code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, hashed, err := generateRecoveryCodes(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.UserTwoFactorRepository.ReplaceRecoveryCodes(userId, hashed); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Verify menerima kode TOTP atau salah satu recovery code, keduanya hanya bisa dipakai sekali
+func (s *TwoFactorServiceImpl) Verify(userId int64, code string, recoveryCode string) error {
+	twoFactor, err := s.findEnabled(userId)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil {
+		return exception.NewBusnissLogicErr("two factor authentication is not enabled")
+	}
+
+	if code == "" {
+		used, err := s.UserTwoFactorRepository.UseRecoveryCode(userId, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return exception.NewUnAuthorizationErr("invalid recovery code")
+		}
+		return nil
+	}
+
+	step, err := s.validateCode(twoFactor, code)
+	if err != nil {
+		return err
+	}
+
+	marked, err := s.UserTwoFactorRepository.MarkStepUsed(userId, step)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return exception.NewUnAuthorizationErr("code has already been used, please wait for the next code")
+	}
+
+	return nil
+}
+
+// guardedVerify Verify untuk user yang sudah login, kode salah dihitung ke counter 2FA per user yang sama dengan login
+func (s *TwoFactorServiceImpl) guardedVerify(userId int64, code string, recoveryCode string) error {
+	user := &models.User{ID: userId}
+
+	if err := s.LoginGuard.CheckTwoFactor(user, "", dto.SessionMeta{}); err != nil {
+		return err
+	}
+
+	if err := s.Verify(userId, code, recoveryCode); err != nil {
+		if isUnauthorizedErr(err) {
+			s.LoginGuard.RegisterTwoFactorFailure(user, "", time.Time{}, dto.SessionMeta{}, err.Error())
+		}
+		return err
+	}
+
+	s.LoginGuard.RegisterTwoFactorSuccess(user)
+
+	return nil
+}
+
+func (s *TwoFactorServiceImpl) findEnabled(userId int64) (*models.UserTwoFactor, error) {
+	twoFactor, err := s.UserTwoFactorRepository.FindByUserId(userId)
+	if err != nil {
+		if isNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if twoFactor.EnabledAt == nil {
+		return nil, nil
+	}
+
+	return twoFactor, nil
+}
+
+func (s *TwoFactorServiceImpl) ReencryptSecrets() (int, error) {
+	keyId := s.Config.GetEncryptionKeyID()
+	reencrypted := 0
+	var lastId int64
+
+	for {
+		twoFactors, err := s.UserTwoFactorRepository.FindNotEncryptedWith(keyId, lastId, reencryptBatchSize)
+		if err != nil {
+			return reencrypted, err
+		}
+		if len(twoFactors) == 0 {
+			return reencrypted, nil
+		}
+
+		for _, twoFactor := range twoFactors {
+			lastId = twoFactor.ID
+
+			// secret yang key-nya sudah dihapus dari config dilewati, user tersebut harus setup ulang 2FA
+			secret, err := utils.DecryptString(twoFactor.SecretEncrypted)
+			if err != nil {
+				utils.Logger.Errorf("failed to decrypt two factor secret of user %d %v", twoFactor.UserID, err)
+				continue
+			}
+
+			secretEncrypted, err := utils.EncryptString(secret)
+			if err != nil {
+				return reencrypted, err
+			}
+
+			replaced, err := s.UserTwoFactorRepository.ReplaceSecret(twoFactor.ID, twoFactor.SecretEncrypted, secretEncrypted)
+			if err != nil {
+				return reencrypted, err
+			}
+			if replaced {
+				reencrypted++
+			}
+		}
+	}
+}
+
+func (s *TwoFactorServiceImpl) validateCode(twoFactor *models.UserTwoFactor, code string) (int64, error) {
+	secret, err := utils.DecryptString(twoFactor.SecretEncrypted)
+	if err != nil {
+		return 0, err
+	}
+
+	step, ok := utils.ValidateTotp(secret, code, time.Now())
+	if !ok {
+		return 0, exception.NewUnAuthorizationErr("invalid two factor code")
+	}
+
+	return step, nil
+}
+
+// generateRecoveryCodes mengembalikan kode asli untuk user dan model berisi hash untuk disimpan
+func generateRecoveryCodes(userId int64) ([]string, []models.UserRecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashed := make([]models.UserRecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, recoveryCodeLength)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:recoveryCodeLength]
+		code := encoded[:recoveryCodeLength/2] + "-" + encoded[recoveryCodeLength/2:]
+
+		codes = append(codes, code)
+		hashed = append(hashed, models.UserRecoveryCode{
+			UserID:   userId,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	return codes, hashed, nil
+}
+
+// hashRecoveryCode menormalkan input user (huruf besar, spasi, strip) sebelum di-hash
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return utils.HashToken(normalized)
+}
+
+func isNotFoundErr(err error) bool {
+	customErr, ok := err.(*exception.ErrorCustom)
+	return ok && customErr.Code == exception.ERR_NOT_FOUND
+}
+
+func isUnauthorizedErr(err error) bool {
+	customErr, ok := err.(*exception.ErrorCustom)
+	return ok && customErr.Code == exception.ERR_UNAUTHORIZATION
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/MrBista/blog-api/internal/dto"
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/exception"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+type UserBlockService interface {
+	Add(targetId int64, blockType enum.UserBlockType, user utils.Claims) error
+	Remove(targetId int64, blockType enum.UserBlockType, user utils.Claims) error
+	FindMine(blockType enum.UserBlockType, user utils.Claims) ([]dto.UserBlockResponse, error)
+}
+
+type UserBlockServiceImpl struct {
+	UserBlockRepository repository.UserBlockRepository
+	UserRepository      repository.UserRepository
+}
+
+func NewUserBlockService(userBlockRepository repository.UserBlockRepository, userRepository repository.UserRepository) UserBlockService {
+	return &UserBlockServiceImpl{
+		UserBlockRepository: userBlockRepository,
+		UserRepository:      userRepository,
+	}
+}
+
+func (s *UserBlockServiceImpl) Add(targetId int64, blockType enum.UserBlockType, user utils.Claims) error {
+	if targetId == int64(user.UserId) {
+		return exception.NewBadRequestErr("Cannot " + string(blockType) + " yourself")
+	}
+
+	target, err := s.UserRepository.FindById(int(targetId))
+	if err != nil {
+		return err
+	}
+	if enum.UserStatus(target.Status) == enum.UserStatusDeleted {
+		return exception.NewNotFoundErr("User not found")
+	}
+
+	return s.UserBlockRepository.Create(models.UserBlock{
+		UserID:   int64(user.UserId),
+		TargetID: targetId,
+		Type:     string(blockType),
+	})
+}
+
+func (s *UserBlockServiceImpl) Remove(targetId int64, blockType enum.UserBlockType, user utils.Claims) error {
+	exists, err := s.UserBlockRepository.Exists(int64(user.UserId), targetId, blockType)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if blockType == enum.UserBlockTypeMute {
+			return exception.NewNotFoundErr("User is not muted")
+		}
+		return exception.NewNotFoundErr("User is not blocked")
+	}
+
+	return s.UserBlockRepository.Delete(int64(user.UserId), targetId, blockType)
+}
+
+func (s *UserBlockServiceImpl) FindMine(blockType enum.UserBlockType, user utils.Claims) ([]dto.UserBlockResponse, error) {
+	return s.UserBlockRepository.FindAllByUserId(int64(user.UserId), blockType)
+}
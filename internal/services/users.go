@@ -13,12 +13,12 @@ import (
 type UserService interface {
 	FindAllUsers() ([]models.User, error)
 	FindAllUserWithPaginatin(filter dto.UserFilterRequest) (*dto.PaginationResult, error)
-	DeactiveUsers(ids []int) error
+	DeactiveUsers(ids []int, actor utils.Claims) error
 	CreateUser(userBody dto.RegisterRequest) (*dto.UserResponse, error)
 	FollowUser(userToFollow int, userDetail *utils.Claims) error
 	UnFollowUser(userToUnFollow int, userDetail *utils.Claims) error
-	GetListFollower(userId int) ([]dto.UserFollowerDTO, error)
-	GetListFollowing(userId int) ([]dto.UserFollowingDTO, error)
+	GetListFollower(userId int, viewerId int) ([]dto.UserFollowerDTO, error)
+	GetListFollowing(userId int, viewerId int) ([]dto.UserFollowingDTO, error)
 	CountFollower(userId int) (int64, error)
 	CountFollowing(userId int) (int64, error)
 	CheckIsFollowing(targetUserId int, currentUserId int) (bool, error)
@@ -26,14 +26,18 @@ type UserService interface {
 }
 
 type UserServiceImpl struct {
-	UserRepository repository.UserRepository
-	DB             *gorm.DB
+	UserRepository      repository.UserRepository
+	UserBlockRepository repository.UserBlockRepository
+	FeedService         FeedService
+	DB                  *gorm.DB
 }
 
-func NewUserService(userRepository repository.UserRepository, db *gorm.DB) UserService {
+func NewUserService(userRepository repository.UserRepository, userBlockRepository repository.UserBlockRepository, feedService FeedService, db *gorm.DB) UserService {
 	return &UserServiceImpl{
-		UserRepository: userRepository,
-		DB:             db,
+		UserRepository:      userRepository,
+		UserBlockRepository: userBlockRepository,
+		FeedService:         feedService,
+		DB:                  db,
 	}
 }
 
@@ -66,8 +70,14 @@ func (s *UserServiceImpl) DetailUser(userId int) (*dto.UserResponse, error) {
 	return userResponse, nil
 }
 
-func (s *UserServiceImpl) DeactiveUsers(ids []int) error {
-	if err := s.UserRepository.DeactiveUsers(ids); err != nil {
+func (s *UserServiceImpl) DeactiveUsers(ids []int, actor utils.Claims) error {
+	for _, id := range ids {
+		if id == actor.UserId {
+			return exception.NewBadRequestErr("You cannot ban yourself")
+		}
+	}
+
+	if err := s.UserRepository.DeactiveUsers(ids, SessionRevokedBanned); err != nil {
 		return err
 	}
 
@@ -101,7 +111,7 @@ func (s *UserServiceImpl) CreateUser(userBody dto.RegisterRequest) (*dto.UserRes
 	}
 
 	modelUser := models.User{
-		Name:     userBody.Email,
+		Name:     displayName(userBody.Name, userBody.Username),
 		Username: userBody.Username,
 		Email:    userBody.Email,
 		Password: passwordHash,
@@ -140,6 +150,14 @@ func (s *UserServiceImpl) FollowUser(userToFollow int, userDetail *utils.Claims)
 		return exception.NewNotFoundErr("User not found")
 	}
 
+	blocked, err := s.UserBlockRepository.IsBlockedEither(int64(userDetail.UserId), int64(userToFollow))
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return exception.NewForbiddenErr("Cannot follow this user")
+	}
+
 	isFollowing, err := s.UserRepository.CheckIsFollowing(userDetail.UserId, userToFollow)
 	if err != nil {
 		return err
@@ -157,6 +175,11 @@ func (s *UserServiceImpl) FollowUser(userToFollow int, userDetail *utils.Claims)
 		return err
 	}
 
+	// inbox feed hanya pelengkap, follow tetap berhasil walau backfill gagal
+	if err := s.FeedService.OnFollow(int64(userDetail.UserId), int64(userToFollow)); err != nil {
+		utils.Logger.WithError(err).Warn("failed to backfill feed for user ", userDetail.UserId)
+	}
+
 	return nil
 }
 
@@ -177,10 +200,14 @@ func (s *UserServiceImpl) UnFollowUser(userToUnFollow int, userDetail *utils.Cla
 		return err
 	}
 
+	if err := s.FeedService.OnUnfollow(int64(userDetail.UserId), int64(userToUnFollow)); err != nil {
+		utils.Logger.WithError(err).Warn("failed to clean feed for user ", userDetail.UserId)
+	}
+
 	return nil
 }
 
-func (s *UserServiceImpl) GetListFollower(userId int) ([]dto.UserFollowerDTO, error) {
+func (s *UserServiceImpl) GetListFollower(userId int, viewerId int) ([]dto.UserFollowerDTO, error) {
 	var userExists int64
 	if err := s.DB.Model(&models.User{}).Where("id = ?", userId).Count(&userExists).Error; err != nil {
 		return nil, exception.NewGormDBErr(err)
@@ -189,7 +216,7 @@ func (s *UserServiceImpl) GetListFollower(userId int) ([]dto.UserFollowerDTO, er
 		return nil, exception.NewNotFoundErr("User not found")
 	}
 
-	followers, err := s.UserRepository.GetListFollower(userId)
+	followers, err := s.UserRepository.GetListFollower(userId, viewerId)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +224,7 @@ func (s *UserServiceImpl) GetListFollower(userId int) ([]dto.UserFollowerDTO, er
 	return followers, nil
 }
 
-func (s *UserServiceImpl) GetListFollowing(userId int) ([]dto.UserFollowingDTO, error) {
+func (s *UserServiceImpl) GetListFollowing(userId int, viewerId int) ([]dto.UserFollowingDTO, error) {
 	var userExists int64
 	if err := s.DB.Model(&models.User{}).Where("id = ?", userId).Count(&userExists).Error; err != nil {
 		return nil, exception.NewGormDBErr(err)
@@ -206,7 +233,7 @@ func (s *UserServiceImpl) GetListFollowing(userId int) ([]dto.UserFollowingDTO,
 		return nil, exception.NewNotFoundErr("User not found")
 	}
 
-	following, err := s.UserRepository.GetListFollowing(userId)
+	following, err := s.UserRepository.GetListFollowing(userId, viewerId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,22 @@
+package spam
+
+// Input adalah data komentar yang dinilai, komentar tamu hanya punya Email
+type Input struct {
+	UserID  *int64
+	Email   *string
+	PostID  int64
+	Content string
+	// CommentID terisi saat komentar diedit, 0 untuk komentar baru
+	CommentID int64
+}
+
+type Result struct {
+	// Score 0 sampai 1, semakin tinggi semakin mungkin spam
+	Score   float64
+	Reasons []string
+}
+
+// SpamChecker bisa diganti implementasi lain (Akismet, model ML, dll) tanpa mengubah service komentar
+type SpamChecker interface {
+	Check(input Input) (*Result, error)
+}
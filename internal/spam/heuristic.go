@@ -0,0 +1,95 @@
+package spam
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+// History dipakai heuristic untuk melihat komentar sebelumnya dari penulis yang sama,
+// excludeId (0 = tidak ada) supaya komentar yang sedang diedit tidak dihitung sebagai riwayatnya sendiri
+type History interface {
+	CountRecentByAuthor(userId *int64, email *string, since time.Time, excludeId int64) (int64, error)
+	CountDuplicateByAuthor(userId *int64, email *string, content string, since time.Time, excludeId int64) (int64, error)
+}
+
+type HeuristicConfig struct {
+	MaxLinks       int
+	BlockedWords   []string
+	VelocityLimit  int
+	VelocityWindow time.Duration
+	DuplicateSince time.Duration
+}
+
+// HeuristicChecker adalah SpamChecker default yang berjalan lokal tanpa layanan eksternal
+type HeuristicChecker struct {
+	History History
+	Config  HeuristicConfig
+}
+
+func NewHeuristicChecker(history History, config HeuristicConfig) SpamChecker {
+	if config.MaxLinks <= 0 {
+		config.MaxLinks = 2
+	}
+	if config.VelocityLimit <= 0 {
+		config.VelocityLimit = 5
+	}
+	if config.VelocityWindow <= 0 {
+		config.VelocityWindow = time.Minute
+	}
+	if config.DuplicateSince <= 0 {
+		config.DuplicateSince = 24 * time.Hour
+	}
+
+	return &HeuristicChecker{
+		History: history,
+		Config:  config,
+	}
+}
+
+func (c *HeuristicChecker) Check(input Input) (*Result, error) {
+	result := &Result{Reasons: []string{}}
+
+	if links := len(linkPattern.FindAllStringIndex(input.Content, -1)); links > c.Config.MaxLinks {
+		result.add(0.4+0.1*float64(links-c.Config.MaxLinks-1), fmt.Sprintf("too many links (%d)", links))
+	}
+
+	lower := strings.ToLower(input.Content)
+	for _, word := range c.Config.BlockedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" && strings.Contains(lower, word) {
+			result.add(0.5, "contains blocked word \""+word+"\"")
+		}
+	}
+
+	now := time.Now()
+
+	duplicates, err := c.History.CountDuplicateByAuthor(input.UserID, input.Email, input.Content, now.Add(-c.Config.DuplicateSince), input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if duplicates > 0 {
+		result.add(0.6, "duplicate content")
+	}
+
+	recent, err := c.History.CountRecentByAuthor(input.UserID, input.Email, now.Add(-c.Config.VelocityWindow), input.CommentID)
+	if err != nil {
+		return nil, err
+	}
+	if recent >= int64(c.Config.VelocityLimit) {
+		result.add(0.5, fmt.Sprintf("posting too fast (%d comments in %s)", recent, c.Config.VelocityWindow))
+	}
+
+	return result, nil
+}
+
+func (r *Result) add(score float64, reason string) {
+	r.Score += score
+	if r.Score > 1 {
+		r.Score = 1
+	}
+	r.Reasons = append(r.Reasons, reason)
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+type DiffLine struct {
+	Type    string `json:"type"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+	Content string `json:"content"`
+}
+
+// batas diff supaya dua revisi yang sangat berbeda tidak menghabiskan CPU/memory
+const (
+	maxDiffLines = 10000
+	maxDiffEdits = 1000
+)
+
+var ErrDiffTooLarge = errors.New("diff is too large")
+
+// DiffLines membandingkan dua teks per baris memakai algoritma Myers O((n+m)·D),
+// D adalah jumlah baris yang berubah. ErrDiffTooLarge kalau teks atau perubahannya melewati batas.
+func DiffLines(oldText, newText string) ([]DiffLine, error) {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+	if len(oldLines) > maxDiffLines || len(newLines) > maxDiffLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	// baris awal & akhir yang sama tidak perlu ikut dihitung
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	oldMid := oldLines[prefix : len(oldLines)-suffix]
+	newMid := newLines[prefix : len(newLines)-suffix]
+
+	middle, err := myersDiff(oldMid, newMid, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DiffLine, 0, prefix+len(middle)+suffix)
+	for k := 0; k < prefix; k++ {
+		result = append(result, DiffLine{Type: DiffEqual, OldLine: k + 1, NewLine: k + 1, Content: oldLines[k]})
+	}
+	result = append(result, middle...)
+	for k := 0; k < suffix; k++ {
+		result = append(result, DiffLine{
+			Type:    DiffEqual,
+			OldLine: len(oldLines) - suffix + k + 1,
+			NewLine: len(newLines) - suffix + k + 1,
+			Content: oldLines[len(oldLines)-suffix+k],
+		})
+	}
+
+	return result, nil
+}
+
+// myersDiff mencari edit script terpendek, offset adalah jumlah baris sebelum a/b untuk nomor baris
+func myersDiff(a, b []string, offset int) ([]DiffLine, error) {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxDiffEdits {
+		maxD = maxDiffEdits
+	}
+
+	// v[k] = x terjauh di diagonal k, trace[d] menyimpan v[-d..d] setelah langkah d untuk backtrack
+	v := make([]int, 2*maxD+3)
+	center := maxD + 1
+	var trace [][]int32
+
+	found := -1
+	for d := 0; d <= maxD && found < 0; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[center+k-1] < v[center+k+1]) {
+				x = v[center+k+1]
+			} else {
+				x = v[center+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[center+k] = x
+			if x >= n && y >= m {
+				found = d
+			}
+		}
+
+		step := make([]int32, 2*d+1)
+		for k := -d; k <= d; k++ {
+			step[k+d] = int32(v[center+k])
+		}
+		trace = append(trace, step)
+	}
+	if found < 0 {
+		return nil, ErrDiffTooLarge
+	}
+
+	// backtrack dari (n, m) ke (0, 0), hasil disusun terbalik
+	reversed := make([]DiffLine, 0, n+m)
+	x, y := n, m
+	for d := found; d >= 0; d-- {
+		k := x - y
+
+		prevX, prevY := 0, 0
+		if d > 0 {
+			prev := trace[d-1]
+			at := func(k int) int { return int(prev[k+d-1]) }
+
+			prevK := k - 1
+			if k == -d || (k != d && at(k-1) < at(k+1)) {
+				prevK = k + 1
+			}
+			prevX = at(prevK)
+			prevY = prevX - prevK
+		}
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, DiffLine{Type: DiffEqual, OldLine: offset + x + 1, NewLine: offset + y + 1, Content: a[x]})
+		}
+
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, DiffLine{Type: DiffInsert, NewLine: offset + y + 1, Content: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, DiffLine{Type: DiffDelete, OldLine: offset + x + 1, Content: a[x]})
+		}
+	}
+
+	result := make([]DiffLine, len(reversed))
+	for i, line := range reversed {
+		result[len(reversed)-1-i] = line
+	}
+	return result, nil
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
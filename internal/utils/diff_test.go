@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// rebuild menyusun ulang teks lama dan baru dari hasil diff sambil mengecek nomor baris
+func rebuild(t *testing.T, lines []DiffLine) (string, string, int) {
+	t.Helper()
+
+	var oldLines, newLines []string
+	edits := 0
+	for _, line := range lines {
+		switch line.Type {
+		case DiffEqual:
+			oldLines = append(oldLines, line.Content)
+			newLines = append(newLines, line.Content)
+			if line.OldLine != len(oldLines) || line.NewLine != len(newLines) {
+				t.Fatalf("equal line numbers %d/%d, want %d/%d", line.OldLine, line.NewLine, len(oldLines), len(newLines))
+			}
+		case DiffDelete:
+			oldLines = append(oldLines, line.Content)
+			edits++
+			if line.OldLine != len(oldLines) {
+				t.Fatalf("delete line number %d, want %d", line.OldLine, len(oldLines))
+			}
+		case DiffInsert:
+			newLines = append(newLines, line.Content)
+			edits++
+			if line.NewLine != len(newLines) {
+				t.Fatalf("insert line number %d, want %d", line.NewLine, len(newLines))
+			}
+		}
+	}
+
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), edits
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		edits    int
+	}{
+		{"empty", "", "", 0},
+		{"insert all", "", "a\nb", 2},
+		{"delete all", "a\nb", "", 2},
+		{"equal", "a\nb\nc", "a\nb\nc", 0},
+		// contoh dari paper Myers, edit script terpendek D = 5
+		{"myers paper", "A\nB\nC\nA\nB\nB\nA", "C\nB\nA\nB\nA\nC", 5},
+		{"change middle", "head\nold\ntail", "head\nnew\ntail", 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lines, err := DiffLines(tc.old, tc.new)
+			if err != nil {
+				t.Fatalf("DiffLines: %v", err)
+			}
+
+			oldText, newText, edits := rebuild(t, lines)
+			if oldText != tc.old || newText != tc.new {
+				t.Fatalf("rebuilt %q -> %q, want %q -> %q", oldText, newText, tc.old, tc.new)
+			}
+			if edits != tc.edits {
+				t.Fatalf("edits = %d, want %d", edits, tc.edits)
+			}
+		})
+	}
+}
+
+func TestDiffLinesTooLarge(t *testing.T) {
+	many := strings.Repeat("line\n", maxDiffLines+1)
+	if _, err := DiffLines(many, "x"); !errors.Is(err, ErrDiffTooLarge) {
+		t.Fatalf("err = %v, want ErrDiffTooLarge", err)
+	}
+
+	// semua baris berbeda: jumlah edit melewati batas walaupun jumlah baris masih boleh
+	var oldText, newText strings.Builder
+	for i := 0; i < maxDiffEdits; i++ {
+		oldText.WriteString("old\n")
+		newText.WriteString("new\n")
+	}
+	if _, err := DiffLines(oldText.String(), newText.String()); !errors.Is(err, ErrDiffTooLarge) {
+		t.Fatalf("err = %v, want ErrDiffTooLarge", err)
+	}
+}
+
+func TestDiffLinesIsMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomText := func() string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	for iter := 0; iter < 200; iter++ {
+		oldText, newText := randomText(), randomText()
+
+		lines, err := DiffLines(oldText, newText)
+		if err != nil {
+			t.Fatalf("DiffLines: %v", err)
+		}
+		gotOld, gotNew, edits := rebuild(t, lines)
+		if gotOld != oldText || gotNew != newText {
+			t.Fatalf("rebuilt %q -> %q, want %q -> %q", gotOld, gotNew, oldText, newText)
+		}
+
+		// edit script terpendek = n + m - 2*LCS
+		a, b := splitLines(oldText), splitLines(newText)
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		if want := len(a) + len(b) - 2*lcs[0][0]; edits != want {
+			t.Fatalf("%q -> %q: edits = %d, want %d", oldText, newText, edits, want)
+		}
+	}
+}
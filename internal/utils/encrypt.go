@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/MrBista/blog-api/internal/config"
+)
+
+var ErrUnknownEncryptionKey = errors.New("unknown encryption key")
+
+// EncryptString mengenkripsi data sensitif yang harus bisa dibaca lagi (misal secret TOTP) dengan AES-GCM
+// memakai two_factor.encryption_key. Hasilnya "<key id>:<base64>" supaya key bisa dirotasi.
+func EncryptString(plain string) (string, error) {
+	keyId := config.AppConfig.TwoFactor.GetEncryptionKeyID()
+	gcm, err := newDataCipher(keyId)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return keyId + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptString membuka hasil EncryptString dengan key aktif atau key lama sesuai key id
+func DecryptString(encrypted string) (string, error) {
+	keyId, payload, ok := strings.Cut(encrypted, ":")
+	if !ok {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	gcm, err := newDataCipher(keyId)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+func newDataCipher(keyId string) (cipher.AEAD, error) {
+	keys, err := config.AppConfig.TwoFactor.EncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := keys[keyId]
+	if !ok {
+		return nil, ErrUnknownEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MrBista/blog-api/internal/config"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune(b)), 32)))
+}
+
+func setTwoFactorKeys(t *testing.T, cfg config.TwoFactorConfig) {
+	t.Helper()
+	previous := config.AppConfig
+	config.AppConfig = &config.Config{TwoFactor: cfg}
+	t.Cleanup(func() { config.AppConfig = previous })
+}
+
+func TestEncryptStringRoundTrip(t *testing.T) {
+	setTwoFactorKeys(t, config.TwoFactorConfig{EncryptionKey: testKey('a'), EncryptionKeyID: "v1"})
+
+	encrypted, err := EncryptString("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("EncryptString: %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "v1:") {
+		t.Fatalf("encrypted %q has no key id prefix", encrypted)
+	}
+
+	plain, err := DecryptString(encrypted)
+	if err != nil {
+		t.Fatalf("DecryptString: %v", err)
+	}
+	if plain != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("decrypted %q", plain)
+	}
+}
+
+func TestDecryptStringAfterRotation(t *testing.T) {
+	setTwoFactorKeys(t, config.TwoFactorConfig{EncryptionKey: testKey('a'), EncryptionKeyID: "v1"})
+	encrypted, err := EncryptString("secret")
+	if err != nil {
+		t.Fatalf("EncryptString: %v", err)
+	}
+
+	// key baru aktif, key lama masih dipakai untuk membuka data lama
+	setTwoFactorKeys(t, config.TwoFactorConfig{
+		EncryptionKey:          testKey('b'),
+		EncryptionKeyID:        "v2",
+		PreviousEncryptionKeys: map[string]string{"v1": testKey('a')},
+	})
+	if plain, err := DecryptString(encrypted); err != nil || plain != "secret" {
+		t.Fatalf("DecryptString = %q, %v", plain, err)
+	}
+	if reencrypted, err := EncryptString("secret"); err != nil || !strings.HasPrefix(reencrypted, "v2:") {
+		t.Fatalf("EncryptString = %q, %v, want v2 prefix", reencrypted, err)
+	}
+
+	// setelah key lama dihapus dari config data lama tidak bisa dibuka lagi
+	setTwoFactorKeys(t, config.TwoFactorConfig{EncryptionKey: testKey('b'), EncryptionKeyID: "v2"})
+	if _, err := DecryptString(encrypted); !errors.Is(err, ErrUnknownEncryptionKey) {
+		t.Fatalf("err = %v, want ErrUnknownEncryptionKey", err)
+	}
+}
+
+func TestDecryptStringWrongKey(t *testing.T) {
+	setTwoFactorKeys(t, config.TwoFactorConfig{EncryptionKey: testKey('a'), EncryptionKeyID: "v1"})
+	encrypted, err := EncryptString("secret")
+	if err != nil {
+		t.Fatalf("EncryptString: %v", err)
+	}
+
+	// id sama tapi isi key berbeda, GCM harus menolak
+	setTwoFactorKeys(t, config.TwoFactorConfig{EncryptionKey: testKey('c'), EncryptionKeyID: "v1"})
+	if _, err := DecryptString(encrypted); err == nil {
+		t.Fatal("DecryptString with wrong key succeeded")
+	}
+}
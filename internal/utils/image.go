@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// CropSquare memotong bagian tengah gambar menjadi persegi lalu memperkecil ke size x size.
+// Gambar yang lebih kecil dari size tidak diperbesar. Pixel transparan ditimpa warna putih
+// supaya hasilnya bisa disimpan sebagai JPEG.
+func CropSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	if side < size {
+		size = side
+	}
+
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	resized := image.NewRGBA64(image.Rect(0, 0, size, size))
+
+	// box filter: setiap pixel tujuan adalah rata-rata area sumber yang diwakilinya
+	for dy := 0; dy < size; dy++ {
+		sy0 := y0 + dy*side/size
+		sy1 := y0 + (dy+1)*side/size
+
+		for dx := 0; dx < size; dx++ {
+			sx0 := x0 + dx*side/size
+			sx1 := x0 + (dx+1)*side/size
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			resized.SetRGBA64(dx, dy, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	dst := image.NewRGBA(resized.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), resized, image.Point{}, draw.Over)
+
+	return dst
+}
@@ -12,15 +12,28 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	AccessTokenType     = "accessToken"
+	MfaPendingTokenType = "mfa_pending"
+
+	// personal access token bukan JWT, dikenali dari prefix-nya
+	PersonalAccessTokenType   = "personalAccessToken"
+	PersonalAccessTokenPrefix = "bpat_"
+)
+
 type Claims struct {
-	UserId    int    `json:"userId"`
-	Role      int    `json:"role"`
-	TokenType string `json:"tokenType"`
+	UserId    int      `json:"userId"`
+	Role      int      `json:"role"`
+	TokenType string   `json:"tokenType"`
+	SessionId int64    `json:"sid"`
+	Scopes    []string `json:"scopes,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type JwtService struct {
 	Config *config.JwtConfig
+	// Keys nil di mode HS256, token ditandatangani dengan secret_key
+	Keys *JwtKeySet
 }
 
 var jwtService *JwtService
@@ -32,6 +45,27 @@ func InitJwtService() {
 	jwtService = &JwtService{
 		Config: &config.AppConfig.JWT,
 	}
+
+	if !jwtService.Config.IsAsymmetric() {
+		return
+	}
+
+	keySet, err := NewJwtKeySet(jwtService.Config)
+	if err != nil {
+		log.Fatalf("failed to load jwt keys: %v", err)
+	}
+	jwtService.Keys = keySet
+
+	// key pertama dibuat di parent sebelum child prefork jalan, supaya semua child memakai key yang sama
+	if _, err := keySet.Active(); err != nil && !fiber.IsChild() && jwtService.Config.KeyDir != "" && jwtService.Config.ActiveKid == "" {
+		if _, err := keySet.Rotate(jwtService.KeyRetention()); err != nil {
+			log.Fatalf("failed to generate jwt key: %v", err)
+		}
+	}
+
+	if _, err := keySet.Active(); err != nil {
+		log.Fatalf("no jwt signing key for algorithm %s", jwtService.Config.GetAlgorithm())
+	}
 }
 
 func GetJwtService() *JwtService {
@@ -41,33 +75,67 @@ func GetJwtService() *JwtService {
 	return jwtService
 }
 
-func (s *JwtService) CreateAccessToken(userId, role int) (string, error) {
-	var secretKey = s.Config.GetSecretKey()
+// KeyRetention lama key lama tetap diterima setelah dirotasi, yaitu umur token terpanjang yang ditandatangani
+func (s *JwtService) KeyRetention() time.Duration {
+	retention := s.Config.GetExpTimeAccessToken()
+	if pendingExp := config.AppConfig.TwoFactor.GetPendingExp(); pendingExp > retention {
+		retention = pendingExp
+	}
+	return retention + time.Minute
+}
+
+func (s *JwtService) sign(claims Claims) (string, error) {
+	if s.Keys == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.Config.GetSecretKey()))
+	}
+
+	key, err := s.Keys.Active()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.Kid
+
+	return token.SignedString(key.PrivateKey)
+}
+
+func (s *JwtService) CreateAccessToken(userId, role int, sessionId int64) (string, error) {
 	expAt := s.Config.GetExpTimeAccessToken()
 	claims := Claims{
 		UserId:    userId,
 		Role:      role,
-		TokenType: "accessToken",
+		TokenType: AccessTokenType,
+		SessionId: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    "blog_api",
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expAt)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return s.sign(claims)
+}
 
-	return token.SignedString([]byte(secretKey))
+// CreateMfaPendingToken token singkat setelah password benar, hanya bisa ditukar dengan kode 2FA.
+// ID (jti) dipakai sebagai key counter percobaan kode salah per token
+func (s *JwtService) CreateMfaPendingToken(userId, role int) (string, error) {
+	claims := Claims{
+		UserId:    userId,
+		Role:      role,
+		TokenType: MfaPendingTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        GenerateRandomString(24),
+			Issuer:    "blog_api",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.AppConfig.TwoFactor.GetPendingExp())),
+		},
+	}
+
+	return s.sign(claims)
 }
 
 func (s *JwtService) VerifyToken(tokenString string) (*Claims, error) {
-	var secretKey = s.Config.GetSecretKey()
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validasi signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -80,6 +148,33 @@ func (s *JwtService) VerifyToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey memilih key dari header kid, algoritma token harus sama dengan algoritma key
+// supaya public key tidak bisa dipakai sebagai secret HMAC
+func (s *JwtService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if s.Keys == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.Config.GetSecretKey()), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("token has no kid")
+	}
+
+	key, err := s.Keys.Find(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey, nil
+}
+
 func GetUserClaims(c *fiber.Ctx) (*Claims, error) {
 	value, ok := c.Locals("user").(*Claims)
 	if !ok || value == nil {
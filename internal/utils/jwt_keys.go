@@ -0,0 +1,544 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+)
+
+const (
+	jwtKeyFileExt     = ".pem"
+	jwtKidTimeLayout  = "20060102T150405Z"
+	jwtRSAKeyBits     = 2048
+	jwtKeyFileMode    = 0600
+	jwtKeyDirFileMode = 0700
+
+	// dengan prefork setiap child punya salinan key sendiri, key_dir dibaca ulang secara berkala
+	// supaya key hasil rotasi di process lain ikut dipakai
+	jwtKeyReloadInterval = time.Minute
+	// kid yang belum dikenal memicu baca ulang key_dir, dibatasi supaya token palsu tidak membuat disk sibuk
+	jwtUnknownKidReloadInterval = 10 * time.Second
+)
+
+var (
+	ErrJwtKeyNotFound      = errors.New("jwt signing key not found")
+	ErrNoActiveJwtKey      = errors.New("no active jwt signing key")
+	ErrJwtRotationDisabled = errors.New("jwt key rotation needs jwt.key_dir and an empty jwt.active_kid")
+)
+
+// JwtKey satu key penandatangan token, PrivateKey nil untuk key yang hanya dipakai verifikasi
+type JwtKey struct {
+	Kid        string
+	Algorithm  string
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	CreatedAt  time.Time
+	// FromDir key dari key_dir, hanya key ini yang dihapus otomatis setelah pensiun
+	FromDir bool
+}
+
+type JwtKeySet struct {
+	mu             sync.RWMutex
+	config         *config.JwtConfig
+	keys           map[string]*JwtKey
+	active         *JwtKey
+	loadedAt       time.Time
+	lastMissReload time.Time
+}
+
+// JSONWebKey public key dalam format JWK (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+func NewJwtKeySet(jwtConfig *config.JwtConfig) (*JwtKeySet, error) {
+	keySet := &JwtKeySet{
+		config: jwtConfig,
+		keys:   map[string]*JwtKey{},
+	}
+
+	if err := keySet.Reload(); err != nil {
+		return nil, err
+	}
+
+	return keySet, nil
+}
+
+// Reload membaca ulang key dari config dan key_dir
+func (ks *JwtKeySet) Reload() error {
+	keys := map[string]*JwtKey{}
+
+	for _, keyConfig := range ks.config.Keys {
+		key, err := loadConfiguredJwtKey(keyConfig)
+		if err != nil {
+			return err
+		}
+		keys[key.Kid] = key
+	}
+
+	if ks.config.KeyDir != "" {
+		dirKeys, err := loadJwtKeyDir(ks.config.KeyDir)
+		if err != nil {
+			return err
+		}
+		for _, key := range dirKeys {
+			keys[key.Kid] = key
+		}
+	}
+
+	active := ks.pickActive(keys)
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.active = active
+	ks.loadedAt = time.Now()
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// pickActive memakai jwt.active_kid kalau diisi, selain itu key terbaru yang punya private key dengan algoritma yang dikonfigurasi
+func (ks *JwtKeySet) pickActive(keys map[string]*JwtKey) *JwtKey {
+	algorithm := ks.config.GetAlgorithm()
+
+	if ks.config.ActiveKid != "" {
+		key, ok := keys[ks.config.ActiveKid]
+		if !ok || key.PrivateKey == nil || key.Algorithm != algorithm {
+			return nil
+		}
+		return key
+	}
+
+	var active *JwtKey
+	for _, key := range keys {
+		if key.PrivateKey == nil || key.Algorithm != algorithm {
+			continue
+		}
+		if active == nil || key.CreatedAt.After(active.CreatedAt) {
+			active = key
+		}
+	}
+
+	return active
+}
+
+func (ks *JwtKeySet) reloadIfStale() {
+	if ks.config.KeyDir == "" {
+		return
+	}
+
+	ks.mu.RLock()
+	stale := time.Since(ks.loadedAt) > jwtKeyReloadInterval
+	ks.mu.RUnlock()
+
+	if stale {
+		if err := ks.Reload(); err != nil {
+			Logger.Errorf("failed to reload jwt keys %v", err)
+		}
+	}
+}
+
+// Active key yang dipakai untuk menandatangani token baru
+func (ks *JwtKeySet) Active() (*JwtKey, error) {
+	ks.reloadIfStale()
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if ks.active == nil {
+		return nil, ErrNoActiveJwtKey
+	}
+
+	return ks.active, nil
+}
+
+func (ks *JwtKeySet) Find(kid string) (*JwtKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if ks.config.KeyDir == "" {
+		return nil, ErrJwtKeyNotFound
+	}
+
+	// kemungkinan key baru hasil rotasi di process lain
+	ks.mu.Lock()
+	shouldReload := time.Since(ks.lastMissReload) > jwtUnknownKidReloadInterval
+	if shouldReload {
+		ks.lastMissReload = time.Now()
+	}
+	ks.mu.Unlock()
+
+	if !shouldReload {
+		return nil, ErrJwtKeyNotFound
+	}
+
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if key, ok := ks.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, ErrJwtKeyNotFound
+}
+
+// Keys semua key yang masih dipakai untuk verifikasi, terbaru lebih dulu
+func (ks *JwtKeySet) Keys() []*JwtKey {
+	ks.reloadIfStale()
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make([]*JwtKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+
+	return keys
+}
+
+func (ks *JwtKeySet) IsActive(key *JwtKey) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.active != nil && ks.active.Kid == key.Kid
+}
+
+// Rotate membuat key baru di key_dir yang langsung dipakai untuk tanda tangan. Key lama tetap dipakai
+// verifikasi sampai retention lewat sejak dipensiunkan, yaitu sampai token terakhirnya kadaluarsa.
+func (ks *JwtKeySet) Rotate(retention time.Duration) (*JwtKey, error) {
+	if ks.config.KeyDir == "" || ks.config.ActiveKid != "" {
+		return nil, ErrJwtRotationDisabled
+	}
+
+	now := time.Now()
+	key, err := generateJwtKey(ks.config.GetAlgorithm(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeJwtKeyFile(ks.config.KeyDir, key); err != nil {
+		return nil, err
+	}
+
+	ks.pruneRetired(now, retention)
+
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// pruneRetired menghapus file key yang sudah pensiun lebih lama dari retention.
+// Key pensiun saat key berikutnya (yang lebih baru) dibuat.
+func (ks *JwtKeySet) pruneRetired(now time.Time, retention time.Duration) {
+	dirKeys, err := loadJwtKeyDir(ks.config.KeyDir)
+	if err != nil {
+		Logger.Errorf("failed to read jwt key dir %v", err)
+		return
+	}
+
+	sort.Slice(dirKeys, func(i, j int) bool {
+		return dirKeys[i].CreatedAt.Before(dirKeys[j].CreatedAt)
+	})
+
+	for i := 0; i < len(dirKeys)-1; i++ {
+		retiredAt := dirKeys[i+1].CreatedAt
+		if now.Sub(retiredAt) <= retention {
+			continue
+		}
+
+		path := filepath.Join(ks.config.KeyDir, dirKeys[i].Kid+jwtKeyFileExt)
+		if err := os.Remove(path); err != nil {
+			Logger.Errorf("failed to remove retired jwt key %s %v", dirKeys[i].Kid, err)
+		}
+	}
+}
+
+// JWKS public key semua key yang masih berlaku, untuk service lain yang memverifikasi token
+func (ks *JwtKeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0)}
+
+	for _, key := range ks.Keys() {
+		jwk := JSONWebKey{
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Algorithm,
+		}
+
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+func loadConfiguredJwtKey(keyConfig config.JwtKeyConfig) (*JwtKey, error) {
+	if keyConfig.Kid == "" {
+		return nil, errors.New("jwt key in config must have a kid")
+	}
+
+	key := &JwtKey{Kid: keyConfig.Kid}
+
+	switch {
+	case keyConfig.PrivateKeyFile != "":
+		data, err := os.ReadFile(keyConfig.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", keyConfig.Kid, err)
+		}
+		privateKey, err := parseJwtPrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key %s: %w", keyConfig.Kid, err)
+		}
+		key.PrivateKey = privateKey
+		key.PublicKey = privateKey.Public()
+	case keyConfig.PublicKeyFile != "":
+		data, err := os.ReadFile(keyConfig.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", keyConfig.Kid, err)
+		}
+		publicKey, err := parseJwtPublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key %s: %w", keyConfig.Kid, err)
+		}
+		key.PublicKey = publicKey
+	default:
+		return nil, fmt.Errorf("jwt key %s needs private_key_file or public_key_file", keyConfig.Kid)
+	}
+
+	algorithm, err := jwtKeyAlgorithm(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jwt key %s: %w", keyConfig.Kid, err)
+	}
+	key.Algorithm = algorithm
+
+	return key, nil
+}
+
+// loadJwtKeyDir membaca semua <kid>.pem di key_dir, folder dibuat kalau belum ada
+func loadJwtKeyDir(dir string) ([]*JwtKey, error) {
+	if err := os.MkdirAll(dir, jwtKeyDirFileMode); err != nil {
+		return nil, fmt.Errorf("failed to create jwt key dir: %w", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt key dir: %w", err)
+	}
+
+	keys := make([]*JwtKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), jwtKeyFileExt) {
+			continue
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), jwtKeyFileExt)
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", kid, err)
+		}
+
+		privateKey, err := parseJwtPrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key %s: %w", kid, err)
+		}
+
+		algorithm, err := jwtKeyAlgorithm(privateKey.Public())
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key %s: %w", kid, err)
+		}
+
+		// kid hasil rotasi diawali waktu pembuatan, key yang di-copy manual memakai waktu file
+		createdAt, err := time.Parse(jwtKidTimeLayout, strings.SplitN(kid, "-", 2)[0])
+		if err != nil {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read jwt key %s: %w", kid, err)
+			}
+			createdAt = info.ModTime()
+		}
+
+		keys = append(keys, &JwtKey{
+			Kid:        kid,
+			Algorithm:  algorithm,
+			PrivateKey: privateKey,
+			PublicKey:  privateKey.Public(),
+			CreatedAt:  createdAt,
+			FromDir:    true,
+		})
+	}
+
+	return keys, nil
+}
+
+func generateJwtKey(algorithm string, now time.Time) (*JwtKey, error) {
+	var privateKey crypto.Signer
+
+	switch algorithm {
+	case config.JwtAlgorithmRS256:
+		rsaKey, err := rsa.GenerateKey(rand.Reader, jwtRSAKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		privateKey = rsaKey
+	case config.JwtAlgorithmEdDSA:
+		_, edKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		privateKey = edKey
+	default:
+		return nil, fmt.Errorf("cannot generate key for jwt algorithm %s", algorithm)
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	createdAt := now.UTC().Truncate(time.Second)
+
+	return &JwtKey{
+		Kid:        createdAt.Format(jwtKidTimeLayout) + "-" + hex.EncodeToString(suffix),
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+		CreatedAt:  createdAt,
+		FromDir:    true,
+	}, nil
+}
+
+// writeJwtKeyFile menulis ke file sementara lalu rename, supaya process lain tidak membaca file setengah jadi
+func writeJwtKeyFile(dir string, key *JwtKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, jwtKeyDirFileMode); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(jwtKeyFileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := pem.Encode(tmp, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, key.Kid+jwtKeyFileExt))
+}
+
+func parseJwtPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("not a PEM file")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported private key format, use PKCS#8 or PKCS#1")
+}
+
+func parseJwtPublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("not a PEM file")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported public key format")
+}
+
+func jwtKeyAlgorithm(publicKey crypto.PublicKey) (string, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return config.JwtAlgorithmRS256, nil
+	case ed25519.PublicKey:
+		return config.JwtAlgorithmEdDSA, nil
+	default:
+		return "", errors.New("only RSA and Ed25519 keys are supported")
+	}
+}
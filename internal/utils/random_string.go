@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"math/big"
 )
 
 func GenerateRandomString(length int) string {
@@ -10,3 +11,19 @@ func GenerateRandomString(length int) string {
 	rand.Read(b)
 	return base64.URLEncoding.EncodeToString(b)[:length]
 }
+
+// GenerateNumericCode membuat kode angka acak (OTP) dengan panjang length
+func GenerateNumericCode(length int) (string, error) {
+	digits := make([]byte, length)
+	max := big.NewInt(10)
+
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+
+	return string(digits), nil
+}
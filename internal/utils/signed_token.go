@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/config"
+)
+
+var ErrInvalidSignedToken = errors.New("invalid or expired token")
+
+// CreateSignedToken membuat token untuk link di email (konfirmasi, reset, dll).
+// purpose ikut ditandatangani supaya token untuk satu keperluan tidak bisa dipakai di keperluan lain.
+func CreateSignedToken(purpose string, subject string, ttl time.Duration) string {
+	expiresAt := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	payload := subject + "|" + expiresAt
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	signature := base64.RawURLEncoding.EncodeToString(signTokenPayload(purpose, payload))
+
+	return encodedPayload + "." + signature
+}
+
+// VerifySignedToken mengembalikan subject kalau signature cocok dan token belum kadaluarsa
+func VerifySignedToken(purpose string, token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", ErrInvalidSignedToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalidSignedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidSignedToken
+	}
+
+	payload := string(payloadBytes)
+	if !hmac.Equal(signature, signTokenPayload(purpose, payload)) {
+		return "", ErrInvalidSignedToken
+	}
+
+	separator := strings.LastIndex(payload, "|")
+	if separator < 0 {
+		return "", ErrInvalidSignedToken
+	}
+
+	expiresAt, err := strconv.ParseInt(payload[separator+1:], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", ErrInvalidSignedToken
+	}
+
+	return payload[:separator], nil
+}
+
+func signTokenPayload(purpose string, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(config.AppConfig.JWT.GetSecretKey()))
+	mac.Write([]byte(purpose + ":" + payload))
+	return mac.Sum(nil)
+}
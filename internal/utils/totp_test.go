@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// secret ASCII "12345678901234567890" dari appendix RFC 4226 dan RFC 6238
+var rfcSecret = []byte("12345678901234567890")
+
+func TestHotpRFC4226Vectors(t *testing.T) {
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, want := range expected {
+		if got := hotp(rfcSecret, int64(counter)); got != want {
+			t.Errorf("hotp(counter=%d) = %s, want %s", counter, got, want)
+		}
+	}
+}
+
+func TestValidateTotpRFC6238Vectors(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfcSecret)
+
+	// nilai SHA1 di RFC 6238 memakai 8 digit, di sini diambil 6 digit terakhir
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tc := range cases {
+		step, ok := ValidateTotp(secret, tc.code, time.Unix(tc.unix, 0))
+		if !ok {
+			t.Errorf("ValidateTotp(t=%d, %s) rejected a valid code", tc.unix, tc.code)
+			continue
+		}
+		if want := tc.unix / TotpPeriod; step != want {
+			t.Errorf("ValidateTotp(t=%d) step = %d, want %d", tc.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTotpSkew(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfcSecret)
+	// kode untuk t=59 ada di step 1
+	code := "287082"
+
+	for _, tc := range []struct {
+		unix int64
+		ok   bool
+	}{
+		{29, true},  // step 0, satu step lebih awal
+		{60, true},  // step 2, satu step terlambat
+		{90, false}, // step 3, di luar toleransi
+	} {
+		step, ok := ValidateTotp(secret, code, time.Unix(tc.unix, 0))
+		if ok != tc.ok {
+			t.Errorf("ValidateTotp(t=%d) ok = %v, want %v", tc.unix, ok, tc.ok)
+		}
+		if ok && step != 1 {
+			t.Errorf("ValidateTotp(t=%d) step = %d, want 1", tc.unix, step)
+		}
+	}
+}
+
+func TestValidateTotpRejectsMalformedInput(t *testing.T) {
+	secret := totpEncoding.EncodeToString(rfcSecret)
+	now := time.Unix(59, 0)
+
+	if _, ok := ValidateTotp(strings.ToLower(secret), "287082", now); !ok {
+		t.Error("lowercase secret should be accepted")
+	}
+	if _, ok := ValidateTotp(secret, "94287082", now); ok {
+		t.Error("8 digit code should be rejected")
+	}
+	if _, ok := ValidateTotp("not-base32!", "287082", now); ok {
+		t.Error("invalid secret should be rejected")
+	}
+}
@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	accountBatchSize = 20
+	// export yang processing lebih lama dari ini dianggap macet dan diambil ulang
+	exportStaleAfter = 30 * time.Minute
+)
+
+// AccountWorker mengerjakan export data pribadi, menghapus file export kadaluarsa dan akun yang masa tenggangnya lewat
+type AccountWorker struct {
+	AccountRepository    repository.AccountRepository
+	DataExportRepository repository.DataExportRepository
+	AccountService       services.AccountService
+	Interval             time.Duration
+}
+
+func NewAccountWorker(accountRepository repository.AccountRepository, dataExportRepository repository.DataExportRepository, accountService services.AccountService, interval time.Duration) *AccountWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &AccountWorker{
+		AccountRepository:    accountRepository,
+		DataExportRepository: dataExportRepository,
+		AccountService:       accountService,
+		Interval:             interval,
+	}
+}
+
+// Start menjalankan worker di goroutine sendiri sampai ctx dibatalkan
+func (w *AccountWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		w.Run()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Run()
+			}
+		}
+	}()
+}
+
+func (w *AccountWorker) Run() {
+	w.ProcessExports()
+	w.CleanupExports()
+	w.DeleteDueAccounts()
+}
+
+func (w *AccountWorker) ProcessExports() {
+	staleBefore := time.Now().Add(-exportStaleAfter)
+
+	exports, err := w.DataExportRepository.FindPending(staleBefore, accountBatchSize)
+	if err != nil {
+		utils.Logger.Errorf("failed to find pending data exports %v", err)
+		return
+	}
+
+	for _, export := range exports {
+		claimed, err := w.DataExportRepository.Claim(export.ID, staleBefore)
+		if err != nil {
+			utils.Logger.Errorf("failed to claim data export %d %v", export.ID, err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		if err := w.AccountService.BuildExport(export); err != nil {
+			utils.Logger.Errorf("failed to build data export %d %v", export.ID, err)
+			continue
+		}
+
+		utils.Logger.WithFields(logrus.Fields{
+			"exportId": export.ID,
+			"userId":   export.UserID,
+		}).Info("data export ready")
+	}
+}
+
+func (w *AccountWorker) CleanupExports() {
+	if err := w.AccountService.CleanupExpiredExports(time.Now(), accountBatchSize); err != nil {
+		utils.Logger.Errorf("failed to cleanup expired data exports %v", err)
+	}
+}
+
+func (w *AccountWorker) DeleteDueAccounts() {
+	users, err := w.AccountRepository.FindDueDeletions(time.Now(), accountBatchSize)
+	if err != nil {
+		utils.Logger.Errorf("failed to find accounts due for deletion %v", err)
+		return
+	}
+
+	for _, user := range users {
+		if err := w.AccountService.DeleteAccount(user); err != nil {
+			utils.Logger.Errorf("failed to delete account %d %v", user.ID, err)
+			continue
+		}
+
+		utils.Logger.WithFields(logrus.Fields{
+			"userId": user.ID,
+		}).Info("account deleted")
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// FeedFanout menyebar post yang baru terbit ke inbox feed follower (feed.strategy write)
+type FeedFanout struct {
+	FeedService services.FeedService
+	Interval    time.Duration
+}
+
+func NewFeedFanout(feedService services.FeedService, interval time.Duration) *FeedFanout {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &FeedFanout{
+		FeedService: feedService,
+		Interval:    interval,
+	}
+}
+
+// Start menjalankan worker di goroutine sendiri sampai ctx dibatalkan
+func (w *FeedFanout) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		w.Run()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.Run()
+			}
+		}
+	}()
+}
+
+func (w *FeedFanout) Run() {
+	count, err := w.FeedService.FanoutPending()
+	if err != nil {
+		utils.Logger.Errorf("failed to fan out posts to feed %v", err)
+	}
+	if count > 0 {
+		utils.Logger.WithFields(logrus.Fields{
+			"posts": count,
+		}).Info("posts fanned out to feed")
+	}
+
+	removed, err := w.FeedService.CleanupInbox()
+	if err != nil {
+		utils.Logger.Errorf("failed to cleanup feed inbox %v", err)
+		return
+	}
+	if removed > 0 {
+		utils.Logger.WithFields(logrus.Fields{
+			"items": removed,
+		}).Info("old feed items removed")
+	}
+}
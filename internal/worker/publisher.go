@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/enum"
+	"github.com/MrBista/blog-api/internal/models"
+	"github.com/MrBista/blog-api/internal/repository"
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const publishBatchSize = 50
+
+// PostPublisher menerbitkan post terjadwal yang scheduled_at-nya sudah lewat
+type PostPublisher struct {
+	PostRepository            repository.PostRepository
+	PostStatusEventRepository repository.PostStatusEventRepository
+	SearchService             services.SearchService
+	Interval                  time.Duration
+}
+
+func NewPostPublisher(postRepository repository.PostRepository, postStatusEventRepository repository.PostStatusEventRepository, searchService services.SearchService, interval time.Duration) *PostPublisher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &PostPublisher{
+		PostRepository:            postRepository,
+		PostStatusEventRepository: postStatusEventRepository,
+		SearchService:             searchService,
+		Interval:                  interval,
+	}
+}
+
+// Start menjalankan publisher di goroutine sendiri sampai ctx dibatalkan
+func (p *PostPublisher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.Interval)
+		defer ticker.Stop()
+
+		p.PublishDuePosts()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.PublishDuePosts()
+			}
+		}
+	}()
+}
+
+func (p *PostPublisher) PublishDuePosts() {
+	now := time.Now()
+
+	posts, err := p.PostRepository.FindDueScheduledPosts(now, publishBatchSize)
+	if err != nil {
+		utils.Logger.Errorf("failed to find scheduled posts %v", err)
+		return
+	}
+
+	for _, post := range posts {
+		published, err := p.PostRepository.PublishScheduledPost(post.ID, now)
+		if err != nil {
+			utils.Logger.Errorf("failed to publish scheduled post %d %v", post.ID, err)
+			continue
+		}
+
+		if !published {
+			continue
+		}
+
+		// actor kosong menandakan transisi dilakukan oleh sistem
+		event := models.PostStatusEvent{
+			PostID:     post.ID,
+			FromStatus: post.Status,
+			ToStatus:   uint8(enum.PostStatusPublished),
+		}
+		if err := p.PostStatusEventRepository.Create(&event); err != nil {
+			utils.Logger.Errorf("failed to record publish event for post %d %v", post.ID, err)
+		}
+
+		p.SearchService.SyncPost(post.ID)
+
+		utils.Logger.WithFields(logrus.Fields{
+			"postId": post.ID,
+			"slug":   post.Slug,
+		}).Info("scheduled post published")
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/MrBista/blog-api/internal/services"
+	"github.com/MrBista/blog-api/internal/utils"
+)
+
+// SearchReconciler menyamakan index pencarian dengan database secara berkala, memperbaiki
+// perubahan yang gagal di-sync (misal index error atau process mati sebelum SyncPost)
+type SearchReconciler struct {
+	SearchService services.SearchService
+	Interval      time.Duration
+}
+
+func NewSearchReconciler(searchService services.SearchService, interval time.Duration) *SearchReconciler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &SearchReconciler{
+		SearchService: searchService,
+		Interval:      interval,
+	}
+}
+
+// Start menjalankan rekonsiliasi pertama langsung lalu berkala sampai ctx dibatalkan
+func (r *SearchReconciler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		r.Reconcile()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.Reconcile()
+			}
+		}
+	}()
+}
+
+func (r *SearchReconciler) Reconcile() {
+	fixed, err := r.SearchService.Reconcile()
+	if err != nil {
+		utils.Logger.Errorf("failed to reconcile search index %v", err)
+		return
+	}
+
+	if fixed > 0 {
+		utils.Logger.Infof("search index reconciled, %d posts reindexed or removed", fixed)
+	}
+}